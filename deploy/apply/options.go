@@ -23,6 +23,9 @@ type Options struct {
 	// Toggle whether existing resources will attempt to be imported.
 	// Used when migrating an existing project. Only needs to be done once.
 	ImportExisting bool
+	// Toggle whether existing resources are imported through import blocks in the generated configs
+	// instead of terraform import commands. Requires Terraform 1.5 or later and ImportExisting to be set.
+	ImportBlocks bool
 	// Normalized directory path to store generated Terraform configs.
 	TerraformConfigsPath string
 	// Extra flags to pass to terraform apply command.
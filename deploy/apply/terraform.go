@@ -34,6 +34,10 @@ import (
 // (e.g. forseti project wants to store its audit logs in remote audit project while remote audit project wants to be monitored by the forseti project).
 // Then, all data hosting projects are deployed from beginning till end so one data project doesn't leave other data projects in a half deployed state.
 func Terraform(conf *config.Config, projectIDs []string, opts *Options, rn runner.Runner) error {
+	if opts.ImportBlocks && !opts.ImportExisting {
+		return errors.New("import blocks can only be used when existing resources are imported")
+	}
+
 	idSet := make(map[string]bool)
 	for _, p := range projectIDs {
 		idSet[p] = true
@@ -344,20 +348,26 @@ func resources(project *config.Project, opts *Options, workDir string, rn runner
 	rs := project.TerraformResources()
 	tfConf := terraform.NewConfig()
 
-	tfConf.Providers = append(tfConf.Providers,
-		&terraform.Provider{
+	providers := []*terraform.Provider{
+		{
 			Name: "google",
 
 			// Needed to work around issues like https://github.com/terraform-providers/terraform-provider-google/issues/4460.
 			// Also used if a resource does not explicitly set the project field.
-			Properties: map[string]interface{}{"project": project.ID},
+			Project: project.ID,
 		},
 		// Beta provider needed for some resources such as healthcare resources.
-		&terraform.Provider{
-			Name:       "google-beta",
-			Properties: map[string]interface{}{"project": project.ID},
+		{
+			Name:    "google-beta",
+			Project: project.ID,
 		},
-	)
+	}
+	for _, p := range providers {
+		if err := p.Init(); err != nil {
+			return fmt.Errorf("failed to init provider %q: %v", p.Name, err)
+		}
+	}
+	tfConf.Providers = append(tfConf.Providers, providers...)
 
 	tfConf.Terraform.Backend = &terraform.Backend{
 		Bucket: project.DevopsConfig.StateBucket.Name,
@@ -377,7 +387,11 @@ func resources(project *config.Project, opts *Options, workDir string, rn runner
 		return err
 	}
 	tfOpts := &terraform.Options{ApplyFlags: opts.TerraformApplyFlags, CustomConfig: project.TerraformDeployments.Resources.Config}
-	if opts.ImportExisting {
+	if opts.ImportExisting && opts.ImportBlocks {
+		if err := addImportBlocks(tfConf, rn, rs...); err != nil {
+			return err
+		}
+	} else if opts.ImportExisting {
 		if err := addImports(tfOpts, rn, rs...); err != nil {
 			return err
 		}
@@ -395,13 +409,19 @@ type dependerTF interface {
 }
 
 // addResources adds the given resources to the given terraform config.
+// Data resources are added as data sources.
 func addResources(config *terraform.Config, resources ...tfconfig.Resource) error {
 	for _, r := range resources {
-		config.Resources = append(config.Resources, &terraform.Resource{
+		tr := &terraform.Resource{
 			Name:       r.ID(),
 			Type:       r.ResourceType(),
 			Properties: r,
-		})
+		}
+		if _, ok := r.(tfconfig.DataResource); ok {
+			config.Data = append(config.Data, tr)
+		} else {
+			config.Resources = append(config.Resources, tr)
+		}
 
 		if d, ok := r.(dependerTF); ok {
 			if err := addResources(config, d.DependentResources()...); err != nil {
@@ -415,17 +435,41 @@ func addResources(config *terraform.Config, resources ...tfconfig.Resource) erro
 // addImports updates the terraform options with the given resources' import IDs, so the resources are imported to the terraform state if they already exist.
 // The import ID for a resource can be found in terraform's documentation for the resource.
 func addImports(opts *terraform.Options, rn runner.Runner, resources ...tfconfig.Resource) error {
-	type importer interface {
-		ImportID(rn runner.Runner) (string, error)
+	imports, err := getImports(rn, resources...)
+	if err != nil {
+		return err
+	}
+	opts.Imports = append(opts.Imports, imports...)
+	return nil
+}
+
+// addImportBlocks adds import blocks for the given resources to the given terraform config.
+// Unlike addImports, the imports are run as part of the terraform plan, which requires Terraform 1.5 or later.
+func addImportBlocks(config *terraform.Config, rn runner.Runner, resources ...tfconfig.Resource) error {
+	imports, err := getImports(rn, resources...)
+	if err != nil {
+		return err
+	}
+	for i := range imports {
+		config.Imports = append(config.Imports, &imports[i])
+	}
+	if len(config.Imports) > 0 && config.Terraform != nil {
+		config.Terraform.RequireVersion("1.5.0")
 	}
+	return nil
+}
+
+// getImports returns the imports of the given resources and their dependent resources.
+func getImports(rn runner.Runner, resources ...tfconfig.Resource) ([]terraform.Import, error) {
+	var imports []terraform.Import
 	for _, r := range resources {
-		if i, ok := r.(importer); ok {
+		if i, ok := r.(tfconfig.Importer); ok {
 			id, err := i.ImportID(rn)
 			if err != nil {
-				return fmt.Errorf("failed to get import ID for %q %q: %v", r.ResourceType(), r.ID(), err)
+				return nil, fmt.Errorf("failed to get import ID for %q %q: %v", r.ResourceType(), r.ID(), err)
 			}
 			if id != "" {
-				opts.Imports = append(opts.Imports, terraform.Import{
+				imports = append(imports, terraform.Import{
 					Address: fmt.Sprintf("%s.%s", r.ResourceType(), r.ID()),
 					ID:      id,
 				})
@@ -433,10 +477,12 @@ func addImports(opts *terraform.Options, rn runner.Runner, resources ...tfconfig
 		}
 
 		if d, ok := r.(dependerTF); ok {
-			if err := addImports(opts, rn, d.DependentResources()...); err != nil {
-				return err
+			deps, err := getImports(rn, d.DependentResources()...)
+			if err != nil {
+				return nil, err
 			}
+			imports = append(imports, deps...)
 		}
 	}
-	return nil
+	return imports, nil
 }
@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	"github.com/GoogleCloudPlatform/healthcare/deploy/config"
+	"github.com/GoogleCloudPlatform/healthcare/deploy/config/tfconfig"
 	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
 	"github.com/GoogleCloudPlatform/healthcare/deploy/terraform"
 	"github.com/GoogleCloudPlatform/healthcare/deploy/testconf"
@@ -325,7 +326,11 @@ compute_instances:
         initialize_params:
           image: debian-cloud/debian-9
       network_interface:
-        network: default`,
+        network: default
+      shielded_instance_config:
+        enable_secure_boot: true
+        enable_vtpm: true
+        enable_integrity_monitoring: true`,
 			wantImports: []terraform.Import{{
 				Address: "google_compute_instance.foo-instance",
 				ID:      "my-project/us-central1-a/foo-instance",
@@ -720,6 +725,7 @@ spanner_instances:
       project: my-project
       config: regional-us-central1
       display_name: example spanner instance
+      num_nodes: 1
 - google_spanner_database:
     example-database:
       name: example-database
@@ -727,6 +733,7 @@ spanner_instances:
       instance: ${google_spanner_instance.example-instance.name}
       ddl:
       - CREATE TABLE t1 (t1 INT64 NOT NULL,) PRIMARY KEY(t1)
+      deletion_protection: true
 - google_spanner_database_iam_member:
     example-instance_example-database:
       for_each:
@@ -1059,6 +1066,84 @@ module:
 	}
 }
 
+func TestAddResourcesData(t *testing.T) {
+	p := &tfconfig.DataGoogleProject{ProjectID: "other-project"}
+	sa := &tfconfig.ServiceAccount{AccountID: "foo-account", Project: "my-project"}
+	tfConf := terraform.NewConfig()
+	if err := addResources(tfConf, p, sa); err != nil {
+		t.Fatalf("addResources = %v", err)
+	}
+
+	got := makeApplyCall(t, tfConf, nil).Config
+	want := unmarshal(t, `
+terraform:
+  required_version: '>= 0.12.0'
+data:
+- google_project:
+    other-project:
+      project_id: other-project
+resource:
+- google_service_account:
+    foo-account:
+      account_id: foo-account
+      project: my-project
+      display_name: ""`)
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("terraform configs differ (-got, +want):\n%v", diff)
+	}
+}
+
+func TestAddImportBlocks(t *testing.T) {
+	sa := &tfconfig.ServiceAccount{AccountID: "foo-account", Project: "my-project"}
+	p := &tfconfig.DataGoogleProject{ProjectID: "my-project"}
+	tfConf := terraform.NewConfig()
+	if err := addResources(tfConf, p, sa); err != nil {
+		t.Fatalf("addResources = %v", err)
+	}
+	if err := addImportBlocks(tfConf, &tfTestRunner{}, p, sa); err != nil {
+		t.Fatalf("addImportBlocks = %v", err)
+	}
+
+	got := makeApplyCall(t, tfConf, nil).Config
+	want := unmarshal(t, `
+terraform:
+  required_version: '>= 1.5.0'
+data:
+- google_project:
+    my-project:
+      project_id: my-project
+resource:
+- google_service_account:
+    foo-account:
+      account_id: foo-account
+      project: my-project
+      display_name: ""
+import:
+- to: google_service_account.foo-account
+  id: projects/my-project/serviceAccounts/foo-account@my-project.iam.gserviceaccount.com`)
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("terraform configs differ (-got, +want):\n%v", diff)
+	}
+}
+
+func TestAddImportBlocksKeepsStricterRequiredVersion(t *testing.T) {
+	sa := &tfconfig.ServiceAccount{AccountID: "foo-account", Project: "my-project"}
+	tfConf := terraform.NewConfig()
+	tfConf.Terraform.RequiredVersion = ">= 1.6.0"
+	if err := addImportBlocks(tfConf, &tfTestRunner{}, sa); err != nil {
+		t.Fatalf("addImportBlocks = %v", err)
+	}
+	if got, want := tfConf.Terraform.RequiredVersion, ">= 1.6.0"; got != want {
+		t.Errorf("RequiredVersion = %q, want %q", got, want)
+	}
+}
+
+func TestTerraformImportBlocksRequireImportExisting(t *testing.T) {
+	if err := Terraform(&config.Config{}, nil, &Options{ImportBlocks: true}, &tfTestRunner{}); err == nil {
+		t.Error("Terraform = nil, want error")
+	}
+}
+
 func makeApplyCall(t *testing.T, config *terraform.Config, opts *terraform.Options) applyCall {
 	b, err := json.Marshal(config)
 	if err != nil {
@@ -45,6 +45,7 @@ var (
 	dryRun              = flag.Bool("dry_run", false, "Whether or not to run DPT in the dry run mode. If true, prints the commands that will run without executing.")
 	enableTerraform     = flag.Bool("enable_terraform", true, "Whether terraform is preferred over deployment manager.")
 	importExisting      = flag.Bool("terraform_import_existing", false, "TERRAFORM ONLY. Whether applicable Terraform resources will try to be imported (used for migrating an existing installation).")
+	importBlocks        = flag.Bool("terraform_import_blocks", false, "TERRAFORM ONLY. Whether resources are imported through import blocks instead of import commands when --terraform_import_existing is set. Requires Terraform 1.5 or later.")
 	terraformConfigsDir = flag.String("terraform_configs_dir", "", "TERRAFORM ONLY. Directory path to store generated Terraform configs. The configs are discarded if not specified.")
 	terraformApplyFlags = flag.String("terraform_apply_flags", "", "TERRAFORM ONLY. Extra option flags to pass to apply command.")
 	projects            arrayFlags
@@ -135,7 +136,7 @@ func applyConfigs() (err error) {
 		return fmt.Errorf("failed to mkdir %q: %v", *terraformConfigsDir, err)
 	}
 
-	opts := &apply.Options{DryRun: *dryRun, ImportExisting: *importExisting, TerraformConfigsPath: *terraformConfigsDir}
+	opts := &apply.Options{DryRun: *dryRun, ImportExisting: *importExisting, ImportBlocks: *importBlocks, TerraformConfigsPath: *terraformConfigsDir}
 	if *terraformApplyFlags != "" {
 		var err error
 		opts.TerraformApplyFlags, err = shlex.Split(*terraformApplyFlags)
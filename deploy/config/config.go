@@ -84,6 +84,9 @@ type Project struct {
 	ViolationExceptions   map[string][]string `json:"violation_exceptions"`
 	StackdriverAlertEmail string              `json:"stackdriver_alert_email"`
 
+	// CommonLabels are merged into the labels of all terraform resources that support labels.
+	CommonLabels map[string]string `json:"common_labels"`
+
 	Resources struct {
 		// Deployment manager resources
 		BQDatasets      []*BigqueryDataset `json:"bq_datasets"`
@@ -112,6 +115,8 @@ type Project struct {
 	ComputeFirewalls     []*tfconfig.ComputeFirewall               `json:"compute_firewalls"`
 	ComputeImages        []*tfconfig.ComputeImage                  `json:"compute_images"`
 	ComputeInstances     []*tfconfig.ComputeInstance               `json:"compute_instances"`
+	ComputeNetworks      []*tfconfig.ComputeNetwork                `json:"compute_networks"`
+	ComputeSubnetworks   []*tfconfig.ComputeSubnetwork             `json:"compute_subnetworks"`
 	DataFusionInstances  []*tfconfig.DataFusionInstance            `json:"data_fusion_instances"`
 	HealthcareDatasets   []*tfconfig.HealthcareDataset             `json:"healthcare_datasets"`
 	IAMCustomRoles       []*tfconfig.ProjectIAMCustomRole          `json:"project_iam_custom_roles"`
@@ -185,7 +190,7 @@ func (c *Config) Init(genFields *AllGeneratedFields) error {
 		}
 		p.GeneratedFields = c.AllGeneratedFields.Projects[p.ID]
 		if err := p.Init(c.ProjectForDevops(p), c.ProjectForAuditLogs(p)); err != nil {
-			return fmt.Errorf("failed to init project %q: %v", p.ID, err)
+			return fmt.Errorf("failed to init project %q: %w", p.ID, err)
 		}
 	}
 	return nil
@@ -43,12 +43,28 @@ func (p *Project) initTerraform(auditProject *Project) error {
 		}
 	}
 
-	for _, r := range p.TerraformResources() {
-		if err := r.Init(p.ID); err != nil {
-			return fmt.Errorf("failed to init %q (%v): %v", r.ResourceType(), r, err)
-		}
+	rs := p.TerraformResources()
+	tfconfig.MergeCommonLabels(rs, p.CommonLabels)
+	// Resources are only validated once all of them are initialized, as validation assumes initialized resources.
+	errs := new(tfconfig.MultiError)
+	for _, r := range rs {
+		errs.Add(r.ResourceType(), r.ID(), r.Init(p.ID))
 	}
-	return nil
+	if len(errs.Errors) > 0 {
+		return fmt.Errorf("failed to init resources (%d errors):\n%w", len(errs.Errors), errs)
+	}
+	return validateTerraformResources(rs)
+}
+
+// validateTerraformResources validates the initialized resources.
+// Errors are collected across all resources so they can be reported together.
+func validateTerraformResources(rs []tfconfig.Resource) error {
+	err := tfconfig.ValidateResources(rs)
+	var errs *tfconfig.MultiError
+	if errors.As(err, &errs) {
+		return fmt.Errorf("failed to validate resources (%d errors):\n%w", len(errs.Errors), errs)
+	}
+	return err
 }
 
 func (p *Project) initTerraformAuditResources(auditProject *Project) error {
@@ -57,6 +73,7 @@ func (p *Project) initTerraformAuditResources(auditProject *Project) error {
 		return errors.New("audit.logs_bigquery_dataset must be set")
 	}
 
+	tfconfig.MergeCommonLabels([]tfconfig.Resource{d}, p.CommonLabels)
 	if err := d.Init(auditProject.ID); err != nil {
 		return fmt.Errorf("failed to init logs bq dataset: %v", err)
 	}
@@ -67,10 +84,9 @@ func (p *Project) initTerraformAuditResources(auditProject *Project) error {
 	)
 
 	p.BQLogSinkTF = &tfconfig.LoggingSink{
-		Name:                 "audit-logs-to-bigquery",
-		Destination:          fmt.Sprintf("bigquery.googleapis.com/projects/%s/datasets/%s", auditProject.ID, d.DatasetID),
-		Filter:               `logName:"logs/cloudaudit.googleapis.com"`,
-		UniqueWriterIdentity: true,
+		Name:        "audit-logs-to-bigquery",
+		Destination: fmt.Sprintf("bigquery.googleapis.com/projects/%s/datasets/%s", auditProject.ID, d.DatasetID),
+		Filter:      `logName:"logs/cloudaudit.googleapis.com"`,
 	}
 	if err := p.BQLogSinkTF.Init(p.ID); err != nil {
 		return fmt.Errorf("failed to init bigquery log sink: %v", err)
@@ -81,6 +97,7 @@ func (p *Project) initTerraformAuditResources(auditProject *Project) error {
 		return nil
 	}
 
+	tfconfig.MergeCommonLabels([]tfconfig.Resource{b}, p.CommonLabels)
 	if err := b.Init(auditProject.ID); err != nil {
 		return fmt.Errorf("failed to init logs gcs bucket: %v", err)
 	}
@@ -126,6 +143,9 @@ func (p *Project) initServices() error {
 	if err := p.Services.Init(p.ID); err != nil {
 		return fmt.Errorf("failed to init services: %v", err)
 	}
+	if err := p.Services.Validate(); err != nil {
+		return fmt.Errorf("failed to validate services: %v", err)
+	}
 	return nil
 }
 
@@ -283,6 +303,12 @@ func (p *Project) TerraformResources() []tfconfig.Resource {
 	for _, r := range p.ComputeInstances {
 		rs = append(rs, r)
 	}
+	for _, r := range p.ComputeNetworks {
+		rs = append(rs, r)
+	}
+	for _, r := range p.ComputeSubnetworks {
+		rs = append(rs, r)
+	}
 	for _, r := range p.DataFusionInstances {
 		rs = append(rs, r)
 	}
@@ -0,0 +1,99 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/healthcare/deploy/config"
+	"github.com/GoogleCloudPlatform/healthcare/deploy/config/tfconfig"
+	"github.com/GoogleCloudPlatform/healthcare/deploy/testconf"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestInitTerraformValidationErrors(t *testing.T) {
+	config.EnableTerraform = true
+	conf := testconf.ConfigBeforeInit(t, &testconf.ConfigData{`
+service_accounts:
+- account_id: foo-account
+  display_name: ` + strings.Repeat("a", 101) + `
+- account_id: bar-account
+  description: ` + strings.Repeat("b", 257) + `
+- account_id: baz-account
+  display_name: Baz Account`})
+
+	err := conf.Init(new(config.AllGeneratedFields))
+	if err == nil {
+		t.Fatal("conf.Init = nil, want error")
+	}
+	for _, want := range []string{"failed to validate resources (2 errors)", "foo-account", "display_name", "bar-account", "description"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("conf.Init = %v, want error containing %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "baz-account") {
+		t.Errorf("conf.Init = %v, want no error for valid service account baz-account", err)
+	}
+}
+
+func TestInitTerraformInitErrors(t *testing.T) {
+	config.EnableTerraform = true
+	conf := testconf.ConfigBeforeInit(t, &testconf.ConfigData{`
+service_accounts:
+- account_id: foo_account
+- account_id: bar
+- account_id: baz-account`})
+
+	err := conf.Init(new(config.AllGeneratedFields))
+	if err == nil {
+		t.Fatal("conf.Init = nil, want error")
+	}
+	for _, want := range []string{"failed to init resources (2 errors)", "google_service_account.foo_account", "google_service_account.bar"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("conf.Init = %v, want error containing %q", err, want)
+		}
+	}
+	var merr *tfconfig.MultiError
+	if !errors.As(err, &merr) || len(merr.Errors) != 2 {
+		t.Errorf("errors.As(%v, %T) = false or wrong number of errors, want 2 aggregated errors", err, merr)
+	}
+}
+
+func TestInitTerraformCommonLabels(t *testing.T) {
+	config.EnableTerraform = true
+	_, p := testconf.ConfigAndProject(t, &testconf.ConfigData{`
+common_labels:
+  data-classification: phi
+  environment: prod
+storage_buckets:
+- name: foo-bucket
+  location: US
+  labels:
+    environment: dev`})
+
+	if len(p.StorageBuckets) != 1 {
+		t.Fatalf("len(p.StorageBuckets) = %d, want 1", len(p.StorageBuckets))
+	}
+	want := map[string]string{"data-classification": "phi", "environment": "dev"}
+	if diff := cmp.Diff(p.StorageBuckets[0].Labels, want); diff != "" {
+		t.Errorf("bucket labels differ (-got +want):\n%v", diff)
+	}
+	want = map[string]string{"data-classification": "phi", "environment": "prod"}
+	if diff := cmp.Diff(p.Audit.LogsBigqueryDataset.Labels, want); diff != "" {
+		t.Errorf("audit dataset labels differ (-got +want):\n%v", diff)
+	}
+}
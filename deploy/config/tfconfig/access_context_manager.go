@@ -0,0 +1,190 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
+)
+
+// AccessContextManagerServicePerimeter represents a Terraform VPC service controls perimeter.
+type AccessContextManagerServicePerimeter struct {
+	// Name is of the form accessPolicies/{policy_id}/servicePerimeters/{short_name}.
+	Name     string `json:"name"`
+	Provider string `json:"provider,omitempty"`
+
+	// Parent is the access policy of the perimeter, of the form accessPolicies/{policy_id}.
+	Parent        string                  `json:"parent"`
+	Title         string                  `json:"title"`
+	PerimeterType string                  `json:"perimeter_type,omitempty"`
+	Status        *ServicePerimeterStatus `json:"status,omitempty"`
+}
+
+// ServicePerimeterStatus represents the restrictions of a service perimeter.
+type ServicePerimeterStatus struct {
+	// Resources are the projects in the perimeter, of the form projects/{project_number}.
+	Resources             []string               `json:"resources,omitempty"`
+	AccessLevels          []string               `json:"access_levels,omitempty"`
+	RestrictedServices    []string               `json:"restricted_services,omitempty"`
+	VPCAccessibleServices *VPCAccessibleServices `json:"vpc_accessible_services,omitempty"`
+}
+
+// VPCAccessibleServices restricts the services accessible from networks within the perimeter.
+type VPCAccessibleServices struct {
+	EnableRestriction bool     `json:"enable_restriction"`
+	AllowedServices   []string `json:"allowed_services,omitempty"`
+}
+
+// perimeterTypes are the valid types of a service perimeter.
+var perimeterTypes = map[string]bool{
+	"PERIMETER_TYPE_REGULAR": true,
+	"PERIMETER_TYPE_BRIDGE":  true,
+}
+
+// Init initializes the resource.
+// Perimeters belong to an organization's access policy so the project ID is unused.
+func (p *AccessContextManagerServicePerimeter) Init(string) error {
+	if p.Name == "" {
+		return errors.New("name must be set")
+	}
+	if p.Parent == "" {
+		return errors.New("parent must be set")
+	}
+	if p.Title == "" {
+		return errors.New("title must be set")
+	}
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (p *AccessContextManagerServicePerimeter) Validate() error {
+	if p.PerimeterType != "" && !perimeterTypes[p.PerimeterType] {
+		return fmt.Errorf("invalid perimeter_type %q for perimeter %q: must be one of PERIMETER_TYPE_REGULAR or PERIMETER_TYPE_BRIDGE", p.PerimeterType, p.Title)
+	}
+	if p.Status == nil {
+		return nil
+	}
+	for _, s := range p.Status.RestrictedServices {
+		if !serviceRE.MatchString(s) {
+			return fmt.Errorf("invalid restricted service %q for perimeter %q: must be of the form <service>.googleapis.com", s, p.Title)
+		}
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (p *AccessContextManagerServicePerimeter) ID() string {
+	return standardizeID(p.Title)
+}
+
+// ResourceType returns the resource terraform provider type.
+func (p *AccessContextManagerServicePerimeter) ResourceType() string {
+	return "google_access_context_manager_service_perimeter"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (p *AccessContextManagerServicePerimeter) ImportID(runner.Runner) (string, error) {
+	return p.Name, nil
+}
+
+// AccessContextManagerAccessLevel represents a Terraform access level.
+type AccessContextManagerAccessLevel struct {
+	// Name is of the form accessPolicies/{policy_id}/accessLevels/{short_name}.
+	Name     string `json:"name"`
+	Provider string `json:"provider,omitempty"`
+
+	// Parent is the access policy of the access level, of the form accessPolicies/{policy_id}.
+	Parent string            `json:"parent"`
+	Title  string            `json:"title"`
+	Basic  *BasicAccessLevel `json:"basic,omitempty"`
+}
+
+// BasicAccessLevel is an access level granted when its conditions are met.
+type BasicAccessLevel struct {
+	Conditions []*AccessLevelCondition `json:"conditions"`
+
+	// CombiningFunction is how the conditions are combined, either AND (the default) or OR.
+	CombiningFunction string `json:"combining_function,omitempty"`
+}
+
+// AccessLevelCondition is a condition of a basic access level.
+type AccessLevelCondition struct {
+	IPSubnetworks        []string      `json:"ip_subnetworks,omitempty"`
+	RequiredAccessLevels []string      `json:"required_access_levels,omitempty"`
+	Members              []string      `json:"members,omitempty"`
+	DevicePolicy         *DevicePolicy `json:"device_policy,omitempty"`
+}
+
+// DevicePolicy is the policy a device must satisfy to meet an access level condition.
+type DevicePolicy struct {
+	RequireScreenLock         bool     `json:"require_screen_lock,omitempty"`
+	RequireAdminApproval      bool     `json:"require_admin_approval,omitempty"`
+	RequireCorpOwned          bool     `json:"require_corp_owned,omitempty"`
+	AllowedEncryptionStatuses []string `json:"allowed_encryption_statuses,omitempty"`
+}
+
+// Init initializes the resource.
+// Access levels belong to an organization's access policy so the project ID is unused.
+func (l *AccessContextManagerAccessLevel) Init(string) error {
+	if l.Name == "" {
+		return errors.New("name must be set")
+	}
+	if l.Parent == "" {
+		return errors.New("parent must be set")
+	}
+	if l.Title == "" {
+		return errors.New("title must be set")
+	}
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (l *AccessContextManagerAccessLevel) Validate() error {
+	if l.Basic == nil {
+		return nil
+	}
+	if f := l.Basic.CombiningFunction; f != "" && f != "AND" && f != "OR" {
+		return fmt.Errorf("invalid combining_function %q for access level %q: must be one of AND or OR", f, l.Title)
+	}
+	if len(l.Basic.Conditions) == 0 {
+		return fmt.Errorf("access level %q must have at least one condition", l.Title)
+	}
+	for _, c := range l.Basic.Conditions {
+		for _, n := range c.IPSubnetworks {
+			if _, _, err := net.ParseCIDR(n); err != nil {
+				return fmt.Errorf("invalid ip subnetwork %q for access level %q: %v", n, l.Title, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (l *AccessContextManagerAccessLevel) ID() string {
+	return standardizeID(l.Title)
+}
+
+// ResourceType returns the resource terraform provider type.
+func (l *AccessContextManagerAccessLevel) ResourceType() string {
+	return "google_access_context_manager_access_level"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (l *AccessContextManagerAccessLevel) ImportID(runner.Runner) (string, error) {
+	return l.Name, nil
+}
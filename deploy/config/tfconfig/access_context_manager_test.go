@@ -0,0 +1,170 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"testing"
+)
+
+func TestAccessContextManagerServicePerimeter(t *testing.T) {
+	p := &AccessContextManagerServicePerimeter{
+		Name:          "accessPolicies/123/servicePerimeters/phi_perimeter",
+		Parent:        "accessPolicies/123",
+		Title:         "PHI Perimeter",
+		PerimeterType: "PERIMETER_TYPE_REGULAR",
+		Status: &ServicePerimeterStatus{
+			Resources:          []string{"projects/1111", "projects/2222"},
+			RestrictedServices: []string{"bigquery.googleapis.com", "healthcare.googleapis.com", "storage.googleapis.com"},
+			VPCAccessibleServices: &VPCAccessibleServices{
+				EnableRestriction: true,
+				AllowedServices:   []string{"RESTRICTED-SERVICES"},
+			},
+		},
+	}
+	if err := p.Init("my-project"); err != nil {
+		t.Fatalf("p.Init = %v", err)
+	}
+	if err := p.Validate(); err != nil {
+		t.Fatalf("p.Validate = %v", err)
+	}
+	if got, want := p.ID(), "phi_perimeter"; got != want {
+		t.Errorf("p.ID = %q, want %q", got, want)
+	}
+
+	want := `{
+  "name": "accessPolicies/123/servicePerimeters/phi_perimeter",
+  "parent": "accessPolicies/123",
+  "title": "PHI Perimeter",
+  "perimeter_type": "PERIMETER_TYPE_REGULAR",
+  "status": {
+    "resources": ["projects/1111", "projects/2222"],
+    "restricted_services": ["bigquery.googleapis.com", "healthcare.googleapis.com", "storage.googleapis.com"],
+    "vpc_accessible_services": {
+      "enable_restriction": true,
+      "allowed_services": ["RESTRICTED-SERVICES"]
+    }
+  }
+}`
+	checkJSON(t, p, want)
+}
+
+func TestAccessContextManagerServicePerimeterValidate(t *testing.T) {
+	cases := []struct {
+		name          string
+		perimeterType string
+		services      []string
+		wantErr       bool
+	}{
+		{name: "valid", services: []string{"storage.googleapis.com", "bigquerydatatransfer.googleapis.com"}},
+		{name: "bridge", perimeterType: "PERIMETER_TYPE_BRIDGE"},
+		{name: "invalid_type", perimeterType: "BRIDGE", wantErr: true},
+		{name: "no_domain", services: []string{"storage"}, wantErr: true},
+		{name: "other_domain", services: []string{"storage.example.com"}, wantErr: true},
+		{name: "wildcard", services: []string{"*.googleapis.com"}, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &AccessContextManagerServicePerimeter{
+				Title:         "foo",
+				PerimeterType: tc.perimeterType,
+				Status:        &ServicePerimeterStatus{RestrictedServices: tc.services},
+			}
+			err := p.Validate()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("p.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccessContextManagerAccessLevel(t *testing.T) {
+	l := &AccessContextManagerAccessLevel{
+		Name:   "accessPolicies/123/accessLevels/corp_access",
+		Parent: "accessPolicies/123",
+		Title:  "Corp Access",
+		Basic: &BasicAccessLevel{
+			CombiningFunction: "OR",
+			Conditions: []*AccessLevelCondition{
+				{IPSubnetworks: []string{"192.0.2.0/24", "2001:db8::/32"}},
+				{
+					Members: []string{"user:foo@my-domain.com"},
+					DevicePolicy: &DevicePolicy{
+						RequireScreenLock:         true,
+						AllowedEncryptionStatuses: []string{"ENCRYPTED"},
+					},
+				},
+				{RequiredAccessLevels: []string{"accessPolicies/123/accessLevels/base"}},
+			},
+		},
+	}
+	if err := l.Init("my-project"); err != nil {
+		t.Fatalf("l.Init = %v", err)
+	}
+	if err := l.Validate(); err != nil {
+		t.Fatalf("l.Validate = %v", err)
+	}
+	if got, want := l.ID(), "corp_access"; got != want {
+		t.Errorf("l.ID = %q, want %q", got, want)
+	}
+
+	want := `{
+  "name": "accessPolicies/123/accessLevels/corp_access",
+  "parent": "accessPolicies/123",
+  "title": "Corp Access",
+  "basic": {
+    "combining_function": "OR",
+    "conditions": [
+      {"ip_subnetworks": ["192.0.2.0/24", "2001:db8::/32"]},
+      {
+        "members": ["user:foo@my-domain.com"],
+        "device_policy": {
+          "require_screen_lock": true,
+          "allowed_encryption_statuses": ["ENCRYPTED"]
+        }
+      },
+      {"required_access_levels": ["accessPolicies/123/accessLevels/base"]}
+    ]
+  }
+}`
+	checkJSON(t, l, want)
+}
+
+func TestAccessContextManagerAccessLevelValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		basic   *BasicAccessLevel
+		wantErr bool
+	}{
+		{name: "no_basic"},
+		{name: "valid_cidr", basic: &BasicAccessLevel{Conditions: []*AccessLevelCondition{{IPSubnetworks: []string{"10.0.0.0/8"}}}}},
+		{name: "ip_without_prefix", basic: &BasicAccessLevel{Conditions: []*AccessLevelCondition{{IPSubnetworks: []string{"10.0.0.1"}}}}, wantErr: true},
+		{name: "malformed_cidr", basic: &BasicAccessLevel{Conditions: []*AccessLevelCondition{{IPSubnetworks: []string{"10.0.0.0/33"}}}}, wantErr: true},
+		{name: "no_conditions", basic: &BasicAccessLevel{}, wantErr: true},
+		{
+			name:    "invalid_combining_function",
+			basic:   &BasicAccessLevel{CombiningFunction: "XOR", Conditions: []*AccessLevelCondition{{Members: []string{"user:foo@my-domain.com"}}}},
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			l := &AccessContextManagerAccessLevel{Title: "foo", Basic: tc.basic}
+			err := l.Validate()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("l.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
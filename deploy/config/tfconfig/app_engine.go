@@ -0,0 +1,108 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
+)
+
+// AppEngineApplication represents a Terraform App Engine application.
+type AppEngineApplication struct {
+	Project         string                    `json:"project"`
+	Provider        string                    `json:"provider,omitempty"`
+	LocationID      string                    `json:"location_id"`
+	AuthDomain      string                    `json:"auth_domain,omitempty"`
+	DatabaseType    string                    `json:"database_type,omitempty"`
+	FeatureSettings *AppEngineFeatureSettings `json:"feature_settings,omitempty"`
+
+	// Lifecycle defaults to preventing destruction as an application can never be deleted or moved once created.
+	Lifecycle *Lifecycle `json:"lifecycle,omitempty"`
+}
+
+// AppEngineFeatureSettings are the optional features of an App Engine application.
+type AppEngineFeatureSettings struct {
+	SplitHealthChecks bool `json:"split_health_checks"`
+}
+
+// appEngineLocations are the regions an App Engine application can be created in.
+// Note that us-central and europe-west have no numeric suffix.
+var appEngineLocations = map[string]bool{
+	"asia-east1":              true,
+	"asia-east2":              true,
+	"asia-northeast1":         true,
+	"asia-northeast2":         true,
+	"asia-northeast3":         true,
+	"asia-south1":             true,
+	"asia-southeast1":         true,
+	"asia-southeast2":         true,
+	"australia-southeast1":    true,
+	"europe-central2":         true,
+	"europe-west":             true,
+	"europe-west2":            true,
+	"europe-west3":            true,
+	"europe-west6":            true,
+	"northamerica-northeast1": true,
+	"southamerica-east1":      true,
+	"us-central":              true,
+	"us-east1":                true,
+	"us-east4":                true,
+	"us-west1":                true,
+	"us-west2":                true,
+	"us-west3":                true,
+	"us-west4":                true,
+}
+
+// Init initializes the resource.
+func (a *AppEngineApplication) Init(projectID string) error {
+	if a.Project != "" {
+		return fmt.Errorf("project must be unset: %v", a.Project)
+	}
+	a.Project = projectID
+	if a.Lifecycle == nil {
+		a.Lifecycle = &Lifecycle{PreventDestroy: true}
+	}
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (a *AppEngineApplication) Validate() error {
+	if !appEngineLocations[a.LocationID] {
+		return fmt.Errorf("invalid location_id %q for App Engine application: must be an App Engine region, e.g. us-central", a.LocationID)
+	}
+	switch a.DatabaseType {
+	case "", "CLOUD_FIRESTORE", "CLOUD_DATASTORE_COMPATIBILITY":
+	default:
+		return fmt.Errorf("invalid database_type %q for App Engine application: must be CLOUD_FIRESTORE or CLOUD_DATASTORE_COMPATIBILITY", a.DatabaseType)
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+// It is hardcoded to return "app" as there is at most one of this resource in a project.
+func (*AppEngineApplication) ID() string {
+	return "app"
+}
+
+// ResourceType returns the resource terraform provider type.
+func (*AppEngineApplication) ResourceType() string {
+	return "google_app_engine_application"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (a *AppEngineApplication) ImportID(runner.Runner) (string, error) {
+	return a.Project, nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"testing"
+)
+
+func TestAppEngineApplication(t *testing.T) {
+	a := &AppEngineApplication{
+		LocationID:      "us-central",
+		AuthDomain:      "example.com",
+		DatabaseType:    "CLOUD_FIRESTORE",
+		FeatureSettings: &AppEngineFeatureSettings{SplitHealthChecks: true},
+	}
+	if err := a.Init("my-project"); err != nil {
+		t.Fatalf("a.Init = %v", err)
+	}
+	if err := a.Validate(); err != nil {
+		t.Fatalf("a.Validate = %v", err)
+	}
+
+	want := `{
+  "project": "my-project",
+  "location_id": "us-central",
+  "auth_domain": "example.com",
+  "database_type": "CLOUD_FIRESTORE",
+  "feature_settings": {
+    "split_health_checks": true
+  },
+  "lifecycle": {
+    "prevent_destroy": true
+  }
+}`
+	checkJSON(t, a, want)
+}
+
+func TestAppEngineApplicationValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		app     *AppEngineApplication
+		wantErr bool
+	}{
+		{
+			name: "europe_west",
+			app:  &AppEngineApplication{LocationID: "europe-west"},
+		},
+		{
+			name:    "no_location",
+			app:     &AppEngineApplication{},
+			wantErr: true,
+		},
+		{
+			name:    "compute_region",
+			app:     &AppEngineApplication{LocationID: "us-central1"},
+			wantErr: true,
+		},
+		{
+			name:    "multi_region",
+			app:     &AppEngineApplication{LocationID: "us"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid_database_type",
+			app:     &AppEngineApplication{LocationID: "us-central", DatabaseType: "CLOUD_SQL"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.app.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("a.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
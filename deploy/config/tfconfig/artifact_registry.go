@@ -0,0 +1,95 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
+)
+
+// ArtifactRegistryRepository represents a Terraform artifact registry repository.
+type ArtifactRegistryRepository struct {
+	RepositoryID string            `json:"repository_id"`
+	Project      string            `json:"project"`
+	Location     string            `json:"location"`
+	Format       string            `json:"format"`
+	Description  string            `json:"description,omitempty"`
+	KMSKeyName   string            `json:"kms_key_name,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	Provider     string            `json:"provider,omitempty"`
+}
+
+// artifactRegistryFormats are the valid formats of an artifact registry repository.
+var artifactRegistryFormats = map[string]bool{
+	"APT":    true,
+	"DOCKER": true,
+	"GO":     true,
+	"KFP":    true,
+	"MAVEN":  true,
+	"NPM":    true,
+	"PYTHON": true,
+	"YUM":    true,
+}
+
+// Init initializes the resource.
+func (r *ArtifactRegistryRepository) Init(projectID string) error {
+	if r.RepositoryID == "" {
+		return errors.New("repository_id must be set")
+	}
+	if r.Location == "" {
+		return errors.New("location must be set")
+	}
+	if r.Project != "" {
+		return fmt.Errorf("project must be unset: %v", r.Project)
+	}
+	r.Project = projectID
+	r.Provider = "google-beta"
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (r *ArtifactRegistryRepository) Validate() error {
+	if !artifactRegistryFormats[r.Format] {
+		return fmt.Errorf("invalid format %q for repository %q: must be one of APT, DOCKER, GO, KFP, MAVEN, NPM, PYTHON or YUM", r.Format, r.RepositoryID)
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (r *ArtifactRegistryRepository) ID() string {
+	return r.RepositoryID
+}
+
+// ResourceType returns the resource terraform provider type.
+func (r *ArtifactRegistryRepository) ResourceType() string {
+	return "google_artifact_registry_repository"
+}
+
+// GetLabels returns the labels of the resource.
+func (r *ArtifactRegistryRepository) GetLabels() map[string]string {
+	return r.Labels
+}
+
+// SetLabels sets the labels of the resource.
+func (r *ArtifactRegistryRepository) SetLabels(labels map[string]string) {
+	r.Labels = labels
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (r *ArtifactRegistryRepository) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("projects/%s/locations/%s/repositories/%s", r.Project, r.Location, r.RepositoryID), nil
+}
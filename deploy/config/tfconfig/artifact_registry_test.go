@@ -0,0 +1,73 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestArtifactRegistryRepository(t *testing.T) {
+	r := &ArtifactRegistryRepository{
+		RepositoryID: "deid-images",
+		Location:     "us-central1",
+		Format:       "DOCKER",
+		Description:  "De-identification pipeline images.",
+		KMSKeyName:   "${google_kms_crypto_key.images-key.id}",
+		Labels:       map[string]string{"team": "deid", "env": "prod", "data-classification": "none"},
+	}
+	if err := r.Init("my-project"); err != nil {
+		t.Fatalf("r.Init = %v", err)
+	}
+	if err := r.Validate(); err != nil {
+		t.Fatalf("r.Validate = %v", err)
+	}
+
+	want := `{
+  "repository_id": "deid-images",
+  "project": "my-project",
+  "location": "us-central1",
+  "format": "DOCKER",
+  "description": "De-identification pipeline images.",
+  "kms_key_name": "${google_kms_crypto_key.images-key.id}",
+  "labels": {"data-classification": "none", "env": "prod", "team": "deid"},
+  "provider": "google-beta"
+}`
+	checkJSON(t, r, want)
+
+	// Labels must always be marshalled in the same (sorted) order.
+	wantLabels := `"labels":{"data-classification":"none","env":"prod","team":"deid"}`
+	for i := 0; i < 10; i++ {
+		b, err := json.Marshal(r)
+		if err != nil {
+			t.Fatalf("json.Marshal = %v", err)
+		}
+		if !strings.Contains(string(b), wantLabels) {
+			t.Fatalf("json.Marshal = %s, want labels %s", b, wantLabels)
+		}
+	}
+}
+
+func TestArtifactRegistryRepositoryValidate(t *testing.T) {
+	for _, format := range []string{"", "docker", "HELM"} {
+		t.Run(format, func(t *testing.T) {
+			r := &ArtifactRegistryRepository{RepositoryID: "foo-repo", Format: format}
+			if err := r.Validate(); err == nil {
+				t.Error("r.Validate = nil, want error")
+			}
+		})
+	}
+}
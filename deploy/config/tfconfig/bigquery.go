@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 
 	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
 )
@@ -28,6 +29,9 @@ type BigqueryDataset struct {
 	Project   string `json:"project"`
 	Location  string `json:"location"`
 
+	DefaultTableExpirationMs int64             `json:"default_table_expiration_ms,omitempty"`
+	Labels                   map[string]string `json:"labels,omitempty"`
+
 	// Note: accesses are authoritative, meaning they will overwrite any existing access.
 	// We can support non-authoritative access after https://github.com/terraform-providers/terraform-provider-google/issues/3990 is fixed.
 	// TODO: set access on the dataset by default so it overrides the default which
@@ -61,6 +65,25 @@ func (d *BigqueryDataset) Init(projectID string) error {
 	return nil
 }
 
+// Validate checks that the resource is valid.
+func (d *BigqueryDataset) Validate() error {
+	for _, a := range d.Accesses {
+		var n int
+		for _, v := range []string{a.UserByEmail, a.GroupByEmail, a.Domain, a.SpecialGroup} {
+			if v != "" {
+				n++
+			}
+		}
+		if a.View != nil {
+			n++
+		}
+		if n != 1 {
+			return fmt.Errorf("access with role %q on dataset %q must set exactly one of user_by_email, group_by_email, domain, special_group or view, got %d", a.Role, d.DatasetID, n)
+		}
+	}
+	return nil
+}
+
 // ID returns the resource unique identifier.
 func (d *BigqueryDataset) ID() string {
 	return d.DatasetID
@@ -71,6 +94,16 @@ func (d *BigqueryDataset) ResourceType() string {
 	return "google_bigquery_dataset"
 }
 
+// GetLabels returns the labels of the resource.
+func (d *BigqueryDataset) GetLabels() map[string]string {
+	return d.Labels
+}
+
+// SetLabels sets the labels of the resource.
+func (d *BigqueryDataset) SetLabels(labels map[string]string) {
+	d.Labels = labels
+}
+
 // ImportID returns the ID to use for terraform imports.
 func (d *BigqueryDataset) ImportID(runner.Runner) (string, error) {
 	return fmt.Sprintf("%s/%s", d.Project, d.ID()), nil
@@ -97,3 +130,136 @@ func (d *BigqueryDataset) UnmarshalJSON(data []byte) error {
 func (d *BigqueryDataset) MarshalJSON() ([]byte, error) {
 	return interfacePair{d.raw, aliasBigqueryDataset(*d)}.MarshalJSON()
 }
+
+// WarnBigqueryAccessConflicts logs a warning for each dataset in the given resources that sets access
+// and is also granted IAM members through BigqueryDatasetIAMMembers, whether the members reference the dataset or set its ID.
+// Access is authoritative so terraform will continuously remove and re-add the IAM members.
+func WarnBigqueryAccessConflicts(rs []Resource) {
+	withAccess := make(map[string]bool)
+	for _, r := range rs {
+		if d, ok := r.(*BigqueryDataset); ok && len(d.Accesses) > 0 {
+			withAccess[d.DatasetID] = true
+		}
+	}
+	warned := make(map[string]bool)
+	for _, r := range rs {
+		ms, ok := r.(*BigqueryDatasetIAMMembers)
+		if !ok {
+			continue
+		}
+		for _, m := range ms.Members {
+			id := refName(m.DatasetID)
+			if withAccess[id] && !warned[id] {
+				log.Printf("Dataset %q sets access and IAM members: access is authoritative and will conflict with the IAM members", id)
+				warned[id] = true
+			}
+		}
+	}
+}
+
+// BigqueryDatasetIAMMembers represents multiple Terraform bigquery dataset IAM members across one or more datasets.
+// It is used to wrap and merge multiple IAM members into a single IAM member when being marshalled to JSON.
+type BigqueryDatasetIAMMembers struct {
+	Members   []*BigqueryDatasetIAMMember
+	DependsOn []string
+	project   string
+}
+
+// BigqueryDatasetIAMMember represents a Terraform bigquery dataset IAM member.
+type BigqueryDatasetIAMMember struct {
+	DatasetID string        `json:"dataset_id"`
+	Role      string        `json:"role"`
+	Member    string        `json:"member"`
+	Condition *IAMCondition `json:"condition,omitempty"`
+
+	// The following fields should not be set by users.
+
+	// ForEach is used to let a single iam member expand to reference multiple iam members
+	// through the use of terraform's for_each iterator.
+	ForEach   map[string]*BigqueryDatasetIAMMember `json:"for_each,omitempty"`
+	Project   string                               `json:"project,omitempty"`
+	DependsOn []string                             `json:"depends_on,omitempty"`
+
+	// Dynamic holds the condition dynamic block of the member, see memberConditionDynamicBlock.
+	Dynamic map[string]interface{} `json:"dynamic,omitempty"`
+}
+
+// projectOnlyBigqueryRoles are bigquery roles that can only be meaningfully granted at the project level.
+var projectOnlyBigqueryRoles = map[string]bool{
+	"roles/bigquery.admin":           true,
+	"roles/bigquery.jobUser":         true,
+	"roles/bigquery.readSessionUser": true,
+	"roles/bigquery.resourceAdmin":   true,
+	"roles/bigquery.resourceEditor":  true,
+	"roles/bigquery.resourceViewer":  true,
+	"roles/bigquery.user":            true,
+}
+
+// Init initializes the resource.
+func (ms *BigqueryDatasetIAMMembers) Init(projectID string) error {
+	for _, m := range ms.Members {
+		if m.Project != "" {
+			return fmt.Errorf("project must be unset: %v", m.Project)
+		}
+		if m.DatasetID == "" {
+			return fmt.Errorf("dataset_id must be set for role %q and member %q", m.Role, m.Member)
+		}
+		if err := validateIAMMember(m.Member); err != nil {
+			return fmt.Errorf("invalid member for role %q on dataset %q: %v", m.Role, m.DatasetID, err)
+		}
+	}
+	ms.project = projectID
+	return nil
+}
+
+// Validate checks that the resource is valid.
+// Roles that are only meaningful at the project level only log a warning as the API still accepts them.
+func (ms *BigqueryDatasetIAMMembers) Validate() error {
+	for _, m := range ms.Members {
+		if projectOnlyBigqueryRoles[m.Role] {
+			log.Printf("Role %q granted on dataset %q should be granted at the project level instead", m.Role, m.DatasetID)
+		}
+	}
+	return nil
+}
+
+func (m *BigqueryDatasetIAMMember) forEachKey() string {
+	return resourceMemberKey(m.DatasetID, m.Role, m.Member, m.Condition)
+}
+
+func (m *BigqueryDatasetIAMMember) iamCondition() *IAMCondition {
+	return m.Condition
+}
+
+// ID returns the resource unique identifier.
+// It is hardcoded to return "datasets" as there is at most one of this resource in a deployment.
+func (ms *BigqueryDatasetIAMMembers) ID() string {
+	return "datasets"
+}
+
+// ResourceType returns the resource terraform provider type.
+func (ms *BigqueryDatasetIAMMembers) ResourceType() string {
+	return "google_bigquery_dataset_iam_member"
+}
+
+func (ms *BigqueryDatasetIAMMembers) dependsOn() []string {
+	return ms.DependsOn
+}
+
+// MarshalJSON marshals the list of members into a single member.
+// The single member will set a for_each block to expand to multiple iam members in the terraform call.
+// See forEachKeys for details on how the for_each keys are built.
+func (ms *BigqueryDatasetIAMMembers) MarshalJSON() ([]byte, error) {
+	return marshalForEachMembers(&BigqueryDatasetIAMMember{
+		Project:   ms.project,
+		DatasetID: "${each.value.dataset_id}",
+		Role:      "${each.value.role}",
+		Member:    "${each.value.member}",
+		DependsOn: normalizeDependsOn(ms.DependsOn),
+	}, ms.Members)
+}
+
+// UnmarshalJSON unmarshals the bytes to a list of members.
+func (ms *BigqueryDatasetIAMMembers) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &ms.Members)
+}
@@ -0,0 +1,187 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
+
+// captureLog returns the output logged while running f.
+func captureLog(t *testing.T, f func()) string {
+	t.Helper()
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+	f()
+	return buf.String()
+}
+
+func TestBigqueryDatasetIAMMembers(t *testing.T) {
+	ms := &BigqueryDatasetIAMMembers{
+		Members: []*BigqueryDatasetIAMMember{
+			{DatasetID: "foo_dataset", Role: "roles/bigquery.dataViewer", Member: "group:analysts@my-domain.com"},
+			{DatasetID: "bar_dataset", Role: "roles/bigquery.dataViewer", Member: "group:analysts@my-domain.com"},
+		},
+		DependsOn: []string{"google_bigquery_dataset.foo_dataset"},
+	}
+	if err := ms.Init("my-project"); err != nil {
+		t.Fatalf("ms.Init = %v", err)
+	}
+
+	want := `{
+  "for_each": {
+    "foo_dataset roles/bigquery.dataViewer group:analysts@my-domain.com": {
+      "dataset_id": "foo_dataset",
+      "role": "roles/bigquery.dataViewer",
+      "member": "group:analysts@my-domain.com"
+    },
+    "bar_dataset roles/bigquery.dataViewer group:analysts@my-domain.com": {
+      "dataset_id": "bar_dataset",
+      "role": "roles/bigquery.dataViewer",
+      "member": "group:analysts@my-domain.com"
+    }
+  },
+  "project": "my-project",
+  "dataset_id": "${each.value.dataset_id}",
+  "role": "${each.value.role}",
+  "member": "${each.value.member}",
+  "depends_on": ["google_bigquery_dataset.foo_dataset"]
+}`
+	checkJSON(t, ms, want)
+}
+
+func TestBigqueryDatasetIAMMembersReferenceKey(t *testing.T) {
+	m := &BigqueryDatasetIAMMember{DatasetID: "${google_bigquery_dataset.foo_dataset.dataset_id}", Role: "roles/bigquery.dataViewer", Member: "group:analysts@my-domain.com"}
+	if got, want := m.forEachKey(), "foo_dataset roles/bigquery.dataViewer group:analysts@my-domain.com"; got != want {
+		t.Errorf("m.forEachKey() = %q, want %q", got, want)
+	}
+}
+
+func TestBigqueryDatasetIAMMembersValidate(t *testing.T) {
+	cases := []struct {
+		name     string
+		role     string
+		wantWarn bool
+	}{
+		{name: "dataset_role", role: "roles/bigquery.dataViewer"},
+		{name: "project_role", role: "roles/bigquery.admin", wantWarn: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ms := &BigqueryDatasetIAMMembers{Members: []*BigqueryDatasetIAMMember{
+				{DatasetID: "foo_dataset", Role: tc.role, Member: "group:analysts@my-domain.com"},
+			}}
+			if err := ms.Init("my-project"); err != nil {
+				t.Fatalf("ms.Init = %v", err)
+			}
+			var err error
+			out := captureLog(t, func() { err = ms.Validate() })
+			if err != nil {
+				t.Fatalf("ms.Validate = %v", err)
+			}
+			if gotWarn := out != ""; gotWarn != tc.wantWarn {
+				t.Errorf("ms.Validate logged %q, want warning: %t", out, tc.wantWarn)
+			}
+		})
+	}
+}
+
+func TestBigqueryDataset(t *testing.T) {
+	d := &BigqueryDataset{
+		DatasetID:                "foo_dataset",
+		Location:                 "US",
+		DefaultTableExpirationMs: 3600000,
+		Labels:                   map[string]string{"team": "analytics"},
+		Accesses: []*Access{
+			{Role: "OWNER", SpecialGroup: "projectOwners"},
+			{Role: "READER", GroupByEmail: "analysts@my-domain.com"},
+			{Role: "WRITER", UserByEmail: "etl@my-project.iam.gserviceaccount.com"},
+		},
+	}
+	if err := d.Init("my-project"); err != nil {
+		t.Fatalf("d.Init = %v", err)
+	}
+	if err := d.Validate(); err != nil {
+		t.Fatalf("d.Validate = %v", err)
+	}
+
+	want := `{
+  "dataset_id": "foo_dataset",
+  "project": "my-project",
+  "location": "US",
+  "default_table_expiration_ms": 3600000,
+  "labels": {"team": "analytics"},
+  "access": [
+    {"role": "OWNER", "special_group": "projectOwners"},
+    {"role": "READER", "group_by_email": "analysts@my-domain.com"},
+    {"role": "WRITER", "user_by_email": "etl@my-project.iam.gserviceaccount.com"}
+  ]
+}`
+	checkJSON(t, d, want)
+}
+
+func TestBigqueryDatasetValidate(t *testing.T) {
+	cases := []struct {
+		name   string
+		access *Access
+	}{
+		{name: "no_entity", access: &Access{Role: "READER"}},
+		{name: "multiple_entities", access: &Access{Role: "READER", GroupByEmail: "analysts@my-domain.com", Domain: "my-domain.com"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := &BigqueryDataset{DatasetID: "foo_dataset", Location: "US", Accesses: []*Access{tc.access}}
+			if err := d.Validate(); err == nil {
+				t.Error("d.Validate = nil, want error")
+			}
+		})
+	}
+}
+
+func TestWarnBigqueryAccessConflicts(t *testing.T) {
+	d := &BigqueryDataset{
+		DatasetID: "foo_dataset",
+		Location:  "US",
+		Accesses:  []*Access{{Role: "READER", GroupByEmail: "analysts@my-domain.com"}},
+	}
+	cases := []struct {
+		name     string
+		members  []*BigqueryDatasetIAMMember
+		wantWarn bool
+	}{
+		{
+			name:    "other_dataset",
+			members: []*BigqueryDatasetIAMMember{{DatasetID: "bar_dataset", Role: "roles/bigquery.dataViewer", Member: "group:analysts@my-domain.com"}},
+		},
+		{
+			name:     "same_dataset",
+			members:  []*BigqueryDatasetIAMMember{{DatasetID: "foo_dataset", Role: "roles/bigquery.dataViewer", Member: "group:analysts@my-domain.com"}},
+			wantWarn: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rs := []Resource{d, &BigqueryDatasetIAMMembers{Members: tc.members}}
+			out := captureLog(t, func() { WarnBigqueryAccessConflicts(rs) })
+			if gotWarn := strings.Contains(out, "foo_dataset"); gotWarn != tc.wantWarn {
+				t.Errorf("WarnBigqueryAccessConflicts logged %q, want warning: %t", out, tc.wantWarn)
+			}
+		})
+	}
+}
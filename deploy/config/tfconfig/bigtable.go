@@ -0,0 +1,106 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
+)
+
+// BigtableInstance represents a Terraform Bigtable instance.
+type BigtableInstance struct {
+	Name     string `json:"name"`
+	Project  string `json:"project"`
+	Provider string `json:"provider,omitempty"`
+
+	// InstanceType defaults to PRODUCTION in the provider.
+	InstanceType string             `json:"instance_type,omitempty"`
+	Clusters     []*BigtableCluster `json:"cluster"`
+}
+
+// BigtableCluster is a cluster of a Bigtable instance.
+type BigtableCluster struct {
+	ClusterID string `json:"cluster_id"`
+	Zone      string `json:"zone"`
+
+	// NumNodes must be unset for DEVELOPMENT instances.
+	NumNodes    int    `json:"num_nodes,omitempty"`
+	StorageType string `json:"storage_type,omitempty"`
+}
+
+// Init initializes the resource.
+func (i *BigtableInstance) Init(projectID string) error {
+	if i.Name == "" {
+		return errors.New("name must be set")
+	}
+	if i.Project != "" {
+		return fmt.Errorf("project must be unset: %v", i.Project)
+	}
+	i.Project = projectID
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (i *BigtableInstance) Validate() error {
+	for _, c := range i.Clusters {
+		if c.ClusterID == "" || c.Zone == "" {
+			return fmt.Errorf("cluster_id and zone must be set for all clusters of instance %q", i.Name)
+		}
+		switch c.StorageType {
+		case "", "SSD", "HDD":
+		default:
+			return fmt.Errorf("invalid storage_type %q for cluster %q: must be SSD or HDD", c.StorageType, c.ClusterID)
+		}
+	}
+
+	switch i.InstanceType {
+	case "", "PRODUCTION":
+		if len(i.Clusters) == 0 {
+			return fmt.Errorf("PRODUCTION instance %q must set at least one cluster", i.Name)
+		}
+		for _, c := range i.Clusters {
+			if c.NumNodes < 1 {
+				return fmt.Errorf("num_nodes of cluster %q in PRODUCTION instance %q must be at least 1, got %d", c.ClusterID, i.Name, c.NumNodes)
+			}
+		}
+	case "DEVELOPMENT":
+		if len(i.Clusters) != 1 {
+			return fmt.Errorf("DEVELOPMENT instance %q must set exactly one cluster, got %d", i.Name, len(i.Clusters))
+		}
+		if n := i.Clusters[0].NumNodes; n != 0 {
+			return fmt.Errorf("num_nodes of cluster %q in DEVELOPMENT instance %q must be unset, got %d", i.Clusters[0].ClusterID, i.Name, n)
+		}
+	default:
+		return fmt.Errorf("invalid instance_type %q for instance %q: must be PRODUCTION or DEVELOPMENT", i.InstanceType, i.Name)
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (i *BigtableInstance) ID() string {
+	return i.Name
+}
+
+// ResourceType returns the resource terraform provider type.
+func (*BigtableInstance) ResourceType() string {
+	return "google_bigtable_instance"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (i *BigtableInstance) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("projects/%s/instances/%s", i.Project, i.Name), nil
+}
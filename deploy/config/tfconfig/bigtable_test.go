@@ -0,0 +1,141 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"testing"
+)
+
+func TestBigtableInstance(t *testing.T) {
+	tests := []struct {
+		name     string
+		instance *BigtableInstance
+		want     string
+	}{
+		{
+			name: "production",
+			instance: &BigtableInstance{
+				Name:         "ingestion-buffer",
+				InstanceType: "PRODUCTION",
+				Clusters: []*BigtableCluster{
+					{ClusterID: "ingestion-c1", Zone: "us-central1-b", NumNodes: 3, StorageType: "SSD"},
+					{ClusterID: "ingestion-c2", Zone: "us-central1-c", NumNodes: 3, StorageType: "SSD"},
+				},
+			},
+			want: `{
+  "name": "ingestion-buffer",
+  "project": "my-project",
+  "instance_type": "PRODUCTION",
+  "cluster": [
+    {"cluster_id": "ingestion-c1", "zone": "us-central1-b", "num_nodes": 3, "storage_type": "SSD"},
+    {"cluster_id": "ingestion-c2", "zone": "us-central1-c", "num_nodes": 3, "storage_type": "SSD"}
+  ]
+}`,
+		},
+		{
+			name: "development",
+			instance: &BigtableInstance{
+				Name:         "ingestion-dev",
+				InstanceType: "DEVELOPMENT",
+				Clusters:     []*BigtableCluster{{ClusterID: "ingestion-dev-c1", Zone: "us-central1-b", StorageType: "HDD"}},
+			},
+			want: `{
+  "name": "ingestion-dev",
+  "project": "my-project",
+  "instance_type": "DEVELOPMENT",
+  "cluster": [
+    {"cluster_id": "ingestion-dev-c1", "zone": "us-central1-b", "storage_type": "HDD"}
+  ]
+}`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.instance.Init("my-project"); err != nil {
+				t.Fatalf("i.Init = %v", err)
+			}
+			if err := tc.instance.Validate(); err != nil {
+				t.Fatalf("i.Validate = %v", err)
+			}
+			checkJSON(t, tc.instance, tc.want)
+		})
+	}
+}
+
+func TestBigtableInstanceValidate(t *testing.T) {
+	cluster := func(numNodes int) *BigtableCluster {
+		return &BigtableCluster{ClusterID: "foo-c1", Zone: "us-central1-b", NumNodes: numNodes}
+	}
+	tests := []struct {
+		name     string
+		instance *BigtableInstance
+		wantErr  bool
+	}{
+		{
+			name:     "default_type_with_nodes",
+			instance: &BigtableInstance{Clusters: []*BigtableCluster{cluster(1)}},
+		},
+		{
+			name:     "production_without_clusters",
+			instance: &BigtableInstance{InstanceType: "PRODUCTION"},
+			wantErr:  true,
+		},
+		{
+			name:     "production_without_nodes",
+			instance: &BigtableInstance{InstanceType: "PRODUCTION", Clusters: []*BigtableCluster{cluster(0)}},
+			wantErr:  true,
+		},
+		{
+			name:     "production_with_one_cluster_without_nodes",
+			instance: &BigtableInstance{InstanceType: "PRODUCTION", Clusters: []*BigtableCluster{cluster(1), cluster(0)}},
+			wantErr:  true,
+		},
+		{
+			name:     "development_without_clusters",
+			instance: &BigtableInstance{InstanceType: "DEVELOPMENT"},
+			wantErr:  true,
+		},
+		{
+			name:     "development_with_two_clusters",
+			instance: &BigtableInstance{InstanceType: "DEVELOPMENT", Clusters: []*BigtableCluster{cluster(0), cluster(0)}},
+			wantErr:  true,
+		},
+		{
+			name:     "development_with_nodes",
+			instance: &BigtableInstance{InstanceType: "DEVELOPMENT", Clusters: []*BigtableCluster{cluster(1)}},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid_type",
+			instance: &BigtableInstance{InstanceType: "STAGING", Clusters: []*BigtableCluster{cluster(1)}},
+			wantErr:  true,
+		},
+		{
+			name: "invalid_storage_type",
+			instance: &BigtableInstance{Clusters: []*BigtableCluster{
+				{ClusterID: "foo-c1", Zone: "us-central1-b", NumNodes: 1, StorageType: "NVME"},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.instance.Name = "foo-instance"
+			if err := tc.instance.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("i.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
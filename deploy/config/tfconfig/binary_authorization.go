@@ -0,0 +1,123 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
+)
+
+// BinaryAuthorizationPolicy represents a Terraform binary authorization policy.
+type BinaryAuthorizationPolicy struct {
+	Project                    string                       `json:"project"`
+	Provider                   string                       `json:"provider,omitempty"`
+	Description                string                       `json:"description,omitempty"`
+	DefaultAdmissionRule       *AdmissionRule               `json:"default_admission_rule"`
+	ClusterAdmissionRules      []*ClusterAdmissionRule      `json:"cluster_admission_rules,omitempty"`
+	AdmissionWhitelistPatterns []*AdmissionWhitelistPattern `json:"admission_whitelist_patterns,omitempty"`
+}
+
+// AdmissionRule is the rule images must satisfy to be deployed.
+type AdmissionRule struct {
+	EvaluationMode  string `json:"evaluation_mode"`
+	EnforcementMode string `json:"enforcement_mode"`
+
+	// RequireAttestationsBy are the attestors that must attest an image, e.g. projects/{project}/attestors/{attestor}.
+	RequireAttestationsBy []string `json:"require_attestations_by,omitempty"`
+}
+
+// ClusterAdmissionRule is an admission rule for a specific cluster.
+type ClusterAdmissionRule struct {
+	// Cluster is of the form {location}.{cluster_id}.
+	Cluster string `json:"cluster"`
+	AdmissionRule
+}
+
+// AdmissionWhitelistPattern is a pattern of images that are always allowed.
+type AdmissionWhitelistPattern struct {
+	NamePattern string `json:"name_pattern"`
+}
+
+// evaluationModes are the valid evaluation modes of an admission rule.
+var evaluationModes = map[string]bool{
+	"ALWAYS_ALLOW":        true,
+	"ALWAYS_DENY":         true,
+	"REQUIRE_ATTESTATION": true,
+}
+
+// enforcementModes are the valid enforcement modes of an admission rule.
+var enforcementModes = map[string]bool{
+	"ENFORCED_BLOCK_AND_AUDIT_LOG": true,
+	"DRYRUN_AUDIT_LOG_ONLY":        true,
+}
+
+// Init initializes the resource.
+func (p *BinaryAuthorizationPolicy) Init(projectID string) error {
+	if p.DefaultAdmissionRule == nil {
+		return errors.New("default_admission_rule must be set")
+	}
+	if p.Project != "" {
+		return fmt.Errorf("project must be unset: %v", p.Project)
+	}
+	p.Project = projectID
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (p *BinaryAuthorizationPolicy) Validate() error {
+	if err := p.DefaultAdmissionRule.validate(); err != nil {
+		return fmt.Errorf("invalid default admission rule: %v", err)
+	}
+	for _, r := range p.ClusterAdmissionRules {
+		if r.Cluster == "" {
+			return errors.New("cluster must be set for cluster admission rules")
+		}
+		if err := r.validate(); err != nil {
+			return fmt.Errorf("invalid admission rule for cluster %q: %v", r.Cluster, err)
+		}
+	}
+	return nil
+}
+
+func (r *AdmissionRule) validate() error {
+	if !evaluationModes[r.EvaluationMode] {
+		return fmt.Errorf("invalid evaluation_mode %q: must be one of ALWAYS_ALLOW, ALWAYS_DENY or REQUIRE_ATTESTATION", r.EvaluationMode)
+	}
+	if !enforcementModes[r.EnforcementMode] {
+		return fmt.Errorf("invalid enforcement_mode %q: must be one of ENFORCED_BLOCK_AND_AUDIT_LOG or DRYRUN_AUDIT_LOG_ONLY", r.EnforcementMode)
+	}
+	if r.EvaluationMode == "REQUIRE_ATTESTATION" && len(r.RequireAttestationsBy) == 0 {
+		return errors.New("require_attestations_by must be set when evaluation_mode is REQUIRE_ATTESTATION")
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+// It is hardcoded to return "policy" as there is at most one of this resource in a project.
+func (p *BinaryAuthorizationPolicy) ID() string {
+	return "policy"
+}
+
+// ResourceType returns the resource terraform provider type.
+func (p *BinaryAuthorizationPolicy) ResourceType() string {
+	return "google_binary_authorization_policy"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (p *BinaryAuthorizationPolicy) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("projects/%s", p.Project), nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"testing"
+)
+
+func TestBinaryAuthorizationPolicy(t *testing.T) {
+	p := &BinaryAuthorizationPolicy{
+		DefaultAdmissionRule: &AdmissionRule{
+			EvaluationMode:  "ALWAYS_DENY",
+			EnforcementMode: "ENFORCED_BLOCK_AND_AUDIT_LOG",
+		},
+		ClusterAdmissionRules: []*ClusterAdmissionRule{{
+			Cluster: "us-central1.deid-cluster",
+			AdmissionRule: AdmissionRule{
+				EvaluationMode:        "REQUIRE_ATTESTATION",
+				EnforcementMode:       "ENFORCED_BLOCK_AND_AUDIT_LOG",
+				RequireAttestationsBy: []string{"projects/my-project/attestors/build-attestor"},
+			},
+		}},
+		AdmissionWhitelistPatterns: []*AdmissionWhitelistPattern{{NamePattern: "gcr.io/google_containers/*"}},
+	}
+	if err := p.Init("my-project"); err != nil {
+		t.Fatalf("p.Init = %v", err)
+	}
+	if err := p.Validate(); err != nil {
+		t.Fatalf("p.Validate = %v", err)
+	}
+
+	want := `{
+  "project": "my-project",
+  "default_admission_rule": {
+    "evaluation_mode": "ALWAYS_DENY",
+    "enforcement_mode": "ENFORCED_BLOCK_AND_AUDIT_LOG"
+  },
+  "cluster_admission_rules": [{
+    "cluster": "us-central1.deid-cluster",
+    "evaluation_mode": "REQUIRE_ATTESTATION",
+    "enforcement_mode": "ENFORCED_BLOCK_AND_AUDIT_LOG",
+    "require_attestations_by": ["projects/my-project/attestors/build-attestor"]
+  }],
+  "admission_whitelist_patterns": [{"name_pattern": "gcr.io/google_containers/*"}]
+}`
+	checkJSON(t, p, want)
+}
+
+func TestBinaryAuthorizationPolicyValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		rule    *AdmissionRule
+		wantErr bool
+	}{
+		{name: "dry_run", rule: &AdmissionRule{EvaluationMode: "ALWAYS_ALLOW", EnforcementMode: "DRYRUN_AUDIT_LOG_ONLY"}},
+		{name: "invalid_enforcement_mode", rule: &AdmissionRule{EvaluationMode: "ALWAYS_DENY", EnforcementMode: "ENFORCED"}, wantErr: true},
+		{name: "no_enforcement_mode", rule: &AdmissionRule{EvaluationMode: "ALWAYS_DENY"}, wantErr: true},
+		{name: "invalid_evaluation_mode", rule: &AdmissionRule{EvaluationMode: "DENY", EnforcementMode: "DRYRUN_AUDIT_LOG_ONLY"}, wantErr: true},
+		{name: "attestation_without_attestors", rule: &AdmissionRule{EvaluationMode: "REQUIRE_ATTESTATION", EnforcementMode: "DRYRUN_AUDIT_LOG_ONLY"}, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &BinaryAuthorizationPolicy{DefaultAdmissionRule: tc.rule}
+			err := p.Validate()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("p.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
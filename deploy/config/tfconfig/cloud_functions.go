@@ -0,0 +1,158 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
+)
+
+// CloudFunctions2Function represents a Terraform Cloud Functions (2nd gen) function.
+type CloudFunctions2Function struct {
+	Name          string                 `json:"name"`
+	Project       string                 `json:"project"`
+	Provider      string                 `json:"provider,omitempty"`
+	Location      string                 `json:"location"`
+	Description   string                 `json:"description,omitempty"`
+	BuildConfig   *FunctionBuildConfig   `json:"build_config"`
+	ServiceConfig *FunctionServiceConfig `json:"service_config,omitempty"`
+
+	// EventTrigger is unset for HTTP functions.
+	EventTrigger *FunctionEventTrigger `json:"event_trigger,omitempty"`
+}
+
+// FunctionBuildConfig is how the function source is built.
+type FunctionBuildConfig struct {
+	Runtime    string          `json:"runtime"`
+	EntryPoint string          `json:"entry_point"`
+	Source     *FunctionSource `json:"source"`
+}
+
+// FunctionSource is the location of the function source.
+type FunctionSource struct {
+	StorageSource *FunctionStorageSource `json:"storage_source"`
+}
+
+// FunctionStorageSource is a GCS archive holding the function source.
+type FunctionStorageSource struct {
+	Bucket string `json:"bucket"`
+	Object string `json:"object"`
+}
+
+// FunctionServiceConfig is how the function is run.
+type FunctionServiceConfig struct {
+	// AvailableMemory is the memory limit of the function, e.g. "256M".
+	AvailableMemory string `json:"available_memory,omitempty"`
+	TimeoutSeconds  int    `json:"timeout_seconds,omitempty"`
+
+	// ServiceAccountEmail can be a reference to a service account, e.g. ServiceAccount.Ref("email").
+	ServiceAccountEmail  string            `json:"service_account_email,omitempty"`
+	EnvironmentVariables map[string]string `json:"environment_variables,omitempty"`
+}
+
+// FunctionEventTrigger is the event that invokes the function.
+type FunctionEventTrigger struct {
+	TriggerRegion string `json:"trigger_region,omitempty"`
+	EventType     string `json:"event_type"`
+
+	// PubsubTopic is the full topic name for pub/sub triggered functions, e.g. Ref(topic, "id").
+	PubsubTopic  string              `json:"pubsub_topic,omitempty"`
+	EventFilters []*EventarcCriteria `json:"event_filters,omitempty"`
+	RetryPolicy  string              `json:"retry_policy,omitempty"`
+
+	// ServiceAccountEmail can be a reference to a service account, e.g. ServiceAccount.Ref("email").
+	ServiceAccountEmail string `json:"service_account_email,omitempty"`
+}
+
+// functionRuntimes are the supported runtimes of a function.
+var functionRuntimes = map[string]bool{
+	"dotnet6":   true,
+	"dotnet8":   true,
+	"go121":     true,
+	"go122":     true,
+	"java17":    true,
+	"java21":    true,
+	"nodejs18":  true,
+	"nodejs20":  true,
+	"php82":     true,
+	"python310": true,
+	"python311": true,
+	"python312": true,
+	"ruby32":    true,
+}
+
+// Timeout bounds in seconds, which depend on whether the function is event driven or HTTP.
+const (
+	maxEventFunctionTimeoutSeconds = 540
+	maxHTTPFunctionTimeoutSeconds  = 3600
+)
+
+// Init initializes the resource.
+func (f *CloudFunctions2Function) Init(projectID string) error {
+	if f.Name == "" {
+		return errors.New("name must be set")
+	}
+	if f.Location == "" {
+		return errors.New("location must be set")
+	}
+	if f.BuildConfig == nil {
+		return errors.New("build_config must be set")
+	}
+	if f.Project != "" {
+		return fmt.Errorf("project must be unset: %v", f.Project)
+	}
+	f.Project = projectID
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (f *CloudFunctions2Function) Validate() error {
+	if !functionRuntimes[f.BuildConfig.Runtime] {
+		return fmt.Errorf("unsupported runtime %q for function %q", f.BuildConfig.Runtime, f.Name)
+	}
+	if src := f.BuildConfig.Source; src == nil || src.StorageSource == nil || src.StorageSource.Bucket == "" || src.StorageSource.Object == "" {
+		return fmt.Errorf("source storage bucket and object must be set for function %q", f.Name)
+	}
+	if f.EventTrigger != nil && f.EventTrigger.EventType == "" {
+		return fmt.Errorf("event_type must be set for event trigger of function %q", f.Name)
+	}
+	if sc := f.ServiceConfig; sc != nil && sc.TimeoutSeconds != 0 {
+		max := maxHTTPFunctionTimeoutSeconds
+		if f.EventTrigger != nil {
+			max = maxEventFunctionTimeoutSeconds
+		}
+		if sc.TimeoutSeconds < 1 || sc.TimeoutSeconds > max {
+			return fmt.Errorf("timeout_seconds of function %q must be between 1 and %d, got %d", f.Name, max, sc.TimeoutSeconds)
+		}
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (f *CloudFunctions2Function) ID() string {
+	return f.Name
+}
+
+// ResourceType returns the resource terraform provider type.
+func (*CloudFunctions2Function) ResourceType() string {
+	return "google_cloudfunctions2_function"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (f *CloudFunctions2Function) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("projects/%s/locations/%s/functions/%s", f.Project, f.Location, f.Name), nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"testing"
+)
+
+func TestCloudFunctions2Function(t *testing.T) {
+	topic := &PubsubTopic{Name: "hl7v2-notifications"}
+	f := &CloudFunctions2Function{
+		Name:     "hl7v2-transform",
+		Location: "us-central1",
+		BuildConfig: &FunctionBuildConfig{
+			Runtime:    "python311",
+			EntryPoint: "transform",
+			Source: &FunctionSource{
+				StorageSource: &FunctionStorageSource{Bucket: "my-project-functions", Object: "transform.zip"},
+			},
+		},
+		ServiceConfig: &FunctionServiceConfig{
+			AvailableMemory:      "256M",
+			TimeoutSeconds:       540,
+			ServiceAccountEmail:  "${google_service_account.transform-runner.email}",
+			EnvironmentVariables: map[string]string{"FHIR_STORE": "patients"},
+		},
+		EventTrigger: &FunctionEventTrigger{
+			TriggerRegion: "us-central1",
+			EventType:     "google.cloud.pubsub.topic.v1.messagePublished",
+			PubsubTopic:   Ref(topic, "id"),
+			RetryPolicy:   "RETRY_POLICY_RETRY",
+		},
+	}
+	if err := f.Init("my-project"); err != nil {
+		t.Fatalf("f.Init = %v", err)
+	}
+	if err := f.Validate(); err != nil {
+		t.Fatalf("f.Validate = %v", err)
+	}
+
+	want := `{
+  "name": "hl7v2-transform",
+  "project": "my-project",
+  "location": "us-central1",
+  "build_config": {
+    "runtime": "python311",
+    "entry_point": "transform",
+    "source": {
+      "storage_source": {
+        "bucket": "my-project-functions",
+        "object": "transform.zip"
+      }
+    }
+  },
+  "service_config": {
+    "available_memory": "256M",
+    "timeout_seconds": 540,
+    "service_account_email": "${google_service_account.transform-runner.email}",
+    "environment_variables": {"FHIR_STORE": "patients"}
+  },
+  "event_trigger": {
+    "trigger_region": "us-central1",
+    "event_type": "google.cloud.pubsub.topic.v1.messagePublished",
+    "pubsub_topic": "${google_pubsub_topic.hl7v2-notifications.id}",
+    "retry_policy": "RETRY_POLICY_RETRY"
+  }
+}`
+	checkJSON(t, f, want)
+}
+
+func TestCloudFunctions2FunctionValidate(t *testing.T) {
+	trigger := &FunctionEventTrigger{EventType: "google.cloud.pubsub.topic.v1.messagePublished"}
+	tests := []struct {
+		name    string
+		runtime string
+		timeout int
+		trigger *FunctionEventTrigger
+		wantErr bool
+	}{
+		{name: "event_default_timeout", runtime: "go121", trigger: trigger},
+		{name: "event_min_timeout", runtime: "go121", timeout: 1, trigger: trigger},
+		{name: "event_max_timeout", runtime: "go121", timeout: 540, trigger: trigger},
+		{name: "event_timeout_too_long", runtime: "go121", timeout: 541, trigger: trigger, wantErr: true},
+		{name: "event_negative_timeout", runtime: "go121", timeout: -1, trigger: trigger, wantErr: true},
+		{name: "http_long_timeout", runtime: "go121", timeout: 3600},
+		{name: "http_timeout_too_long", runtime: "go121", timeout: 3601, wantErr: true},
+		{name: "unknown_runtime", runtime: "go1", wantErr: true},
+		{name: "event_trigger_without_type", runtime: "go121", trigger: &FunctionEventTrigger{}, wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := &CloudFunctions2Function{
+				Name: "foo-function",
+				BuildConfig: &FunctionBuildConfig{
+					Runtime: tc.runtime,
+					Source: &FunctionSource{
+						StorageSource: &FunctionStorageSource{Bucket: "foo-bucket", Object: "foo.zip"},
+					},
+				},
+				ServiceConfig: &FunctionServiceConfig{TimeoutSeconds: tc.timeout},
+				EventTrigger:  tc.trigger,
+			}
+			if err := f.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("f.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
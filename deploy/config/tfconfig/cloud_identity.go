@@ -0,0 +1,150 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CloudIdentityGroup represents a Terraform Cloud Identity group, e.g. a Google Group used in IAM members.
+type CloudIdentityGroup struct {
+	DisplayName string                  `json:"display_name,omitempty"`
+	Description string                  `json:"description,omitempty"`
+	GroupKey    *CloudIdentityEntityKey `json:"group_key"`
+
+	// Parent is the customer the group belongs to, e.g. "C0123abc" or "customers/C0123abc".
+	Parent string `json:"parent"`
+
+	// Labels are the group type labels, not user defined labels.
+	// They default to a discussion forum group, i.e. {"cloudidentity.googleapis.com/groups.discussion_forum": ""}.
+	Labels map[string]string `json:"labels"`
+}
+
+// CloudIdentityEntityKey identifies a group or member, e.g. by email.
+type CloudIdentityEntityKey struct {
+	ID        string `json:"id"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// customerRE matches the resource name of a Cloud Identity customer.
+var customerRE = regexp.MustCompile(`^customers/[A-Za-z0-9]+$`)
+
+// Init initializes the resource.
+// Groups belong to a customer rather than a project so the project ID is unused.
+func (g *CloudIdentityGroup) Init(string) error {
+	if g.GroupKey == nil || g.GroupKey.ID == "" {
+		return errors.New("group_key.id must be set")
+	}
+	if g.Parent == "" {
+		return fmt.Errorf("parent of group %q must be set", g.GroupKey.ID)
+	}
+	if !strings.HasPrefix(g.Parent, "customers/") {
+		g.Parent = "customers/" + g.Parent
+	}
+	if len(g.Labels) == 0 {
+		g.Labels = map[string]string{"cloudidentity.googleapis.com/groups.discussion_forum": ""}
+	}
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (g *CloudIdentityGroup) Validate() error {
+	if !customerRE.MatchString(g.Parent) {
+		return fmt.Errorf("invalid parent %q of group %q: must be of the form customers/<customer id>", g.Parent, g.GroupKey.ID)
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+// It is the standardized group key, e.g. "eng_my-domain_com" for "eng@my-domain.com".
+func (g *CloudIdentityGroup) ID() string {
+	if g.GroupKey == nil {
+		return ""
+	}
+	return standardizeID(g.GroupKey.ID)
+}
+
+// ResourceType returns the resource terraform provider type.
+func (*CloudIdentityGroup) ResourceType() string {
+	return "google_cloud_identity_group"
+}
+
+// CloudIdentityGroupMembership represents a Terraform Cloud Identity group membership.
+type CloudIdentityGroupMembership struct {
+	// Group is the group's terraform reference or the ID of a group in the deployment.
+	Group              string                         `json:"group"`
+	PreferredMemberKey *CloudIdentityEntityKey        `json:"preferred_member_key"`
+	Roles              []*CloudIdentityMembershipRole `json:"roles"`
+}
+
+// CloudIdentityMembershipRole is a role of a member in a group.
+type CloudIdentityMembershipRole struct {
+	Name string `json:"name"`
+}
+
+// membershipRoles are the supported group membership roles.
+var membershipRoles = map[string]bool{
+	"OWNER":   true,
+	"MANAGER": true,
+	"MEMBER":  true,
+}
+
+// Init initializes the resource.
+// Memberships belong to a group rather than a project so the project ID is unused.
+func (m *CloudIdentityGroupMembership) Init(string) error {
+	if m.Group == "" {
+		return errors.New("group must be set")
+	}
+	if m.PreferredMemberKey == nil || m.PreferredMemberKey.ID == "" {
+		return fmt.Errorf("preferred_member_key.id of membership in group %q must be set", m.Group)
+	}
+	m.Group = nameRef("google_cloud_identity_group", m.Group, "id")
+	return nil
+}
+
+// Validate checks that the resource is valid.
+// Every member must have the MEMBER role, including owners and managers.
+func (m *CloudIdentityGroupMembership) Validate() error {
+	hasMember := false
+	for _, r := range m.Roles {
+		if !membershipRoles[r.Name] {
+			return fmt.Errorf("invalid role %q for member %q: must be one of OWNER, MANAGER or MEMBER", r.Name, m.PreferredMemberKey.ID)
+		}
+		if r.Name == "MEMBER" {
+			hasMember = true
+		}
+	}
+	if !hasMember {
+		return fmt.Errorf("roles of member %q must include MEMBER", m.PreferredMemberKey.ID)
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+// It combines the group and member as a member has at most one membership per group.
+func (m *CloudIdentityGroupMembership) ID() string {
+	if m.PreferredMemberKey == nil {
+		return ""
+	}
+	return standardizeID(fmt.Sprintf("%s_%s", refName(m.Group), m.PreferredMemberKey.ID))
+}
+
+// ResourceType returns the resource terraform provider type.
+func (*CloudIdentityGroupMembership) ResourceType() string {
+	return "google_cloud_identity_group_membership"
+}
@@ -0,0 +1,132 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"testing"
+)
+
+func TestCloudIdentityGroup(t *testing.T) {
+	g := &CloudIdentityGroup{
+		DisplayName: "Engineering",
+		GroupKey:    &CloudIdentityEntityKey{ID: "eng@my-domain.com"},
+		Parent:      "C0123abc",
+	}
+	if err := g.Init("my-project"); err != nil {
+		t.Fatalf("g.Init = %v", err)
+	}
+	if err := g.Validate(); err != nil {
+		t.Fatalf("g.Validate = %v", err)
+	}
+
+	want := `{
+  "display_name": "Engineering",
+  "group_key": {"id": "eng@my-domain.com"},
+  "parent": "customers/C0123abc",
+  "labels": {"cloudidentity.googleapis.com/groups.discussion_forum": ""}
+}`
+	checkJSON(t, g, want)
+
+	if got, want := g.ID(), "eng_my-domain_com"; got != want {
+		t.Errorf("g.ID() = %v, want %v", got, want)
+	}
+}
+
+func TestCloudIdentityGroupInitErrors(t *testing.T) {
+	for _, g := range []*CloudIdentityGroup{
+		{Parent: "C0123abc"},
+		{GroupKey: &CloudIdentityEntityKey{ID: "eng@my-domain.com"}},
+	} {
+		if err := g.Init("my-project"); err == nil {
+			t.Errorf("g.Init(%+v) = nil, want error", g)
+		}
+	}
+
+	g := &CloudIdentityGroup{GroupKey: &CloudIdentityEntityKey{ID: "eng@my-domain.com"}, Parent: "customers/C0123/abc"}
+	if err := g.Init("my-project"); err != nil {
+		t.Fatalf("g.Init = %v", err)
+	}
+	if err := g.Validate(); err == nil {
+		t.Error("g.Validate = nil, want error")
+	}
+}
+
+func TestCloudIdentityGroupMembership(t *testing.T) {
+	m := &CloudIdentityGroupMembership{
+		Group:              "eng_my-domain_com",
+		PreferredMemberKey: &CloudIdentityEntityKey{ID: "admin@my-domain.com"},
+		Roles:              []*CloudIdentityMembershipRole{{Name: "OWNER"}, {Name: "MEMBER"}},
+	}
+	if err := m.Init("my-project"); err != nil {
+		t.Fatalf("m.Init = %v", err)
+	}
+	if err := m.Validate(); err != nil {
+		t.Fatalf("m.Validate = %v", err)
+	}
+
+	want := `{
+  "group": "${google_cloud_identity_group.eng_my-domain_com.id}",
+  "preferred_member_key": {"id": "admin@my-domain.com"},
+  "roles": [{"name": "OWNER"}, {"name": "MEMBER"}]
+}`
+	checkJSON(t, m, want)
+
+	if got, want := m.ID(), "eng_my-domain_com_admin_my-domain_com"; got != want {
+		t.Errorf("m.ID() = %v, want %v", got, want)
+	}
+}
+
+func TestCloudIdentityGroupMembershipValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		roles   []*CloudIdentityMembershipRole
+		wantErr bool
+	}{
+		{
+			name:  "member",
+			roles: []*CloudIdentityMembershipRole{{Name: "MEMBER"}},
+		},
+		{
+			name:    "owner_without_member",
+			roles:   []*CloudIdentityMembershipRole{{Name: "OWNER"}},
+			wantErr: true,
+		},
+		{
+			name:    "no_roles",
+			wantErr: true,
+		},
+		{
+			name:    "invalid_role",
+			roles:   []*CloudIdentityMembershipRole{{Name: "MEMBER"}, {Name: "ADMIN"}},
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &CloudIdentityGroupMembership{
+				Group:              "${google_cloud_identity_group.eng.id}",
+				PreferredMemberKey: &CloudIdentityEntityKey{ID: "admin@my-domain.com"},
+				Roles:              tc.roles,
+			}
+			if err := m.Init("my-project"); err != nil {
+				t.Fatalf("m.Init = %v", err)
+			}
+			err := m.Validate()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("m.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
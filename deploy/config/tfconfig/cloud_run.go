@@ -0,0 +1,133 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
+)
+
+// CloudRunService represents a Terraform Cloud Run service.
+type CloudRunService struct {
+	Name     string             `json:"name"`
+	Project  string             `json:"project"`
+	Provider string             `json:"provider,omitempty"`
+	Location string             `json:"location"`
+	Template *CloudRunTemplate  `json:"template"`
+	Traffic  []*CloudRunTraffic `json:"traffic,omitempty"`
+}
+
+// CloudRunTemplate is the template revisions of the service are created from.
+type CloudRunTemplate struct {
+	Spec *CloudRunSpec `json:"spec"`
+}
+
+// CloudRunSpec is the specification of a service revision.
+type CloudRunSpec struct {
+	Containers []*CloudRunContainer `json:"containers"`
+
+	// ServiceAccountName is the email of the service account the revision runs as.
+	// It can be a reference to a service account, e.g. ServiceAccount.Ref("email").
+	ServiceAccountName string `json:"service_account_name,omitempty"`
+}
+
+// CloudRunContainer is a container of a service revision.
+type CloudRunContainer struct {
+	Image     string             `json:"image"`
+	Env       []*CloudRunEnv     `json:"env,omitempty"`
+	Resources *CloudRunResources `json:"resources,omitempty"`
+}
+
+// CloudRunEnv is an environment variable set in a container.
+type CloudRunEnv struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// CloudRunResources are the compute resources of a container, e.g. {"cpu": "1000m", "memory": "512Mi"}.
+type CloudRunResources struct {
+	Limits   map[string]string `json:"limits,omitempty"`
+	Requests map[string]string `json:"requests,omitempty"`
+}
+
+// CloudRunTraffic is the percent of traffic sent to a revision.
+type CloudRunTraffic struct {
+	Percent        int    `json:"percent"`
+	RevisionName   string `json:"revision_name,omitempty"`
+	LatestRevision bool   `json:"latest_revision,omitempty"`
+}
+
+// Init initializes the resource.
+func (s *CloudRunService) Init(projectID string) error {
+	if s.Name == "" {
+		return errors.New("name must be set")
+	}
+	if s.Location == "" {
+		return errors.New("location must be set")
+	}
+	if s.Template == nil || s.Template.Spec == nil || len(s.Template.Spec.Containers) == 0 {
+		return errors.New("template must set at least one container")
+	}
+	if s.Project != "" {
+		return fmt.Errorf("project must be unset: %v", s.Project)
+	}
+	s.Project = projectID
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (s *CloudRunService) Validate() error {
+	for _, c := range s.Template.Spec.Containers {
+		if c.Image == "" {
+			return fmt.Errorf("image must be set for all containers of service %q", s.Name)
+		}
+	}
+	if len(s.Traffic) == 0 {
+		return nil
+	}
+	sum := 0
+	for _, t := range s.Traffic {
+		if (t.RevisionName == "") == !t.LatestRevision {
+			return fmt.Errorf("exactly one of revision_name and latest_revision must be set for traffic of service %q", s.Name)
+		}
+		sum += t.Percent
+	}
+	if sum != 100 {
+		return fmt.Errorf("traffic percentages of service %q sum to %d, want 100", s.Name, sum)
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (s *CloudRunService) ID() string {
+	return s.Name
+}
+
+// ResourceType returns the resource terraform provider type.
+func (*CloudRunService) ResourceType() string {
+	return "google_cloud_run_service"
+}
+
+// Ref returns the terraform interpolation string referencing the given attribute of the service, e.g. "name".
+func (s *CloudRunService) Ref(attr string) string {
+	return Ref(s, attr)
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (s *CloudRunService) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("locations/%s/namespaces/%s/services/%s", s.Location, s.Project, s.Name), nil
+}
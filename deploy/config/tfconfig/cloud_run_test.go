@@ -0,0 +1,128 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"testing"
+)
+
+func TestCloudRunService(t *testing.T) {
+	a := &ServiceAccount{AccountID: "api-runner"}
+	s := &CloudRunService{
+		Name:     "api",
+		Location: "us-central1",
+		Template: &CloudRunTemplate{
+			Spec: &CloudRunSpec{
+				Containers: []*CloudRunContainer{{
+					Image: "gcr.io/my-project/api:v1",
+					Env:   []*CloudRunEnv{{Name: "DATASET", Value: "patients"}},
+					Resources: &CloudRunResources{
+						Limits: map[string]string{"memory": "512Mi", "cpu": "1000m"},
+					},
+				}},
+				ServiceAccountName: a.Ref("email"),
+			},
+		},
+		Traffic: []*CloudRunTraffic{{Percent: 100, LatestRevision: true}},
+	}
+	if err := s.Init("my-project"); err != nil {
+		t.Fatalf("s.Init = %v", err)
+	}
+	if err := s.Validate(); err != nil {
+		t.Fatalf("s.Validate = %v", err)
+	}
+
+	want := `{
+  "name": "api",
+  "project": "my-project",
+  "location": "us-central1",
+  "template": {
+    "spec": {
+      "containers": [{
+        "image": "gcr.io/my-project/api:v1",
+        "env": [{"name": "DATASET", "value": "patients"}],
+        "resources": {
+          "limits": {"cpu": "1000m", "memory": "512Mi"}
+        }
+      }],
+      "service_account_name": "${google_service_account.api-runner.email}"
+    }
+  },
+  "traffic": [{
+    "percent": 100,
+    "latest_revision": true
+  }]
+}`
+	checkJSON(t, s, want)
+}
+
+func TestCloudRunServiceValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		traffic []*CloudRunTraffic
+		wantErr bool
+	}{
+		{
+			name: "no_traffic",
+		},
+		{
+			name: "split",
+			traffic: []*CloudRunTraffic{
+				{Percent: 90, RevisionName: "api-00001"},
+				{Percent: 10, LatestRevision: true},
+			},
+		},
+		{
+			name: "under_100",
+			traffic: []*CloudRunTraffic{
+				{Percent: 80, RevisionName: "api-00001"},
+				{Percent: 10, LatestRevision: true},
+			},
+			wantErr: true,
+		},
+		{
+			name: "over_100",
+			traffic: []*CloudRunTraffic{
+				{Percent: 100, RevisionName: "api-00001"},
+				{Percent: 10, LatestRevision: true},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "no_target",
+			traffic: []*CloudRunTraffic{{Percent: 100}},
+			wantErr: true,
+		},
+		{
+			name:    "both_targets",
+			traffic: []*CloudRunTraffic{{Percent: 100, RevisionName: "api-00001", LatestRevision: true}},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &CloudRunService{
+				Name: "api",
+				Template: &CloudRunTemplate{
+					Spec: &CloudRunSpec{Containers: []*CloudRunContainer{{Image: "gcr.io/my-project/api:v1"}}},
+				},
+				Traffic: tc.traffic,
+			}
+			if err := s.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("s.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
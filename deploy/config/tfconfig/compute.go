@@ -15,9 +15,14 @@
 package tfconfig
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
+	"net"
+	"regexp"
+	"strings"
 
 	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
 )
@@ -27,9 +32,64 @@ type ComputeFirewall struct {
 	Name    string `json:"name"`
 	Project string `json:"project"`
 
+	// Network is kept as is as it is commonly a literal network name such as "default".
+	Network string `json:"network,omitempty"`
+
+	Direction         string        `json:"direction,omitempty"`
+	Priority          int           `json:"priority,omitempty"`
+	Allow             firewallRules `json:"allow,omitempty"`
+	Deny              firewallRules `json:"deny,omitempty"`
+	SourceRanges      []string      `json:"source_ranges,omitempty"`
+	DestinationRanges []string      `json:"destination_ranges,omitempty"`
+	TargetTags        []string      `json:"target_tags,omitempty"`
+
+	// Dynamic generates the allow or deny rules from a terraform expression, e.g. a list variable of rules.
+	// Blocks without content default to the protocol and ports of the current element.
+	Dynamic map[string]*DynamicBlock `json:"dynamic,omitempty"`
+
 	raw json.RawMessage
 }
 
+// FirewallRule is a protocol and ports that a firewall allows or denies.
+type FirewallRule struct {
+	Protocol string   `json:"protocol"`
+	Ports    []string `json:"ports,omitempty"`
+
+	// object is set if the rule was written as a single block object rather than a list.
+	object bool
+}
+
+// firewallRules is a list of firewall rules.
+// As terraform allows a single block to be written as an object, it can also be unmarshalled from and marshalled back to a single rule.
+type firewallRules []*FirewallRule
+
+// UnmarshalJSON unmarshals the bytes to a list of rules.
+func (rs *firewallRules) UnmarshalJSON(b []byte) error {
+	if isJSONObject(b) {
+		r := new(FirewallRule)
+		if err := json.Unmarshal(b, r); err != nil {
+			return err
+		}
+		r.object = true
+		*rs = firewallRules{r}
+		return nil
+	}
+	var list []*FirewallRule
+	if err := json.Unmarshal(b, &list); err != nil {
+		return err
+	}
+	*rs = list
+	return nil
+}
+
+// MarshalJSON marshals the list of rules.
+func (rs firewallRules) MarshalJSON() ([]byte, error) {
+	if len(rs) == 1 && rs[0].object {
+		return json.Marshal(rs[0])
+	}
+	return json.Marshal([]*FirewallRule(rs))
+}
+
 // Init initializes the resource.
 func (f *ComputeFirewall) Init(projectID string) error {
 	if f.Name == "" {
@@ -39,6 +99,44 @@ func (f *ComputeFirewall) Init(projectID string) error {
 		return fmt.Errorf("project must not be set: %q", f.Project)
 	}
 	f.Project = projectID
+	for name, b := range f.Dynamic {
+		if b != nil && b.Content == nil {
+			it := b.iterator(name)
+			b.Content = map[string]interface{}{
+				"protocol": fmt.Sprintf("${%s.value.protocol}", it),
+				"ports":    fmt.Sprintf(`${lookup(%s.value, "ports", null)}`, it),
+			}
+		}
+	}
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (f *ComputeFirewall) Validate() error {
+	for name, b := range f.Dynamic {
+		if name != "allow" && name != "deny" {
+			return fmt.Errorf("invalid dynamic block %q for firewall %q: must be allow or deny", name, f.Name)
+		}
+		if b == nil {
+			return fmt.Errorf("dynamic %s block of firewall %q must be set", name, f.Name)
+		}
+		if err := b.Validate(); err != nil {
+			return fmt.Errorf("invalid dynamic %s block for firewall %q: %v", name, f.Name, err)
+		}
+	}
+	allow := len(f.Allow) > 0 || f.Dynamic["allow"] != nil
+	deny := len(f.Deny) > 0 || f.Dynamic["deny"] != nil
+	if allow == deny {
+		return fmt.Errorf("exactly one of allow and deny must be set for firewall %q", f.Name)
+	}
+	if len(f.Allow) > 0 && f.Dynamic["allow"] != nil || len(f.Deny) > 0 && f.Dynamic["deny"] != nil {
+		return fmt.Errorf("firewall %q must not set both static and dynamic rules", f.Name)
+	}
+	switch f.Direction {
+	case "", "INGRESS", "EGRESS":
+	default:
+		return fmt.Errorf("invalid direction %q for firewall %q: must be INGRESS or EGRESS", f.Direction, f.Name)
+	}
 	return nil
 }
 
@@ -65,7 +163,7 @@ type aliasComputeFirewall ComputeFirewall
 // It is used to store the original (raw) user JSON definition,
 // which can have more fields than what is defined in this struct.
 func (f *ComputeFirewall) UnmarshalJSON(data []byte) error {
-	var alias aliasComputeImage
+	var alias aliasComputeFirewall
 	if err := unmarshalJSONMany(data, &alias, &alias.raw); err != nil {
 		return fmt.Errorf("failed to unmarshal to parsed alias: %v", err)
 	}
@@ -139,13 +237,190 @@ func (i *ComputeImage) MarshalJSON() ([]byte, error) {
 
 // ComputeInstance represents a Terraform GCE compute instance.
 type ComputeInstance struct {
-	Name    string `json:"name"`
-	Project string `json:"project"`
-	Zone    string `json:"zone"`
+	Name        string `json:"name"`
+	Project     string `json:"project"`
+	Zone        string `json:"zone"`
+	MachineType string `json:"machine_type,omitempty"`
+
+	BootDisk          *ComputeBootDisk       `json:"boot_disk,omitempty"`
+	NetworkInterfaces networkInterfaces      `json:"network_interface,omitempty"`
+	ServiceAccount    *ComputeServiceAccount `json:"service_account,omitempty"`
+	Labels            map[string]string      `json:"labels,omitempty"`
+	Metadata          map[string]string      `json:"metadata,omitempty"`
+
+	// ShieldedInstanceConfig defaults to enabling secure boot, vTPM and integrity monitoring.
+	ShieldedInstanceConfig *ShieldedInstanceConfig `json:"shielded_instance_config,omitempty"`
 
 	raw json.RawMessage
 }
 
+// ComputeBootDisk is the boot disk of an instance.
+type ComputeBootDisk struct {
+	InitializeParams *DiskInitializeParams `json:"initialize_params,omitempty"`
+
+	// KMSKeySelfLink is the CMEK key used to encrypt the disk, e.g. "${google_kms_crypto_key.disk-key.id}".
+	KMSKeySelfLink string `json:"kms_key_self_link,omitempty"`
+}
+
+// DiskInitializeParams are the parameters used to create a boot disk.
+type DiskInitializeParams struct {
+	Image string `json:"image,omitempty"`
+	Size  int    `json:"size,omitempty"`
+}
+
+// ComputeNetworkInterface is a network interface of an instance.
+type ComputeNetworkInterface struct {
+	// Network is kept as is as it is commonly a literal network name such as "default".
+	Network string `json:"network,omitempty"`
+
+	// Subnetwork is the subnetwork's terraform reference or the name of a subnetwork in the deployment.
+	Subnetwork string `json:"subnetwork,omitempty"`
+
+	// AccessConfigs give the instance an external IP. They should be left unset so instances are only reachable privately.
+	AccessConfigs accessConfigs `json:"access_config,omitempty"`
+
+	// object is set if the interface was written as a single block object rather than a list.
+	object bool
+	raw    json.RawMessage
+}
+
+// aliasComputeNetworkInterface is used to prevent infinite recursion when dealing with json marshaling.
+// https://stackoverflow.com/q/52433467
+type aliasComputeNetworkInterface ComputeNetworkInterface
+
+// UnmarshalJSON provides a custom JSON unmarshaller.
+// It is used to store the original (raw) user JSON definition,
+// which can have more fields than what is defined in this struct (e.g. network_ip or alias_ip_range).
+func (n *ComputeNetworkInterface) UnmarshalJSON(data []byte) error {
+	var alias aliasComputeNetworkInterface
+	if err := unmarshalJSONMany(data, &alias, &alias.raw); err != nil {
+		return fmt.Errorf("failed to unmarshal to parsed alias: %v", err)
+	}
+	*n = ComputeNetworkInterface(alias)
+	return nil
+}
+
+// MarshalJSON provides a custom JSON marshaller.
+// It is used to merge the original (raw) user JSON definition with the struct.
+func (n *ComputeNetworkInterface) MarshalJSON() ([]byte, error) {
+	return interfacePair{n.raw, aliasComputeNetworkInterface(*n)}.MarshalJSON()
+}
+
+// ComputeAccessConfig is an external IP configuration of a network interface.
+type ComputeAccessConfig struct {
+	NatIP       string `json:"nat_ip,omitempty"`
+	NetworkTier string `json:"network_tier,omitempty"`
+
+	// object is set if the access config was written as a single block object rather than a list.
+	object bool
+	raw    json.RawMessage
+}
+
+// aliasComputeAccessConfig is used to prevent infinite recursion when dealing with json marshaling.
+// https://stackoverflow.com/q/52433467
+type aliasComputeAccessConfig ComputeAccessConfig
+
+// UnmarshalJSON provides a custom JSON unmarshaller.
+// It is used to store the original (raw) user JSON definition,
+// which can have more fields than what is defined in this struct.
+func (c *ComputeAccessConfig) UnmarshalJSON(data []byte) error {
+	var alias aliasComputeAccessConfig
+	if err := unmarshalJSONMany(data, &alias, &alias.raw); err != nil {
+		return fmt.Errorf("failed to unmarshal to parsed alias: %v", err)
+	}
+	*c = ComputeAccessConfig(alias)
+	return nil
+}
+
+// MarshalJSON provides a custom JSON marshaller.
+// It is used to merge the original (raw) user JSON definition with the struct.
+func (c *ComputeAccessConfig) MarshalJSON() ([]byte, error) {
+	return interfacePair{c.raw, aliasComputeAccessConfig(*c)}.MarshalJSON()
+}
+
+// networkInterfaces is a list of network interfaces.
+// As terraform allows a single block to be written as an object, it can also be unmarshalled from a single interface,
+// in which case it is marshalled back to an object.
+type networkInterfaces []*ComputeNetworkInterface
+
+// UnmarshalJSON unmarshals the bytes to a list of network interfaces.
+func (ns *networkInterfaces) UnmarshalJSON(b []byte) error {
+	if isJSONObject(b) {
+		n := new(ComputeNetworkInterface)
+		if err := json.Unmarshal(b, n); err != nil {
+			return err
+		}
+		n.object = true
+		*ns = networkInterfaces{n}
+		return nil
+	}
+	var list []*ComputeNetworkInterface
+	if err := json.Unmarshal(b, &list); err != nil {
+		return err
+	}
+	*ns = list
+	return nil
+}
+
+// MarshalJSON marshals the list of network interfaces.
+func (ns networkInterfaces) MarshalJSON() ([]byte, error) {
+	if len(ns) == 1 && ns[0].object {
+		return json.Marshal(ns[0])
+	}
+	return json.Marshal([]*ComputeNetworkInterface(ns))
+}
+
+// accessConfigs is a list of access configs.
+// Like networkInterfaces, it can also be unmarshalled from and marshalled back to a single access config, e.g. "access_config: {}".
+type accessConfigs []*ComputeAccessConfig
+
+// UnmarshalJSON unmarshals the bytes to a list of access configs.
+func (cs *accessConfigs) UnmarshalJSON(b []byte) error {
+	if isJSONObject(b) {
+		c := new(ComputeAccessConfig)
+		if err := json.Unmarshal(b, c); err != nil {
+			return err
+		}
+		c.object = true
+		*cs = accessConfigs{c}
+		return nil
+	}
+	var list []*ComputeAccessConfig
+	if err := json.Unmarshal(b, &list); err != nil {
+		return err
+	}
+	*cs = list
+	return nil
+}
+
+// MarshalJSON marshals the list of access configs.
+func (cs accessConfigs) MarshalJSON() ([]byte, error) {
+	if len(cs) == 1 && cs[0].object {
+		return json.Marshal(cs[0])
+	}
+	return json.Marshal([]*ComputeAccessConfig(cs))
+}
+
+// isJSONObject returns whether b holds a JSON object, as opposed to e.g. a list.
+func isJSONObject(b []byte) bool {
+	b = bytes.TrimSpace(b)
+	return len(b) > 0 && b[0] == '{'
+}
+
+// ComputeServiceAccount is the service account an instance runs as.
+type ComputeServiceAccount struct {
+	// Email can be a reference to a service account, e.g. ServiceAccount.Ref("email").
+	Email  string   `json:"email"`
+	Scopes []string `json:"scopes"`
+}
+
+// ShieldedInstanceConfig is the shielded VM configuration of an instance.
+type ShieldedInstanceConfig struct {
+	EnableSecureBoot          bool `json:"enable_secure_boot"`
+	EnableVTPM                bool `json:"enable_vtpm"`
+	EnableIntegrityMonitoring bool `json:"enable_integrity_monitoring"`
+}
+
 // Init initializes the resource.
 func (i *ComputeInstance) Init(projectID string) error {
 	if i.Name == "" {
@@ -158,9 +433,40 @@ func (i *ComputeInstance) Init(projectID string) error {
 		return fmt.Errorf("project must not be set: %q", i.Project)
 	}
 	i.Project = projectID
+	for _, n := range i.NetworkInterfaces {
+		n.Subnetwork = nameRef("google_compute_subnetwork", n.Subnetwork, "self_link")
+	}
+	if i.ShieldedInstanceConfig == nil {
+		i.ShieldedInstanceConfig = &ShieldedInstanceConfig{
+			EnableSecureBoot:          true,
+			EnableVTPM:                true,
+			EnableIntegrityMonitoring: true,
+		}
+	}
 	return nil
 }
 
+// Validate checks that the resource is valid.
+func (i *ComputeInstance) Validate() error {
+	for _, n := range i.NetworkInterfaces {
+		if len(n.AccessConfigs) > 0 {
+			log.Printf("Instance %q requests an external IP: consider removing access_config so it is only reachable privately", i.Name)
+			break
+		}
+	}
+	return nil
+}
+
+// GetLabels returns the labels of the resource.
+func (i *ComputeInstance) GetLabels() map[string]string {
+	return i.Labels
+}
+
+// SetLabels sets the labels of the resource.
+func (i *ComputeInstance) SetLabels(labels map[string]string) {
+	i.Labels = labels
+}
+
 // ID returns the resource unique identifier.
 func (i *ComputeInstance) ID() string {
 	return i.Name
@@ -176,7 +482,7 @@ func (i *ComputeInstance) ImportID(runner.Runner) (string, error) {
 	return fmt.Sprintf("%s/%s/%s", i.Project, i.Zone, i.Name), nil
 }
 
-// aliasStorageBucket is used to prevent infinite recursion when dealing with json marshaling.
+// aliasComputeInstance is used to prevent infinite recursion when dealing with json marshaling.
 // https://stackoverflow.com/q/52433467
 type aliasComputeInstance ComputeInstance
 
@@ -197,3 +503,1052 @@ func (i *ComputeInstance) UnmarshalJSON(data []byte) error {
 func (i *ComputeInstance) MarshalJSON() ([]byte, error) {
 	return interfacePair{i.raw, aliasComputeInstance(*i)}.MarshalJSON()
 }
+
+// ComputeNetwork represents a Terraform GCE VPC network.
+type ComputeNetwork struct {
+	Name    string `json:"name"`
+	Project string `json:"project"`
+
+	// AutoCreateSubnetworks is always marshalled as the provider defaults it to true.
+	// Subnetworks should instead be created explicitly so their ranges are known.
+	AutoCreateSubnetworks bool   `json:"auto_create_subnetworks"`
+	RoutingMode           string `json:"routing_mode,omitempty"`
+
+	raw json.RawMessage
+}
+
+// Init initializes the resource.
+func (n *ComputeNetwork) Init(projectID string) error {
+	if n.Name == "" {
+		return errors.New("name must be set")
+	}
+	if n.Project != "" {
+		return fmt.Errorf("project must not be set: %q", n.Project)
+	}
+	n.Project = projectID
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (n *ComputeNetwork) ID() string {
+	return n.Name
+}
+
+// ResourceType returns the resource terraform provider type.
+func (n *ComputeNetwork) ResourceType() string {
+	return "google_compute_network"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (n *ComputeNetwork) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("%s/%s", n.Project, n.Name), nil
+}
+
+// aliasComputeNetwork is used to prevent infinite recursion when dealing with json marshaling.
+// https://stackoverflow.com/q/52433467
+type aliasComputeNetwork ComputeNetwork
+
+// UnmarshalJSON provides a custom JSON unmarshaller.
+// It is used to store the original (raw) user JSON definition,
+// which can have more fields than what is defined in this struct.
+func (n *ComputeNetwork) UnmarshalJSON(data []byte) error {
+	var alias aliasComputeNetwork
+	if err := unmarshalJSONMany(data, &alias, &alias.raw); err != nil {
+		return fmt.Errorf("failed to unmarshal to parsed alias: %v", err)
+	}
+	*n = ComputeNetwork(alias)
+	return nil
+}
+
+// MarshalJSON provides a custom JSON marshaller.
+// It is used to merge the original (raw) user JSON definition with the struct.
+func (n *ComputeNetwork) MarshalJSON() ([]byte, error) {
+	return interfacePair{n.raw, aliasComputeNetwork(*n)}.MarshalJSON()
+}
+
+// ComputeSubnetwork represents a Terraform GCE VPC subnetwork.
+type ComputeSubnetwork struct {
+	Name    string `json:"name"`
+	Project string `json:"project"`
+
+	// Network is the network's terraform reference or the name of a network in the deployment.
+	Network string `json:"network"`
+
+	IPCidrRange           string              `json:"ip_cidr_range"`
+	Region                string              `json:"region"`
+	PrivateIPGoogleAccess *bool               `json:"private_ip_google_access"`
+	SecondaryIPRanges     []*SecondaryIPRange `json:"secondary_ip_range,omitempty"`
+
+	raw json.RawMessage
+}
+
+// SecondaryIPRange is a secondary range of a subnetwork, e.g. for GKE pods and services.
+type SecondaryIPRange struct {
+	RangeName   string `json:"range_name"`
+	IPCidrRange string `json:"ip_cidr_range"`
+}
+
+// Init initializes the resource.
+func (s *ComputeSubnetwork) Init(projectID string) error {
+	if s.Name == "" {
+		return errors.New("name must be set")
+	}
+	if s.Network == "" {
+		return errors.New("network must be set")
+	}
+	if s.Region == "" {
+		return errors.New("region must be set")
+	}
+	if s.Project != "" {
+		return fmt.Errorf("project must not be set: %q", s.Project)
+	}
+	s.Project = projectID
+	s.Network = nameRef("google_compute_network", s.Network, "self_link")
+
+	// Healthcare workloads should reach Google APIs without leaving the private network.
+	if s.PrivateIPGoogleAccess == nil {
+		t := true
+		s.PrivateIPGoogleAccess = &t
+	}
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (s *ComputeSubnetwork) Validate() error {
+	if _, _, err := net.ParseCIDR(s.IPCidrRange); err != nil {
+		return fmt.Errorf("invalid ip_cidr_range of subnetwork %q: %v", s.Name, err)
+	}
+	for _, r := range s.SecondaryIPRanges {
+		if _, _, err := net.ParseCIDR(r.IPCidrRange); err != nil {
+			return fmt.Errorf("invalid ip_cidr_range of secondary range %q in subnetwork %q: %v", r.RangeName, s.Name, err)
+		}
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (s *ComputeSubnetwork) ID() string {
+	return s.Name
+}
+
+// ResourceType returns the resource terraform provider type.
+func (s *ComputeSubnetwork) ResourceType() string {
+	return "google_compute_subnetwork"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (s *ComputeSubnetwork) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("%s/%s/%s", s.Project, s.Region, s.Name), nil
+}
+
+// aliasComputeSubnetwork is used to prevent infinite recursion when dealing with json marshaling.
+// https://stackoverflow.com/q/52433467
+type aliasComputeSubnetwork ComputeSubnetwork
+
+// UnmarshalJSON provides a custom JSON unmarshaller.
+// It is used to store the original (raw) user JSON definition,
+// which can have more fields than what is defined in this struct.
+func (s *ComputeSubnetwork) UnmarshalJSON(data []byte) error {
+	var alias aliasComputeSubnetwork
+	if err := unmarshalJSONMany(data, &alias, &alias.raw); err != nil {
+		return fmt.Errorf("failed to unmarshal to parsed alias: %v", err)
+	}
+	*s = ComputeSubnetwork(alias)
+	return nil
+}
+
+// MarshalJSON provides a custom JSON marshaller.
+// It is used to merge the original (raw) user JSON definition with the struct.
+func (s *ComputeSubnetwork) MarshalJSON() ([]byte, error) {
+	return interfacePair{s.raw, aliasComputeSubnetwork(*s)}.MarshalJSON()
+}
+
+// ComputeGlobalAddress represents a Terraform GCE global address.
+// It is commonly used to allocate the peering range of a service networking connection or a load balancer IP.
+type ComputeGlobalAddress struct {
+	Name    string `json:"name"`
+	Project string `json:"project"`
+
+	// Network is the network's terraform reference or the name of a network in the deployment.
+	Network string `json:"network,omitempty"`
+
+	// Purpose defaults to VPC_PEERING and AddressType to INTERNAL, unless AddressType is EXTERNAL.
+	Purpose      string `json:"purpose,omitempty"`
+	AddressType  string `json:"address_type,omitempty"`
+	Address      string `json:"address,omitempty"`
+	PrefixLength int    `json:"prefix_length,omitempty"`
+
+	raw json.RawMessage
+}
+
+// Init initializes the resource.
+func (a *ComputeGlobalAddress) Init(projectID string) error {
+	if a.Name == "" {
+		return errors.New("name must be set")
+	}
+	if a.Project != "" {
+		return fmt.Errorf("project must not be set: %q", a.Project)
+	}
+	a.Project = projectID
+	a.Network = nameRef("google_compute_network", a.Network, "self_link")
+	if a.AddressType == "EXTERNAL" {
+		return nil
+	}
+	if a.Purpose == "" {
+		a.Purpose = "VPC_PEERING"
+	}
+	if a.AddressType == "" {
+		a.AddressType = "INTERNAL"
+	}
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (a *ComputeGlobalAddress) Validate() error {
+	if err := validateAddressType(a.AddressType); err != nil {
+		return fmt.Errorf("invalid global address %q: %v", a.Name, err)
+	}
+	switch a.Purpose {
+	case "":
+		return nil
+	case "VPC_PEERING", "PRIVATE_SERVICE_CONNECT":
+		if a.AddressType != "INTERNAL" {
+			return fmt.Errorf("address_type of %s address %q must be INTERNAL", a.Purpose, a.Name)
+		}
+	default:
+		return fmt.Errorf("invalid purpose %q for global address %q: must be VPC_PEERING or PRIVATE_SERVICE_CONNECT", a.Purpose, a.Name)
+	}
+	if a.Purpose != "VPC_PEERING" {
+		return nil
+	}
+	if a.Network == "" {
+		return fmt.Errorf("network must be set for VPC_PEERING address %q", a.Name)
+	}
+	if a.PrefixLength <= 0 || a.PrefixLength > 29 {
+		return fmt.Errorf("invalid prefix_length %d for VPC_PEERING address %q: must be between 1 and 29", a.PrefixLength, a.Name)
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (a *ComputeGlobalAddress) ID() string {
+	return a.Name
+}
+
+// ResourceType returns the resource terraform provider type.
+func (a *ComputeGlobalAddress) ResourceType() string {
+	return "google_compute_global_address"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (a *ComputeGlobalAddress) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("projects/%s/global/addresses/%s", a.Project, a.Name), nil
+}
+
+// aliasComputeGlobalAddress is used to prevent infinite recursion when dealing with json marshaling.
+// https://stackoverflow.com/q/52433467
+type aliasComputeGlobalAddress ComputeGlobalAddress
+
+// UnmarshalJSON provides a custom JSON unmarshaller.
+// It is used to store the original (raw) user JSON definition,
+// which can have more fields than what is defined in this struct.
+func (a *ComputeGlobalAddress) UnmarshalJSON(data []byte) error {
+	var alias aliasComputeGlobalAddress
+	if err := unmarshalJSONMany(data, &alias, &alias.raw); err != nil {
+		return fmt.Errorf("failed to unmarshal to parsed alias: %v", err)
+	}
+	*a = ComputeGlobalAddress(alias)
+	return nil
+}
+
+// MarshalJSON provides a custom JSON marshaller.
+// It is used to merge the original (raw) user JSON definition with the struct.
+func (a *ComputeGlobalAddress) MarshalJSON() ([]byte, error) {
+	return interfacePair{a.raw, aliasComputeGlobalAddress(*a)}.MarshalJSON()
+}
+
+// ComputeRouter represents a Terraform GCE router.
+type ComputeRouter struct {
+	Name    string `json:"name"`
+	Project string `json:"project"`
+	Region  string `json:"region"`
+
+	// Network is the network's terraform reference or the name of a network in the deployment.
+	Network string `json:"network"`
+
+	raw json.RawMessage
+}
+
+// Init initializes the resource.
+func (r *ComputeRouter) Init(projectID string) error {
+	if r.Name == "" {
+		return errors.New("name must be set")
+	}
+	if r.Network == "" {
+		return errors.New("network must be set")
+	}
+	if r.Region == "" {
+		return errors.New("region must be set")
+	}
+	if r.Project != "" {
+		return fmt.Errorf("project must not be set: %q", r.Project)
+	}
+	r.Project = projectID
+	r.Network = nameRef("google_compute_network", r.Network, "self_link")
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (r *ComputeRouter) ID() string {
+	return r.Name
+}
+
+// ResourceType returns the resource terraform provider type.
+func (r *ComputeRouter) ResourceType() string {
+	return "google_compute_router"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (r *ComputeRouter) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("%s/%s/%s", r.Project, r.Region, r.Name), nil
+}
+
+// aliasComputeRouter is used to prevent infinite recursion when dealing with json marshaling.
+// https://stackoverflow.com/q/52433467
+type aliasComputeRouter ComputeRouter
+
+// UnmarshalJSON provides a custom JSON unmarshaller.
+// It is used to store the original (raw) user JSON definition,
+// which can have more fields than what is defined in this struct.
+func (r *ComputeRouter) UnmarshalJSON(data []byte) error {
+	var alias aliasComputeRouter
+	if err := unmarshalJSONMany(data, &alias, &alias.raw); err != nil {
+		return fmt.Errorf("failed to unmarshal to parsed alias: %v", err)
+	}
+	*r = ComputeRouter(alias)
+	return nil
+}
+
+// MarshalJSON provides a custom JSON marshaller.
+// It is used to merge the original (raw) user JSON definition with the struct.
+func (r *ComputeRouter) MarshalJSON() ([]byte, error) {
+	return interfacePair{r.raw, aliasComputeRouter(*r)}.MarshalJSON()
+}
+
+// ComputeRouterNAT represents a Terraform GCE router NAT.
+type ComputeRouterNAT struct {
+	Name    string `json:"name"`
+	Project string `json:"project"`
+	Region  string `json:"region"`
+
+	// Router is the router's terraform reference or the name of a router in the deployment.
+	Router string `json:"router"`
+
+	// NatIPAllocateOption is AUTO_ONLY or MANUAL_ONLY, in which case NatIPs must be set.
+	NatIPAllocateOption           string           `json:"nat_ip_allocate_option"`
+	NatIPs                        []string         `json:"nat_ips,omitempty"`
+	SourceSubnetworkIPRangesToNat string           `json:"source_subnetwork_ip_ranges_to_nat"`
+	Subnetworks                   []*NATSubnetwork `json:"subnetwork,omitempty"`
+	LogConfig                     *NATLogConfig    `json:"log_config,omitempty"`
+
+	raw json.RawMessage
+}
+
+// NATSubnetwork is a subnetwork whose ranges are translated by a NAT.
+type NATSubnetwork struct {
+	// Name is the subnetwork's terraform reference or the name of a subnetwork in the deployment.
+	Name                string   `json:"name"`
+	SourceIPRangesToNat []string `json:"source_ip_ranges_to_nat"`
+}
+
+// NATLogConfig is the logging configuration of a NAT.
+type NATLogConfig struct {
+	Enable bool   `json:"enable"`
+	Filter string `json:"filter"`
+}
+
+// sourceSubnetworkIPRangesToNat are the valid source_subnetwork_ip_ranges_to_nat values of a NAT.
+var sourceSubnetworkIPRangesToNat = map[string]bool{
+	"ALL_SUBNETWORKS_ALL_IP_RANGES":         true,
+	"ALL_SUBNETWORKS_ALL_PRIMARY_IP_RANGES": true,
+	"LIST_OF_SUBNETWORKS":                   true,
+}
+
+// Init initializes the resource.
+func (n *ComputeRouterNAT) Init(projectID string) error {
+	if n.Name == "" {
+		return errors.New("name must be set")
+	}
+	if n.Router == "" {
+		return errors.New("router must be set")
+	}
+	if n.Region == "" {
+		return errors.New("region must be set")
+	}
+	if n.Project != "" {
+		return fmt.Errorf("project must not be set: %q", n.Project)
+	}
+	n.Project = projectID
+	n.Router = nameRef("google_compute_router", n.Router, "name")
+	for _, s := range n.Subnetworks {
+		s.Name = nameRef("google_compute_subnetwork", s.Name, "self_link")
+	}
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (n *ComputeRouterNAT) Validate() error {
+	if !sourceSubnetworkIPRangesToNat[n.SourceSubnetworkIPRangesToNat] {
+		return fmt.Errorf("invalid source_subnetwork_ip_ranges_to_nat %q for NAT %q: must be one of ALL_SUBNETWORKS_ALL_IP_RANGES, ALL_SUBNETWORKS_ALL_PRIMARY_IP_RANGES or LIST_OF_SUBNETWORKS", n.SourceSubnetworkIPRangesToNat, n.Name)
+	}
+	if (n.SourceSubnetworkIPRangesToNat == "LIST_OF_SUBNETWORKS") != (len(n.Subnetworks) > 0) {
+		return fmt.Errorf("subnetwork must be set for NAT %q if and only if source_subnetwork_ip_ranges_to_nat is LIST_OF_SUBNETWORKS", n.Name)
+	}
+	switch n.NatIPAllocateOption {
+	case "AUTO_ONLY":
+		if len(n.NatIPs) > 0 {
+			return fmt.Errorf("nat_ips must be unset for AUTO_ONLY NAT %q", n.Name)
+		}
+	case "MANUAL_ONLY":
+		if len(n.NatIPs) == 0 {
+			return fmt.Errorf("nat_ips must be set for MANUAL_ONLY NAT %q", n.Name)
+		}
+	default:
+		return fmt.Errorf("invalid nat_ip_allocate_option %q for NAT %q: must be AUTO_ONLY or MANUAL_ONLY", n.NatIPAllocateOption, n.Name)
+	}
+	if l := n.LogConfig; l != nil {
+		switch l.Filter {
+		case "ERRORS_ONLY", "TRANSLATIONS_ONLY", "ALL":
+		default:
+			return fmt.Errorf("invalid log filter %q for NAT %q: must be one of ERRORS_ONLY, TRANSLATIONS_ONLY or ALL", l.Filter, n.Name)
+		}
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (n *ComputeRouterNAT) ID() string {
+	return n.Name
+}
+
+// ResourceType returns the resource terraform provider type.
+func (n *ComputeRouterNAT) ResourceType() string {
+	return "google_compute_router_nat"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (n *ComputeRouterNAT) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("%s/%s/%s/%s", n.Project, n.Region, refName(n.Router), n.Name), nil
+}
+
+// aliasComputeRouterNAT is used to prevent infinite recursion when dealing with json marshaling.
+// https://stackoverflow.com/q/52433467
+type aliasComputeRouterNAT ComputeRouterNAT
+
+// UnmarshalJSON provides a custom JSON unmarshaller.
+// It is used to store the original (raw) user JSON definition,
+// which can have more fields than what is defined in this struct.
+func (n *ComputeRouterNAT) UnmarshalJSON(data []byte) error {
+	var alias aliasComputeRouterNAT
+	if err := unmarshalJSONMany(data, &alias, &alias.raw); err != nil {
+		return fmt.Errorf("failed to unmarshal to parsed alias: %v", err)
+	}
+	*n = ComputeRouterNAT(alias)
+	return nil
+}
+
+// MarshalJSON provides a custom JSON marshaller.
+// It is used to merge the original (raw) user JSON definition with the struct.
+func (n *ComputeRouterNAT) MarshalJSON() ([]byte, error) {
+	return interfacePair{n.raw, aliasComputeRouterNAT(*n)}.MarshalJSON()
+}
+
+// validateAddressType checks that the address type is INTERNAL or EXTERNAL.
+// An unset type is valid as the provider defaults it to EXTERNAL.
+func validateAddressType(t string) error {
+	switch t {
+	case "", "INTERNAL", "EXTERNAL":
+		return nil
+	default:
+		return fmt.Errorf("invalid address_type %q: must be INTERNAL or EXTERNAL", t)
+	}
+}
+
+// ComputeAddress represents a Terraform GCE regional address.
+type ComputeAddress struct {
+	Name        string `json:"name"`
+	Project     string `json:"project"`
+	Region      string `json:"region"`
+	AddressType string `json:"address_type,omitempty"`
+	Address     string `json:"address,omitempty"`
+	Purpose     string `json:"purpose,omitempty"`
+
+	// Subnetwork is the subnetwork's terraform reference or the name of a subnetwork in the deployment.
+	// It can only be set for INTERNAL addresses.
+	Subnetwork string `json:"subnetwork,omitempty"`
+
+	raw json.RawMessage
+}
+
+// Init initializes the resource.
+func (a *ComputeAddress) Init(projectID string) error {
+	if a.Name == "" {
+		return errors.New("name must be set")
+	}
+	if a.Region == "" {
+		return errors.New("region must be set")
+	}
+	if a.Project != "" {
+		return fmt.Errorf("project must not be set: %q", a.Project)
+	}
+	a.Project = projectID
+	a.Subnetwork = nameRef("google_compute_subnetwork", a.Subnetwork, "self_link")
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (a *ComputeAddress) Validate() error {
+	if err := validateAddressType(a.AddressType); err != nil {
+		return fmt.Errorf("invalid address %q: %v", a.Name, err)
+	}
+	if a.Subnetwork != "" && a.AddressType != "INTERNAL" {
+		return fmt.Errorf("subnetwork can only be set for INTERNAL address %q", a.Name)
+	}
+	if a.Address != "" && net.ParseIP(a.Address) == nil {
+		return fmt.Errorf("invalid IP %q for address %q", a.Address, a.Name)
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (a *ComputeAddress) ID() string {
+	return a.Name
+}
+
+// ResourceType returns the resource terraform provider type.
+func (a *ComputeAddress) ResourceType() string {
+	return "google_compute_address"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (a *ComputeAddress) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("projects/%s/regions/%s/addresses/%s", a.Project, a.Region, a.Name), nil
+}
+
+// aliasComputeAddress is used to prevent infinite recursion when dealing with json marshaling.
+// https://stackoverflow.com/q/52433467
+type aliasComputeAddress ComputeAddress
+
+// UnmarshalJSON provides a custom JSON unmarshaller.
+// It is used to store the original (raw) user JSON definition,
+// which can have more fields than what is defined in this struct.
+func (a *ComputeAddress) UnmarshalJSON(data []byte) error {
+	var alias aliasComputeAddress
+	if err := unmarshalJSONMany(data, &alias, &alias.raw); err != nil {
+		return fmt.Errorf("failed to unmarshal to parsed alias: %v", err)
+	}
+	*a = ComputeAddress(alias)
+	return nil
+}
+
+// MarshalJSON provides a custom JSON marshaller.
+// It is used to merge the original (raw) user JSON definition with the struct.
+func (a *ComputeAddress) MarshalJSON() ([]byte, error) {
+	return interfacePair{a.raw, aliasComputeAddress(*a)}.MarshalJSON()
+}
+
+// ComputeInstanceTemplate represents a Terraform GCE instance template.
+// Templates are used by instance group managers to create identical instances.
+type ComputeInstanceTemplate struct {
+	// Exactly one of Name and NamePrefix must be set.
+	// As templates cannot be updated in place, NamePrefix is recommended so terraform can create the replacement
+	// of a template under a new unique name before destroying the template in use.
+	Name        string `json:"name,omitempty"`
+	NamePrefix  string `json:"name_prefix,omitempty"`
+	Project     string `json:"project"`
+	MachineType string `json:"machine_type"`
+
+	Disks             []*ComputeTemplateDisk `json:"disk"`
+	NetworkInterfaces networkInterfaces      `json:"network_interface,omitempty"`
+	ServiceAccount    *ComputeServiceAccount `json:"service_account,omitempty"`
+	Labels            map[string]string      `json:"labels,omitempty"`
+	Metadata          map[string]string      `json:"metadata,omitempty"`
+
+	// ShieldedInstanceConfig defaults to enabling secure boot, vTPM and integrity monitoring.
+	ShieldedInstanceConfig *ShieldedInstanceConfig `json:"shielded_instance_config,omitempty"`
+
+	// Lifecycle defaults to creating the replacement of a template before destroying it,
+	// as a template cannot be destroyed while instance group managers use it.
+	Lifecycle *Lifecycle `json:"lifecycle,omitempty"`
+
+	raw json.RawMessage
+}
+
+// ComputeTemplateDisk is a disk of the instances created from a template.
+type ComputeTemplateDisk struct {
+	SourceImage string `json:"source_image,omitempty"`
+	DiskSizeGB  int    `json:"disk_size_gb,omitempty"`
+	Boot        bool   `json:"boot,omitempty"`
+	AutoDelete  *bool  `json:"auto_delete,omitempty"`
+
+	DiskEncryptionKey *DiskEncryptionKey `json:"disk_encryption_key,omitempty"`
+}
+
+// DiskEncryptionKey is the CMEK key used to encrypt a disk.
+type DiskEncryptionKey struct {
+	// KMSKeySelfLink is the key's terraform reference, e.g. "${google_kms_crypto_key.disk-key.id}".
+	KMSKeySelfLink string `json:"kms_key_self_link"`
+}
+
+// Init initializes the resource.
+func (t *ComputeInstanceTemplate) Init(projectID string) error {
+	if (t.Name == "") == (t.NamePrefix == "") {
+		return errors.New("exactly one of name and name_prefix must be set")
+	}
+	if t.Project != "" {
+		return fmt.Errorf("project must not be set: %q", t.Project)
+	}
+	t.Project = projectID
+	for _, n := range t.NetworkInterfaces {
+		n.Subnetwork = nameRef("google_compute_subnetwork", n.Subnetwork, "self_link")
+	}
+	if t.ShieldedInstanceConfig == nil {
+		t.ShieldedInstanceConfig = &ShieldedInstanceConfig{
+			EnableSecureBoot:          true,
+			EnableVTPM:                true,
+			EnableIntegrityMonitoring: true,
+		}
+	}
+	if t.Lifecycle == nil {
+		t.Lifecycle = &Lifecycle{CreateBeforeDestroy: true}
+	}
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (t *ComputeInstanceTemplate) Validate() error {
+	if t.MachineType == "" {
+		return fmt.Errorf("machine_type of instance template %q must be set", t.ID())
+	}
+	if len(t.Disks) == 0 {
+		return fmt.Errorf("instance template %q must set at least one disk", t.ID())
+	}
+	boot := 0
+	for _, d := range t.Disks {
+		if d.Boot {
+			boot++
+		}
+	}
+	if boot > 1 {
+		return fmt.Errorf("instance template %q must set at most one boot disk, got %d", t.ID(), boot)
+	}
+	for _, n := range t.NetworkInterfaces {
+		if len(n.AccessConfigs) > 0 {
+			log.Printf("Instance template %q requests an external IP: consider removing access_config so its instances are only reachable privately", t.ID())
+			break
+		}
+	}
+	if t.Name != "" && t.Lifecycle != nil && t.Lifecycle.CreateBeforeDestroy {
+		log.Printf("Instance template %q sets a fixed name with create_before_destroy: its replacement will fail as the name is taken, consider setting name_prefix instead", t.Name)
+	}
+	return nil
+}
+
+// GetLabels returns the labels of the resource.
+func (t *ComputeInstanceTemplate) GetLabels() map[string]string {
+	return t.Labels
+}
+
+// SetLabels sets the labels of the resource.
+func (t *ComputeInstanceTemplate) SetLabels(labels map[string]string) {
+	t.Labels = labels
+}
+
+// ID returns the resource unique identifier.
+// Templates set with a name prefix use the prefix without its trailing dash, e.g. "worker" for "worker-".
+func (t *ComputeInstanceTemplate) ID() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return strings.TrimSuffix(t.NamePrefix, "-")
+}
+
+// ResourceType returns the resource terraform provider type.
+func (t *ComputeInstanceTemplate) ResourceType() string {
+	return "google_compute_instance_template"
+}
+
+// ImportID returns the ID to use for terraform imports.
+// Templates set with a name prefix are not imported as their name is generated by terraform.
+func (t *ComputeInstanceTemplate) ImportID(runner.Runner) (string, error) {
+	if t.Name == "" {
+		return "", nil
+	}
+	return fmt.Sprintf("projects/%s/global/instanceTemplates/%s", t.Project, t.Name), nil
+}
+
+// aliasComputeInstanceTemplate is used to prevent infinite recursion when dealing with json marshaling.
+// https://stackoverflow.com/q/52433467
+type aliasComputeInstanceTemplate ComputeInstanceTemplate
+
+// UnmarshalJSON provides a custom JSON unmarshaller.
+// It is used to store the original (raw) user JSON definition,
+// which can have more fields than what is defined in this struct.
+func (t *ComputeInstanceTemplate) UnmarshalJSON(data []byte) error {
+	var alias aliasComputeInstanceTemplate
+	if err := unmarshalJSONMany(data, &alias, &alias.raw); err != nil {
+		return fmt.Errorf("failed to unmarshal to parsed alias: %v", err)
+	}
+	*t = ComputeInstanceTemplate(alias)
+	return nil
+}
+
+// MarshalJSON provides a custom JSON marshaller.
+// It is used to merge the original (raw) user JSON definition with the struct.
+func (t *ComputeInstanceTemplate) MarshalJSON() ([]byte, error) {
+	return interfacePair{t.raw, aliasComputeInstanceTemplate(*t)}.MarshalJSON()
+}
+
+// ComputeRegionInstanceGroupManager represents a Terraform GCE regional managed instance group.
+type ComputeRegionInstanceGroupManager struct {
+	Name             string `json:"name"`
+	Project          string `json:"project"`
+	Region           string `json:"region"`
+	BaseInstanceName string `json:"base_instance_name"`
+
+	// Versions are the instance templates of the group.
+	// Templates can be set by the name of a template in the deployment or a terraform reference.
+	Versions   []*InstanceGroupManagerVersion `json:"version"`
+	TargetSize int                            `json:"target_size,omitempty"`
+	NamedPorts []*NamedPort                   `json:"named_port,omitempty"`
+
+	AutoHealingPolicies *AutoHealingPolicies `json:"auto_healing_policies,omitempty"`
+
+	raw json.RawMessage
+}
+
+// InstanceGroupManagerVersion is an instance template used by an instance group manager.
+type InstanceGroupManagerVersion struct {
+	Name             string `json:"name,omitempty"`
+	InstanceTemplate string `json:"instance_template"`
+}
+
+// NamedPort maps a name to a port of the instances of a group, e.g. for use by load balancers.
+type NamedPort struct {
+	Name string `json:"name"`
+	Port int    `json:"port"`
+}
+
+// AutoHealingPolicies recreates instances of a group that fail the health check.
+type AutoHealingPolicies struct {
+	// HealthCheck is the health check's terraform reference or the name of a health check in the deployment.
+	HealthCheck     string `json:"health_check"`
+	InitialDelaySec int    `json:"initial_delay_sec"`
+}
+
+// Init initializes the resource.
+func (m *ComputeRegionInstanceGroupManager) Init(projectID string) error {
+	if m.Name == "" {
+		return errors.New("name must be set")
+	}
+	if m.Region == "" {
+		return errors.New("region must be set")
+	}
+	if m.Project != "" {
+		return fmt.Errorf("project must not be set: %q", m.Project)
+	}
+	m.Project = projectID
+	if m.BaseInstanceName == "" {
+		m.BaseInstanceName = m.Name
+	}
+	for _, v := range m.Versions {
+		v.InstanceTemplate = nameRef("google_compute_instance_template", v.InstanceTemplate, "id")
+	}
+	if p := m.AutoHealingPolicies; p != nil {
+		p.HealthCheck = nameRef("google_compute_health_check", p.HealthCheck, "id")
+	}
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (m *ComputeRegionInstanceGroupManager) Validate() error {
+	if len(m.Versions) == 0 {
+		return fmt.Errorf("instance group manager %q must set at least one version", m.Name)
+	}
+	for _, v := range m.Versions {
+		if v.InstanceTemplate == "" {
+			return fmt.Errorf("instance_template of every version of instance group manager %q must be set", m.Name)
+		}
+	}
+	if m.TargetSize < 0 {
+		return fmt.Errorf("target_size of instance group manager %q must not be negative, got %d", m.Name, m.TargetSize)
+	}
+	for _, p := range m.NamedPorts {
+		if p.Name == "" {
+			return fmt.Errorf("named port %d of instance group manager %q must set a name", p.Port, m.Name)
+		}
+		if p.Port < 1 || p.Port > 65535 {
+			return fmt.Errorf("named port %q of instance group manager %q must be between 1 and 65535, got %d", p.Name, m.Name, p.Port)
+		}
+	}
+	if p := m.AutoHealingPolicies; p != nil {
+		if p.HealthCheck == "" {
+			return fmt.Errorf("auto_healing_policies.health_check of instance group manager %q must be set", m.Name)
+		}
+		if p.InitialDelaySec < 0 || p.InitialDelaySec > 3600 {
+			return fmt.Errorf("auto_healing_policies.initial_delay_sec of instance group manager %q must be between 0 and 3600, got %d", m.Name, p.InitialDelaySec)
+		}
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (m *ComputeRegionInstanceGroupManager) ID() string {
+	return m.Name
+}
+
+// ResourceType returns the resource terraform provider type.
+func (m *ComputeRegionInstanceGroupManager) ResourceType() string {
+	return "google_compute_region_instance_group_manager"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (m *ComputeRegionInstanceGroupManager) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("projects/%s/regions/%s/instanceGroupManagers/%s", m.Project, m.Region, m.Name), nil
+}
+
+// aliasComputeRegionInstanceGroupManager is used to prevent infinite recursion when dealing with json marshaling.
+// https://stackoverflow.com/q/52433467
+type aliasComputeRegionInstanceGroupManager ComputeRegionInstanceGroupManager
+
+// UnmarshalJSON provides a custom JSON unmarshaller.
+// It is used to store the original (raw) user JSON definition,
+// which can have more fields than what is defined in this struct.
+func (m *ComputeRegionInstanceGroupManager) UnmarshalJSON(data []byte) error {
+	var alias aliasComputeRegionInstanceGroupManager
+	if err := unmarshalJSONMany(data, &alias, &alias.raw); err != nil {
+		return fmt.Errorf("failed to unmarshal to parsed alias: %v", err)
+	}
+	*m = ComputeRegionInstanceGroupManager(alias)
+	return nil
+}
+
+// MarshalJSON provides a custom JSON marshaller.
+// It is used to merge the original (raw) user JSON definition with the struct.
+func (m *ComputeRegionInstanceGroupManager) MarshalJSON() ([]byte, error) {
+	return interfacePair{m.raw, aliasComputeRegionInstanceGroupManager(*m)}.MarshalJSON()
+}
+
+// ComputeSSLPolicy represents a Terraform GCE SSL policy for HTTPS and SSL load balancers.
+type ComputeSSLPolicy struct {
+	Name    string `json:"name"`
+	Project string `json:"project"`
+
+	// Profile is one of MODERN, RESTRICTED or CUSTOM. Defaults to MODERN.
+	Profile string `json:"profile"`
+
+	// MinTLSVersion is one of TLS_1_2 or TLS_1_3. Defaults to TLS_1_2.
+	MinTLSVersion string `json:"min_tls_version"`
+
+	// CustomFeatures are the SSL features to enable, and must only be set for the CUSTOM profile.
+	CustomFeatures []string `json:"custom_features,omitempty"`
+
+	raw json.RawMessage
+}
+
+// sslPolicyProfiles are the supported SSL policy profiles.
+// COMPATIBLE is not supported as it allows insecure features.
+var sslPolicyProfiles = map[string]bool{
+	"MODERN":     true,
+	"RESTRICTED": true,
+	"CUSTOM":     true,
+}
+
+// minTLSVersions are the supported minimum TLS versions of an SSL policy.
+var minTLSVersions = map[string]bool{
+	"TLS_1_2": true,
+	"TLS_1_3": true,
+}
+
+// Init initializes the resource.
+func (p *ComputeSSLPolicy) Init(projectID string) error {
+	if p.Name == "" {
+		return errors.New("name must be set")
+	}
+	if p.Project != "" {
+		return fmt.Errorf("project must not be set: %q", p.Project)
+	}
+	p.Project = projectID
+	if p.Profile == "" {
+		p.Profile = "MODERN"
+	}
+	if p.MinTLSVersion == "" {
+		p.MinTLSVersion = "TLS_1_2"
+	}
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (p *ComputeSSLPolicy) Validate() error {
+	if !sslPolicyProfiles[p.Profile] {
+		return fmt.Errorf("invalid profile %q for ssl policy %q: must be one of MODERN, RESTRICTED or CUSTOM", p.Profile, p.Name)
+	}
+	if !minTLSVersions[p.MinTLSVersion] {
+		return fmt.Errorf("invalid min_tls_version %q for ssl policy %q: must be at least TLS_1_2", p.MinTLSVersion, p.Name)
+	}
+	switch {
+	case p.Profile == "CUSTOM" && len(p.CustomFeatures) == 0:
+		return fmt.Errorf("custom_features must be set for ssl policy %q with CUSTOM profile", p.Name)
+	case p.Profile != "CUSTOM" && len(p.CustomFeatures) > 0:
+		return fmt.Errorf("custom_features must only be set for ssl policy %q with CUSTOM profile, got profile %q", p.Name, p.Profile)
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (p *ComputeSSLPolicy) ID() string {
+	return p.Name
+}
+
+// ResourceType returns the resource terraform provider type.
+func (p *ComputeSSLPolicy) ResourceType() string {
+	return "google_compute_ssl_policy"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (p *ComputeSSLPolicy) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("projects/%s/global/sslPolicies/%s", p.Project, p.Name), nil
+}
+
+// aliasComputeSSLPolicy is used to prevent infinite recursion when dealing with json marshaling.
+// https://stackoverflow.com/q/52433467
+type aliasComputeSSLPolicy ComputeSSLPolicy
+
+// UnmarshalJSON provides a custom JSON unmarshaller.
+// It is used to store the original (raw) user JSON definition,
+// which can have more fields than what is defined in this struct.
+func (p *ComputeSSLPolicy) UnmarshalJSON(data []byte) error {
+	var alias aliasComputeSSLPolicy
+	if err := unmarshalJSONMany(data, &alias, &alias.raw); err != nil {
+		return fmt.Errorf("failed to unmarshal to parsed alias: %v", err)
+	}
+	*p = ComputeSSLPolicy(alias)
+	return nil
+}
+
+// MarshalJSON provides a custom JSON marshaller.
+// It is used to merge the original (raw) user JSON definition with the struct.
+func (p *ComputeSSLPolicy) MarshalJSON() ([]byte, error) {
+	return interfacePair{p.raw, aliasComputeSSLPolicy(*p)}.MarshalJSON()
+}
+
+// ComputeTargetHTTPSProxy represents a Terraform GCE target HTTPS proxy of a load balancer.
+type ComputeTargetHTTPSProxy struct {
+	Name    string `json:"name"`
+	Project string `json:"project"`
+
+	// URLMap is the URL map's terraform reference or the name of a URL map in the deployment.
+	URLMap string `json:"url_map"`
+
+	// SSLCertificates are terraform references to the certificates served by the proxy.
+	SSLCertificates []string `json:"ssl_certificates,omitempty"`
+
+	// SSLPolicy is the SSL policy's terraform reference or the name of an SSL policy in the deployment.
+	SSLPolicy string `json:"ssl_policy,omitempty"`
+
+	raw json.RawMessage
+}
+
+// Init initializes the resource.
+func (p *ComputeTargetHTTPSProxy) Init(projectID string) error {
+	if p.Name == "" {
+		return errors.New("name must be set")
+	}
+	if p.URLMap == "" {
+		return errors.New("url_map must be set")
+	}
+	if p.Project != "" {
+		return fmt.Errorf("project must not be set: %q", p.Project)
+	}
+	p.Project = projectID
+	p.URLMap = nameRef("google_compute_url_map", p.URLMap, "id")
+	p.SSLPolicy = nameRef("google_compute_ssl_policy", p.SSLPolicy, "id")
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (p *ComputeTargetHTTPSProxy) Validate() error {
+	if p.SSLPolicy == "" {
+		log.Printf("Target HTTPS proxy %q does not set an ssl_policy: consider a MODERN or RESTRICTED policy so outdated TLS versions are rejected", p.Name)
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (p *ComputeTargetHTTPSProxy) ID() string {
+	return p.Name
+}
+
+// ResourceType returns the resource terraform provider type.
+func (p *ComputeTargetHTTPSProxy) ResourceType() string {
+	return "google_compute_target_https_proxy"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (p *ComputeTargetHTTPSProxy) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("projects/%s/global/targetHttpsProxies/%s", p.Project, p.Name), nil
+}
+
+// aliasComputeTargetHTTPSProxy is used to prevent infinite recursion when dealing with json marshaling.
+// https://stackoverflow.com/q/52433467
+type aliasComputeTargetHTTPSProxy ComputeTargetHTTPSProxy
+
+// UnmarshalJSON provides a custom JSON unmarshaller.
+// It is used to store the original (raw) user JSON definition,
+// which can have more fields than what is defined in this struct.
+func (p *ComputeTargetHTTPSProxy) UnmarshalJSON(data []byte) error {
+	var alias aliasComputeTargetHTTPSProxy
+	if err := unmarshalJSONMany(data, &alias, &alias.raw); err != nil {
+		return fmt.Errorf("failed to unmarshal to parsed alias: %v", err)
+	}
+	*p = ComputeTargetHTTPSProxy(alias)
+	return nil
+}
+
+// MarshalJSON provides a custom JSON marshaller.
+// It is used to merge the original (raw) user JSON definition with the struct.
+func (p *ComputeTargetHTTPSProxy) MarshalJSON() ([]byte, error) {
+	return interfacePair{p.raw, aliasComputeTargetHTTPSProxy(*p)}.MarshalJSON()
+}
+
+// instanceTemplateRefRE matches a terraform reference to an instance template managed in the deployment and captures its name.
+var instanceTemplateRefRE = regexp.MustCompile(`^\$\{google_compute_instance_template\.([a-zA-Z0-9_-]+)\.[a-z_]+\}$`)
+
+// CheckInstanceTemplates checks that the instance templates referenced by the instance group managers of the given resources
+// are templates of the given resources (or their dependent resources).
+// Templates set as literal URLs or data source references are not checked as they are managed outside the deployment.
+func CheckInstanceTemplates(rs []Resource) error {
+	all := withDependentResources(rs)
+	templates := make(map[string]bool)
+	for _, r := range all {
+		if t, ok := r.(*ComputeInstanceTemplate); ok {
+			templates[t.ID()] = true
+		}
+	}
+	var errs []string
+	for _, r := range all {
+		m, ok := r.(*ComputeRegionInstanceGroupManager)
+		if !ok {
+			continue
+		}
+		for _, v := range m.Versions {
+			if match := instanceTemplateRefRE.FindStringSubmatch(v.InstanceTemplate); match != nil && !templates[match[1]] {
+				errs = append(errs, fmt.Sprintf("%s.%s: instance template %q is not in the deployment", m.ResourceType(), m.ID(), match[1]))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid instance templates:\n%v", strings.Join(errs, "\n"))
+	}
+	return nil
+}
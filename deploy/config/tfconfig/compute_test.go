@@ -0,0 +1,874 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestComputeNetwork(t *testing.T) {
+	n := &ComputeNetwork{Name: "foo-network", RoutingMode: "REGIONAL"}
+	if err := n.Init("my-project"); err != nil {
+		t.Fatalf("n.Init = %v", err)
+	}
+
+	want := `{
+  "name": "foo-network",
+  "project": "my-project",
+  "auto_create_subnetworks": false,
+  "routing_mode": "REGIONAL"
+}`
+	checkJSON(t, n, want)
+}
+
+func TestComputeSubnetwork(t *testing.T) {
+	s := &ComputeSubnetwork{
+		Name:        "foo-subnetwork",
+		Network:     "foo-network",
+		IPCidrRange: "10.0.0.0/24",
+		Region:      "us-central1",
+		SecondaryIPRanges: []*SecondaryIPRange{
+			{RangeName: "pods", IPCidrRange: "10.4.0.0/14"},
+			{RangeName: "services", IPCidrRange: "10.8.0.0/20"},
+		},
+	}
+	if err := s.Init("my-project"); err != nil {
+		t.Fatalf("s.Init = %v", err)
+	}
+	if err := s.Validate(); err != nil {
+		t.Fatalf("s.Validate = %v", err)
+	}
+
+	want := `{
+  "name": "foo-subnetwork",
+  "project": "my-project",
+  "network": "${google_compute_network.foo-network.self_link}",
+  "ip_cidr_range": "10.0.0.0/24",
+  "region": "us-central1",
+  "private_ip_google_access": true,
+  "secondary_ip_range": [
+    {"range_name": "pods", "ip_cidr_range": "10.4.0.0/14"},
+    {"range_name": "services", "ip_cidr_range": "10.8.0.0/20"}
+  ]
+}`
+	checkJSON(t, s, want)
+}
+
+func TestComputeSubnetworkValidate(t *testing.T) {
+	cases := []struct {
+		name      string
+		cidr      string
+		secondary string
+		wantErr   bool
+	}{
+		{name: "valid", cidr: "10.0.0.0/24", secondary: "10.4.0.0/14"},
+		{name: "missing_prefix", cidr: "10.0.0.0", secondary: "10.4.0.0/14", wantErr: true},
+		{name: "invalid_address", cidr: "10.0.0.256/24", secondary: "10.4.0.0/14", wantErr: true},
+		{name: "invalid_secondary", cidr: "10.0.0.0/24", secondary: "pods", wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &ComputeSubnetwork{
+				Name:              "foo-subnetwork",
+				IPCidrRange:       tc.cidr,
+				SecondaryIPRanges: []*SecondaryIPRange{{RangeName: "pods", IPCidrRange: tc.secondary}},
+			}
+			err := s.Validate()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("s.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestComputeFirewall(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "ingress_allow",
+			data: `{
+  "name": "allow-https",
+  "network": "${google_compute_network.foo-network.self_link}",
+  "direction": "INGRESS",
+  "allow": [{"protocol": "tcp", "ports": ["443"]}],
+  "source_ranges": ["35.191.0.0/16", "130.211.0.0/22"],
+  "target_tags": ["fhir-proxy"]
+}`,
+			want: `{
+  "name": "allow-https",
+  "project": "my-project",
+  "network": "${google_compute_network.foo-network.self_link}",
+  "direction": "INGRESS",
+  "allow": [{"protocol": "tcp", "ports": ["443"]}],
+  "source_ranges": ["35.191.0.0/16", "130.211.0.0/22"],
+  "target_tags": ["fhir-proxy"]
+}`,
+		},
+		{
+			name: "egress_deny",
+			data: `{
+  "name": "deny-all-egress",
+  "network": "default",
+  "direction": "EGRESS",
+  "priority": 65534,
+  "deny": {"protocol": "all"},
+  "destination_ranges": ["0.0.0.0/0"]
+}`,
+			want: `{
+  "name": "deny-all-egress",
+  "project": "my-project",
+  "network": "default",
+  "direction": "EGRESS",
+  "priority": 65534,
+  "deny": {"protocol": "all"},
+  "destination_ranges": ["0.0.0.0/0"]
+}`,
+		},
+		{
+			name: "dynamic_allow",
+			data: `{
+  "name": "allow-services",
+  "network": "default",
+  "dynamic": {"allow": {"for_each": "${var.allowed_services}"}},
+  "source_ranges": ["10.0.0.0/8"]
+}`,
+			want: `{
+  "name": "allow-services",
+  "project": "my-project",
+  "network": "default",
+  "dynamic": {
+    "allow": {
+      "for_each": "${var.allowed_services}",
+      "content": {
+        "protocol": "${allow.value.protocol}",
+        "ports": "${lookup(allow.value, \"ports\", null)}"
+      }
+    }
+  },
+  "source_ranges": ["10.0.0.0/8"]
+}`,
+		},
+		{
+			name: "dynamic_deny_iterator",
+			data: `{
+  "name": "deny-services",
+  "network": "default",
+  "dynamic": {"deny": {"for_each": "${var.denied_services}", "iterator": "rule", "content": {"protocol": "${rule.value}"}}}
+}`,
+			want: `{
+  "name": "deny-services",
+  "project": "my-project",
+  "network": "default",
+  "dynamic": {
+    "deny": {
+      "for_each": "${var.denied_services}",
+      "iterator": "rule",
+      "content": {
+        "protocol": "${rule.value}"
+      }
+    }
+  }
+}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := new(ComputeFirewall)
+			if err := json.Unmarshal([]byte(tc.data), f); err != nil {
+				t.Fatalf("json.Unmarshal = %v", err)
+			}
+			if err := f.Init("my-project"); err != nil {
+				t.Fatalf("f.Init = %v", err)
+			}
+			if err := f.Validate(); err != nil {
+				t.Fatalf("f.Validate = %v", err)
+			}
+			checkJSON(t, f, tc.want)
+		})
+	}
+}
+
+func TestComputeFirewallValidate(t *testing.T) {
+	rule := firewallRules{{Protocol: "tcp"}}
+	dynamic := &DynamicBlock{ForEach: "${var.rules}", Content: map[string]interface{}{"protocol": "${allow.value.protocol}"}}
+	cases := []struct {
+		name string
+		f    *ComputeFirewall
+	}{
+		{name: "allow_and_deny", f: &ComputeFirewall{Allow: rule, Deny: rule}},
+		{name: "no_rules", f: &ComputeFirewall{}},
+		{name: "invalid_direction", f: &ComputeFirewall{Allow: rule, Direction: "OUTBOUND"}},
+		{name: "static_and_dynamic_allow", f: &ComputeFirewall{Allow: rule, Dynamic: map[string]*DynamicBlock{"allow": dynamic}}},
+		{name: "dynamic_allow_and_deny", f: &ComputeFirewall{Dynamic: map[string]*DynamicBlock{"allow": dynamic, "deny": dynamic}}},
+		{name: "invalid_dynamic_block", f: &ComputeFirewall{Allow: rule, Dynamic: map[string]*DynamicBlock{"log_config": dynamic}}},
+		{name: "dynamic_without_for_each", f: &ComputeFirewall{Dynamic: map[string]*DynamicBlock{"allow": {Content: dynamic.Content}}}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.f.Name = "foo-firewall"
+			if err := tc.f.Validate(); err == nil {
+				t.Error("f.Validate = nil, want error")
+			}
+		})
+	}
+}
+
+func TestComputeGlobalAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address *ComputeGlobalAddress
+		want    string
+	}{
+		{
+			name:    "peering_range",
+			address: &ComputeGlobalAddress{Name: "sql-peering-range", Network: "private", PrefixLength: 20},
+			want: `{
+  "name": "sql-peering-range",
+  "project": "my-project",
+  "network": "${google_compute_network.private.self_link}",
+  "purpose": "VPC_PEERING",
+  "address_type": "INTERNAL",
+  "prefix_length": 20
+}`,
+		},
+		{
+			name:    "external",
+			address: &ComputeGlobalAddress{Name: "api-lb-ip", AddressType: "EXTERNAL"},
+			want: `{
+  "name": "api-lb-ip",
+  "project": "my-project",
+  "address_type": "EXTERNAL"
+}`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.address.Init("my-project"); err != nil {
+				t.Fatalf("a.Init = %v", err)
+			}
+			if err := tc.address.Validate(); err != nil {
+				t.Fatalf("a.Validate = %v", err)
+			}
+			checkJSON(t, tc.address, tc.want)
+		})
+	}
+}
+
+func TestComputeGlobalAddressValidate(t *testing.T) {
+	cases := []struct {
+		name string
+		a    *ComputeGlobalAddress
+	}{
+		{name: "no_network", a: &ComputeGlobalAddress{Purpose: "VPC_PEERING", AddressType: "INTERNAL", PrefixLength: 16}},
+		{name: "no_prefix_length", a: &ComputeGlobalAddress{Purpose: "VPC_PEERING", AddressType: "INTERNAL", Network: "default"}},
+		{name: "prefix_length_too_long", a: &ComputeGlobalAddress{Purpose: "VPC_PEERING", AddressType: "INTERNAL", Network: "default", PrefixLength: 30}},
+		{name: "external_peering", a: &ComputeGlobalAddress{Purpose: "VPC_PEERING", AddressType: "EXTERNAL", Network: "default", PrefixLength: 16}},
+		{name: "invalid_purpose", a: &ComputeGlobalAddress{Purpose: "GCE_ENDPOINT", AddressType: "INTERNAL"}},
+		{name: "invalid_address_type", a: &ComputeGlobalAddress{AddressType: "PRIVATE"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.a.Name = "foo-range"
+			if err := tc.a.Validate(); err == nil {
+				t.Error("a.Validate = nil, want error")
+			}
+		})
+	}
+}
+
+func TestComputeInstance(t *testing.T) {
+	i := &ComputeInstance{
+		Name:        "legacy-job",
+		Zone:        "us-central1-a",
+		MachineType: "n1-standard-2",
+		BootDisk: &ComputeBootDisk{
+			InitializeParams: &DiskInitializeParams{Image: "debian-cloud/debian-11", Size: 50},
+			KMSKeySelfLink:   "${google_kms_crypto_key.disk-key.id}",
+		},
+		NetworkInterfaces: networkInterfaces{{Subnetwork: "private-us-central1"}},
+		ServiceAccount: &ComputeServiceAccount{
+			Email:  "${google_service_account.legacy-job.email}",
+			Scopes: []string{"cloud-platform"},
+		},
+		Labels:   map[string]string{"env": "prod"},
+		Metadata: map[string]string{"enable-oslogin": "TRUE"},
+	}
+	if err := i.Init("my-project"); err != nil {
+		t.Fatalf("i.Init = %v", err)
+	}
+	if got := captureLog(t, func() {
+		if err := i.Validate(); err != nil {
+			t.Fatalf("i.Validate = %v", err)
+		}
+	}); got != "" {
+		t.Errorf("i.Validate logged %q, want no warning", got)
+	}
+
+	want := `{
+  "name": "legacy-job",
+  "project": "my-project",
+  "zone": "us-central1-a",
+  "machine_type": "n1-standard-2",
+  "boot_disk": {
+    "initialize_params": {
+      "image": "debian-cloud/debian-11",
+      "size": 50
+    },
+    "kms_key_self_link": "${google_kms_crypto_key.disk-key.id}"
+  },
+  "network_interface": [{
+    "subnetwork": "${google_compute_subnetwork.private-us-central1.self_link}"
+  }],
+  "service_account": {
+    "email": "${google_service_account.legacy-job.email}",
+    "scopes": ["cloud-platform"]
+  },
+  "labels": {"env": "prod"},
+  "metadata": {"enable-oslogin": "TRUE"},
+  "shielded_instance_config": {
+    "enable_secure_boot": true,
+    "enable_vtpm": true,
+    "enable_integrity_monitoring": true
+  }
+}`
+	checkJSON(t, i, want)
+}
+
+func TestComputeInstanceShieldedInstanceConfig(t *testing.T) {
+	i := &ComputeInstance{
+		Name:                   "legacy-job",
+		Zone:                   "us-central1-a",
+		ShieldedInstanceConfig: &ShieldedInstanceConfig{EnableIntegrityMonitoring: true},
+	}
+	if err := i.Init("my-project"); err != nil {
+		t.Fatalf("i.Init = %v", err)
+	}
+	want := `{
+  "name": "legacy-job",
+  "project": "my-project",
+  "zone": "us-central1-a",
+  "shielded_instance_config": {
+    "enable_secure_boot": false,
+    "enable_vtpm": false,
+    "enable_integrity_monitoring": true
+  }
+}`
+	checkJSON(t, i, want)
+}
+
+func TestComputeInstanceNetworkInterfaceRaw(t *testing.T) {
+	data := `{
+  "name": "bastion",
+  "zone": "us-central1-a",
+  "network_interface": {
+    "subnetwork": "private-us-central1",
+    "network_ip": "10.0.0.2",
+    "alias_ip_range": [{"ip_cidr_range": "/24"}],
+    "access_config": {"public_ptr_domain_name": "bastion.example.com."}
+  }
+}`
+	i := new(ComputeInstance)
+	if err := json.Unmarshal([]byte(data), i); err != nil {
+		t.Fatalf("json.Unmarshal = %v", err)
+	}
+	if err := i.Init("my-project"); err != nil {
+		t.Fatalf("i.Init = %v", err)
+	}
+
+	want := `{
+  "name": "bastion",
+  "project": "my-project",
+  "zone": "us-central1-a",
+  "network_interface": {
+    "subnetwork": "${google_compute_subnetwork.private-us-central1.self_link}",
+    "network_ip": "10.0.0.2",
+    "alias_ip_range": [{"ip_cidr_range": "/24"}],
+    "access_config": {"public_ptr_domain_name": "bastion.example.com."}
+  },
+  "shielded_instance_config": {
+    "enable_secure_boot": true,
+    "enable_vtpm": true,
+    "enable_integrity_monitoring": true
+  }
+}`
+	checkJSON(t, i, want)
+}
+
+func TestComputeInstanceExternalIPWarning(t *testing.T) {
+	data := `{
+  "name": "bastion",
+  "zone": "us-central1-a",
+  "network_interface": {
+    "network": "default",
+    "access_config": {}
+  }
+}`
+	i := new(ComputeInstance)
+	if err := json.Unmarshal([]byte(data), i); err != nil {
+		t.Fatalf("json.Unmarshal = %v", err)
+	}
+	if err := i.Init("my-project"); err != nil {
+		t.Fatalf("i.Init = %v", err)
+	}
+	got := captureLog(t, func() {
+		if err := i.Validate(); err != nil {
+			t.Fatalf("i.Validate = %v", err)
+		}
+	})
+	if !strings.Contains(got, `Instance "bastion" requests an external IP`) {
+		t.Errorf("i.Validate logged %q, want external IP warning", got)
+	}
+}
+
+func TestComputeInstanceTemplateAndGroupManager(t *testing.T) {
+	tmpl := &ComputeInstanceTemplate{
+		NamePrefix:  "worker-",
+		MachineType: "e2-standard-4",
+		Disks: []*ComputeTemplateDisk{{
+			SourceImage:       "debian-cloud/debian-11",
+			DiskSizeGB:        50,
+			Boot:              true,
+			DiskEncryptionKey: &DiskEncryptionKey{KMSKeySelfLink: "${google_kms_crypto_key.disk-key.id}"},
+		}},
+		NetworkInterfaces: networkInterfaces{{Subnetwork: "private-us-central1"}},
+		ServiceAccount: &ComputeServiceAccount{
+			Email:  "${google_service_account.worker.email}",
+			Scopes: []string{"cloud-platform"},
+		},
+		Metadata: map[string]string{"enable-oslogin": "TRUE"},
+	}
+	mig := &ComputeRegionInstanceGroupManager{
+		Name:       "workers",
+		Region:     "us-central1",
+		Versions:   []*InstanceGroupManagerVersion{{InstanceTemplate: "worker"}},
+		TargetSize: 3,
+		NamedPorts: []*NamedPort{{Name: "http", Port: 8080}},
+	}
+	for _, r := range []Resource{tmpl, mig} {
+		if err := r.Init("my-project"); err != nil {
+			t.Fatalf("%v.Init = %v", r.ID(), err)
+		}
+		if err := r.(Validator).Validate(); err != nil {
+			t.Fatalf("%v.Validate = %v", r.ID(), err)
+		}
+	}
+
+	wantTemplate := `{
+  "name_prefix": "worker-",
+  "project": "my-project",
+  "machine_type": "e2-standard-4",
+  "disk": [{
+    "source_image": "debian-cloud/debian-11",
+    "disk_size_gb": 50,
+    "boot": true,
+    "disk_encryption_key": {"kms_key_self_link": "${google_kms_crypto_key.disk-key.id}"}
+  }],
+  "network_interface": [{
+    "subnetwork": "${google_compute_subnetwork.private-us-central1.self_link}"
+  }],
+  "service_account": {
+    "email": "${google_service_account.worker.email}",
+    "scopes": ["cloud-platform"]
+  },
+  "metadata": {"enable-oslogin": "TRUE"},
+  "shielded_instance_config": {
+    "enable_secure_boot": true,
+    "enable_vtpm": true,
+    "enable_integrity_monitoring": true
+  },
+  "lifecycle": {"create_before_destroy": true}
+}`
+	checkJSON(t, tmpl, wantTemplate)
+	if id, err := tmpl.ImportID(nil); err != nil || id != "" {
+		t.Errorf("tmpl.ImportID = %q, %v, want no import for a template with a name prefix", id, err)
+	}
+
+	wantMIG := `{
+  "name": "workers",
+  "project": "my-project",
+  "region": "us-central1",
+  "base_instance_name": "workers",
+  "version": [{"instance_template": "${google_compute_instance_template.worker.id}"}],
+  "target_size": 3,
+  "named_port": [{"name": "http", "port": 8080}]
+}`
+	checkJSON(t, mig, wantMIG)
+
+	if err := CheckInstanceTemplates([]Resource{tmpl, mig}); err != nil {
+		t.Errorf("CheckInstanceTemplates = %v", err)
+	}
+	if err := CheckInstanceTemplates([]Resource{mig}); err == nil {
+		t.Error("CheckInstanceTemplates = nil, want error for missing template")
+	}
+	external := &ComputeRegionInstanceGroupManager{Versions: []*InstanceGroupManagerVersion{
+		{InstanceTemplate: "projects/other-project/global/instanceTemplates/worker"},
+	}}
+	if err := CheckInstanceTemplates([]Resource{external}); err != nil {
+		t.Errorf("CheckInstanceTemplates = %v, want nil for template outside the deployment", err)
+	}
+}
+
+func TestComputeInstanceTemplateName(t *testing.T) {
+	for _, tmpl := range []*ComputeInstanceTemplate{
+		{},
+		{Name: "worker", NamePrefix: "worker-"},
+	} {
+		if err := tmpl.Init("my-project"); err == nil {
+			t.Errorf("tmpl.Init(%+v) = nil, want error", tmpl)
+		}
+	}
+
+	tmpl := &ComputeInstanceTemplate{
+		Name:        "worker",
+		MachineType: "e2-standard-4",
+		Disks:       []*ComputeTemplateDisk{{SourceImage: "debian-cloud/debian-11", Boot: true}},
+	}
+	if err := tmpl.Init("my-project"); err != nil {
+		t.Fatalf("tmpl.Init = %v", err)
+	}
+	got := captureLog(t, func() {
+		if err := tmpl.Validate(); err != nil {
+			t.Fatalf("tmpl.Validate = %v", err)
+		}
+	})
+	if !strings.Contains(got, "consider setting name_prefix") {
+		t.Errorf("tmpl.Validate logged %q, want name_prefix warning", got)
+	}
+	id, err := tmpl.ImportID(nil)
+	if err != nil {
+		t.Fatalf("tmpl.ImportID = %v", err)
+	}
+	if want := "projects/my-project/global/instanceTemplates/worker"; id != want {
+		t.Errorf("tmpl.ImportID = %q, want %q", id, want)
+	}
+}
+
+func TestComputeRegionInstanceGroupManagerAutoHealing(t *testing.T) {
+	data := `{
+  "name": "workers",
+  "region": "us-central1",
+  "base_instance_name": "worker",
+  "version": [{"name": "primary", "instance_template": "${google_compute_instance_template.worker.self_link}"}],
+  "auto_healing_policies": {"health_check": "worker-health", "initial_delay_sec": 300},
+  "wait_for_instances": true
+}`
+	m := new(ComputeRegionInstanceGroupManager)
+	if err := json.Unmarshal([]byte(data), m); err != nil {
+		t.Fatalf("json.Unmarshal = %v", err)
+	}
+	if err := m.Init("my-project"); err != nil {
+		t.Fatalf("m.Init = %v", err)
+	}
+	if err := m.Validate(); err != nil {
+		t.Fatalf("m.Validate = %v", err)
+	}
+
+	want := `{
+  "name": "workers",
+  "project": "my-project",
+  "region": "us-central1",
+  "base_instance_name": "worker",
+  "version": [{"name": "primary", "instance_template": "${google_compute_instance_template.worker.self_link}"}],
+  "auto_healing_policies": {
+    "health_check": "${google_compute_health_check.worker-health.id}",
+    "initial_delay_sec": 300
+  },
+  "wait_for_instances": true
+}`
+	checkJSON(t, m, want)
+}
+
+func TestComputeRegionInstanceGroupManagerValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		m       *ComputeRegionInstanceGroupManager
+		wantErr bool
+	}{
+		{
+			name:    "no_versions",
+			m:       &ComputeRegionInstanceGroupManager{},
+			wantErr: true,
+		},
+		{
+			name: "invalid_named_port",
+			m: &ComputeRegionInstanceGroupManager{
+				Versions:   []*InstanceGroupManagerVersion{{InstanceTemplate: "worker"}},
+				NamedPorts: []*NamedPort{{Name: "http", Port: 70000}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "auto_healing_without_health_check",
+			m: &ComputeRegionInstanceGroupManager{
+				Versions:            []*InstanceGroupManagerVersion{{InstanceTemplate: "worker"}},
+				AutoHealingPolicies: &AutoHealingPolicies{InitialDelaySec: 60},
+			},
+			wantErr: true,
+		},
+		{
+			name: "auto_healing_delay_too_long",
+			m: &ComputeRegionInstanceGroupManager{
+				Versions:            []*InstanceGroupManagerVersion{{InstanceTemplate: "worker"}},
+				AutoHealingPolicies: &AutoHealingPolicies{HealthCheck: "worker-health", InitialDelaySec: 7200},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid",
+			m: &ComputeRegionInstanceGroupManager{
+				Versions:            []*InstanceGroupManagerVersion{{InstanceTemplate: "worker"}},
+				AutoHealingPolicies: &AutoHealingPolicies{HealthCheck: "worker-health", InitialDelaySec: 60},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.m.Name = "workers"
+			tc.m.Region = "us-central1"
+			if err := tc.m.Init("my-project"); err != nil {
+				t.Fatalf("m.Init = %v", err)
+			}
+			err := tc.m.Validate()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("m.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestComputeSSLPolicyAndTargetHTTPSProxy(t *testing.T) {
+	policy := &ComputeSSLPolicy{Name: "api-tls", Profile: "RESTRICTED"}
+	proxy := &ComputeTargetHTTPSProxy{
+		Name:            "api-proxy",
+		URLMap:          "api-lb",
+		SSLCertificates: []string{"${google_compute_managed_ssl_certificate.api.id}"},
+		SSLPolicy:       "api-tls",
+	}
+	for _, r := range []Resource{policy, proxy} {
+		if err := r.Init("my-project"); err != nil {
+			t.Fatalf("%v.Init = %v", r.ID(), err)
+		}
+		if got := captureLog(t, func() {
+			if err := r.(Validator).Validate(); err != nil {
+				t.Fatalf("%v.Validate = %v", r.ID(), err)
+			}
+		}); got != "" {
+			t.Errorf("%v.Validate logged %q, want no warning", r.ID(), got)
+		}
+	}
+
+	checkJSON(t, policy, `{
+  "name": "api-tls",
+  "project": "my-project",
+  "profile": "RESTRICTED",
+  "min_tls_version": "TLS_1_2"
+}`)
+	checkJSON(t, proxy, `{
+  "name": "api-proxy",
+  "project": "my-project",
+  "url_map": "${google_compute_url_map.api-lb.id}",
+  "ssl_certificates": ["${google_compute_managed_ssl_certificate.api.id}"],
+  "ssl_policy": "${google_compute_ssl_policy.api-tls.id}"
+}`)
+}
+
+func TestComputeTargetHTTPSProxyNoSSLPolicyWarning(t *testing.T) {
+	p := &ComputeTargetHTTPSProxy{Name: "api-proxy", URLMap: "${google_compute_url_map.api-lb.id}"}
+	if err := p.Init("my-project"); err != nil {
+		t.Fatalf("p.Init = %v", err)
+	}
+	got := captureLog(t, func() {
+		if err := p.Validate(); err != nil {
+			t.Fatalf("p.Validate = %v", err)
+		}
+	})
+	if !strings.Contains(got, `Target HTTPS proxy "api-proxy" does not set an ssl_policy`) {
+		t.Errorf("p.Validate logged %q, want missing ssl_policy warning", got)
+	}
+}
+
+func TestComputeSSLPolicyValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		p       *ComputeSSLPolicy
+		wantErr bool
+	}{
+		{
+			name: "custom_with_features",
+			p:    &ComputeSSLPolicy{Profile: "CUSTOM", CustomFeatures: []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"}},
+		},
+		{
+			name:    "custom_without_features",
+			p:       &ComputeSSLPolicy{Profile: "CUSTOM"},
+			wantErr: true,
+		},
+		{
+			name:    "modern_with_features",
+			p:       &ComputeSSLPolicy{Profile: "MODERN", CustomFeatures: []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"}},
+			wantErr: true,
+		},
+		{
+			name:    "compatible_profile",
+			p:       &ComputeSSLPolicy{Profile: "COMPATIBLE"},
+			wantErr: true,
+		},
+		{
+			name:    "tls_1_1",
+			p:       &ComputeSSLPolicy{MinTLSVersion: "TLS_1_1"},
+			wantErr: true,
+		},
+		{
+			name: "tls_1_3",
+			p:    &ComputeSSLPolicy{MinTLSVersion: "TLS_1_3"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.p.Name = "api-tls"
+			if err := tc.p.Init("my-project"); err != nil {
+				t.Fatalf("p.Init = %v", err)
+			}
+			err := tc.p.Validate()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("p.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestComputeRouterNAT(t *testing.T) {
+	r := &ComputeRouter{Name: "egress-router", Region: "us-central1", Network: "private"}
+	if err := r.Init("my-project"); err != nil {
+		t.Fatalf("r.Init = %v", err)
+	}
+	wantRouter := `{
+  "name": "egress-router",
+  "project": "my-project",
+  "region": "us-central1",
+  "network": "${google_compute_network.private.self_link}"
+}`
+	checkJSON(t, r, wantRouter)
+
+	n := &ComputeRouterNAT{
+		Name:                          "egress-nat",
+		Region:                        "us-central1",
+		Router:                        r.Name,
+		NatIPAllocateOption:           "AUTO_ONLY",
+		SourceSubnetworkIPRangesToNat: "ALL_SUBNETWORKS_ALL_IP_RANGES",
+		LogConfig:                     &NATLogConfig{Enable: true, Filter: "ERRORS_ONLY"},
+	}
+	if err := n.Init("my-project"); err != nil {
+		t.Fatalf("n.Init = %v", err)
+	}
+	if err := n.Validate(); err != nil {
+		t.Fatalf("n.Validate = %v", err)
+	}
+	wantNAT := `{
+  "name": "egress-nat",
+  "project": "my-project",
+  "region": "us-central1",
+  "router": "${google_compute_router.egress-router.name}",
+  "nat_ip_allocate_option": "AUTO_ONLY",
+  "source_subnetwork_ip_ranges_to_nat": "ALL_SUBNETWORKS_ALL_IP_RANGES",
+  "log_config": {
+    "enable": true,
+    "filter": "ERRORS_ONLY"
+  }
+}`
+	checkJSON(t, n, wantNAT)
+
+	got, err := n.ImportID(nil)
+	if err != nil {
+		t.Fatalf("n.ImportID = %v", err)
+	}
+	if want := "my-project/us-central1/egress-router/egress-nat"; got != want {
+		t.Errorf("n.ImportID = %q, want %q", got, want)
+	}
+}
+
+func TestComputeRouterNATValidate(t *testing.T) {
+	subnets := []*NATSubnetwork{{Name: "private-us-central1", SourceIPRangesToNat: []string{"ALL_IP_RANGES"}}}
+	cases := []struct {
+		name string
+		n    *ComputeRouterNAT
+	}{
+		{name: "no_source_ranges", n: &ComputeRouterNAT{NatIPAllocateOption: "AUTO_ONLY"}},
+		{name: "invalid_source_ranges", n: &ComputeRouterNAT{NatIPAllocateOption: "AUTO_ONLY", SourceSubnetworkIPRangesToNat: "ALL_SUBNETWORKS"}},
+		{name: "list_without_subnetworks", n: &ComputeRouterNAT{NatIPAllocateOption: "AUTO_ONLY", SourceSubnetworkIPRangesToNat: "LIST_OF_SUBNETWORKS"}},
+		{name: "subnetworks_without_list", n: &ComputeRouterNAT{NatIPAllocateOption: "AUTO_ONLY", SourceSubnetworkIPRangesToNat: "ALL_SUBNETWORKS_ALL_IP_RANGES", Subnetworks: subnets}},
+		{name: "manual_without_ips", n: &ComputeRouterNAT{NatIPAllocateOption: "MANUAL_ONLY", SourceSubnetworkIPRangesToNat: "ALL_SUBNETWORKS_ALL_IP_RANGES"}},
+		{name: "auto_with_ips", n: &ComputeRouterNAT{NatIPAllocateOption: "AUTO_ONLY", SourceSubnetworkIPRangesToNat: "ALL_SUBNETWORKS_ALL_IP_RANGES", NatIPs: []string{"${google_compute_address.nat.self_link}"}}},
+		{name: "invalid_log_filter", n: &ComputeRouterNAT{NatIPAllocateOption: "AUTO_ONLY", SourceSubnetworkIPRangesToNat: "ALL_SUBNETWORKS_ALL_IP_RANGES", LogConfig: &NATLogConfig{Enable: true}}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.n.Name = "foo-nat"
+			if err := tc.n.Validate(); err == nil {
+				t.Error("n.Validate = nil, want error")
+			}
+		})
+	}
+}
+
+func TestComputeAddress(t *testing.T) {
+	a := &ComputeAddress{
+		Name:        "ilb-ip",
+		Region:      "us-central1",
+		AddressType: "INTERNAL",
+		Address:     "10.0.0.10",
+		Subnetwork:  "private-us-central1",
+	}
+	if err := a.Init("my-project"); err != nil {
+		t.Fatalf("a.Init = %v", err)
+	}
+	if err := a.Validate(); err != nil {
+		t.Fatalf("a.Validate = %v", err)
+	}
+
+	want := `{
+  "name": "ilb-ip",
+  "project": "my-project",
+  "region": "us-central1",
+  "address_type": "INTERNAL",
+  "address": "10.0.0.10",
+  "subnetwork": "${google_compute_subnetwork.private-us-central1.self_link}"
+}`
+	checkJSON(t, a, want)
+}
+
+func TestComputeAddressValidate(t *testing.T) {
+	cases := []struct {
+		name string
+		a    *ComputeAddress
+	}{
+		{name: "external_with_subnetwork", a: &ComputeAddress{AddressType: "EXTERNAL", Subnetwork: "default"}},
+		{name: "default_type_with_subnetwork", a: &ComputeAddress{Subnetwork: "default"}},
+		{name: "invalid_address_type", a: &ComputeAddress{AddressType: "PRIVATE"}},
+		{name: "invalid_ip", a: &ComputeAddress{AddressType: "INTERNAL", Address: "10.0.0"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.a.Name = "foo-address"
+			if err := tc.a.Validate(); err == nil {
+				t.Error("a.Validate = nil, want error")
+			}
+		})
+	}
+}
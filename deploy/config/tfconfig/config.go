@@ -17,22 +17,155 @@
 package tfconfig
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
 )
 
 // Resource is an interface that must be implemented by all concrete resource implementations.
+// Resources can be managed by a provider other than the default google provider (e.g. google-beta)
+// by setting the terraform "provider" meta-argument, exposed as a Provider field on concrete resources.
+// Aliased providers are referenced as "<name>.<alias>" and must be declared in the full terraform config.
 type Resource interface {
 	Init(projectID string) error
 	ID() string
 	ResourceType() string
 }
 
+// DataResource is implemented by resources that read existing infrastructure through a terraform data source
+// instead of managing it. They are emitted under the top level "data" block rather than "resource".
+type DataResource interface {
+	Resource
+	isDataResource()
+}
+
+// Importer is an optional interface implemented by resources that can be imported to the terraform state if they already exist.
+// ImportID returns the ID terraform expects to import the resource. An empty ID means the resource is not imported.
+type Importer interface {
+	ImportID(runner.Runner) (string, error)
+}
+
+// Validator is an optional interface implemented by resources that can check their configuration once initialized.
+type Validator interface {
+	Validate() error
+}
+
+// depender is implemented by resources that deploy other resources along with themselves.
+type depender interface {
+	DependentResources() []Resource
+}
+
+// withDependentResources returns the given resources along with all their (transitive) dependent resources.
+func withDependentResources(rs []Resource) []Resource {
+	var all []Resource
+	for _, r := range rs {
+		all = append(all, r)
+		if d, ok := r.(depender); ok {
+			all = append(all, withDependentResources(d.DependentResources())...)
+		}
+	}
+	return all
+}
+
+// address returns the terraform address of the resource, e.g. "google_service_account.foo-account".
+func address(r Resource) string {
+	addr := fmt.Sprintf("%s.%s", r.ResourceType(), r.ID())
+	if _, ok := r.(DataResource); ok {
+		addr = "data." + addr
+	}
+	return addr
+}
+
+// CheckDuplicateIDs checks that no two of the given resources (or their dependent resources) share the same terraform address.
+// Resources that merge many members under a hardcoded ID (e.g. ProjectIAMMembers) are allowed as long as there is a single instance of them.
+func CheckDuplicateIDs(rs []Resource) error {
+	counts := make(map[string]int)
+	var dups []string
+	for _, r := range withDependentResources(rs) {
+		addr := address(r)
+		counts[addr]++
+		if counts[addr] == 2 {
+			dups = append(dups, addr)
+		}
+	}
+	if len(dups) > 0 {
+		return fmt.Errorf("found resources with duplicate IDs: %v", strings.Join(dups, ", "))
+	}
+	return nil
+}
+
+// ValidateResources validates the given initialized resources, both individually through Validator
+// and across resources (e.g. checking for duplicate IDs), and logs warnings for risky but valid configurations.
+// It is shared by all callers deploying resources so they run the same checks.
+// Errors are aggregated in a *MultiError so all of them are reported at once.
+func ValidateResources(rs []Resource) error {
+	errs := new(MultiError)
+	for _, r := range rs {
+		if v, ok := r.(Validator); ok {
+			errs.Add(r.ResourceType(), r.ID(), v.Validate())
+		}
+	}
+	errs.Add("", "", CheckDuplicateIDs(rs))
+	errs.Add("", "", CheckDependsOn(rs))
+	errs.Add("", "", CheckInstanceTemplates(rs))
+	errs.Add("", "", CheckIAMBindingConflicts(rs))
+	WarnBigqueryAccessConflicts(rs)
+	WarnFirestoreNativeDatabases(rs)
+	return errs.ErrOrNil()
+}
+
 // invalidIDRE defines the invalid characters not allowed in terraform resource names.
 var invalidIDRE = regexp.MustCompile("[^a-z0-9-_]")
 
+// serviceRE matches the name of a Google API service, e.g. healthcare.googleapis.com.
+var serviceRE = regexp.MustCompile(`^[a-z0-9-]+(\.[a-z0-9-]+)*\.googleapis\.com$`)
+
+// durationRE matches terraform duration strings in seconds with up to nine fractional digits, e.g. "90000s" or "3.5s".
+var durationRE = regexp.MustCompile(`^[0-9]+(\.[0-9]{1,9})?s$`)
+
 // standardizeID replaces all characters not allowed for terraform resource names with underscores.
 // It will also lowercase the name.
 func standardizeID(id string) string {
 	return invalidIDRE.ReplaceAllString(strings.ToLower(id), "_")
 }
+
+// isProviderAlias returns whether the given provider references an aliased provider config, e.g. "google.project_a".
+func isProviderAlias(provider string) bool {
+	return strings.Contains(provider, ".")
+}
+
+// Ref returns the terraform interpolation string referencing the attribute of the given resource,
+// e.g. Ref(sa, "email") returns "${google_service_account.<account id>.email}".
+// The resource ID is used as is since it is also the resource's terraform address, so resources must
+// standardize their ID themselves if it may contain characters not allowed in terraform names.
+// For resources merged into a single for_each resource (e.g. ProjectIAMMembers), the reference points at the merged resource.
+// References to data resources are prefixed with "data.".
+func Ref(r Resource, attr string) string {
+	if _, ok := r.(DataResource); ok {
+		return fmt.Sprintf("${data.%s.%s.%s}", r.ResourceType(), r.ID(), attr)
+	}
+	return fmt.Sprintf("${%s.%s.%s}", r.ResourceType(), r.ID(), attr)
+}
+
+// nameRef returns the terraform interpolation string referencing the attribute of the resource with the given type and name.
+// Names that are already terraform references are returned as is.
+func nameRef(resourceType, name, attr string) string {
+	if name == "" || strings.HasPrefix(name, "${") {
+		return name
+	}
+	return fmt.Sprintf("${%s.%s.%s}", resourceType, name, attr)
+}
+
+// resourceRefRE matches a terraform reference to a resource attribute and captures the resource name.
+var resourceRefRE = regexp.MustCompile(`^\$\{(?:data\.)?[a-z][a-z0-9_]*\.([a-zA-Z0-9_-]+)\.[a-z_]+\}$`)
+
+// refName returns the name of the resource referenced by the given terraform reference.
+// It is the inverse of nameRef: values that are not references are returned as is.
+func refName(ref string) string {
+	if m := resourceRefRE.FindStringSubmatch(ref); m != nil {
+		return m[1]
+	}
+	return ref
+}
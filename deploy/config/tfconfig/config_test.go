@@ -0,0 +1,214 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCheckDuplicateIDs(t *testing.T) {
+	cases := []struct {
+		name    string
+		rs      []Resource
+		wantErr string
+	}{
+		{
+			name: "unique",
+			rs: []Resource{
+				&ServiceAccount{AccountID: "foo-account"},
+				&ServiceAccount{AccountID: "bar-account"},
+				&StorageBucket{Name: "foo-account"},
+			},
+		},
+		{
+			name: "singleton",
+			rs: []Resource{
+				&ProjectIAMMembers{Members: []*ProjectIAMMember{
+					{Role: "roles/viewer", Member: "group:foo@my-domain.com"},
+					{Role: "roles/editor", Member: "group:bar@my-domain.com"},
+				}},
+				&ServiceAccount{AccountID: "foo-account"},
+			},
+		},
+		{
+			name: "duplicate_service_accounts",
+			rs: []Resource{
+				&ServiceAccount{AccountID: "foo-account"},
+				&ServiceAccount{AccountID: "foo-account", DisplayName: "Foo"},
+				&ServiceAccount{AccountID: "foo-account", DisplayName: "Foo 2"},
+			},
+			wantErr: "google_service_account.foo-account",
+		},
+		{
+			name:    "duplicate_singletons",
+			rs:      []Resource{new(ProjectIAMMembers), new(ProjectIAMMembers)},
+			wantErr: "google_project_iam_member.project",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckDuplicateIDs(tc.rs)
+			if gotErr := err != nil; gotErr != (tc.wantErr != "") {
+				t.Fatalf("CheckDuplicateIDs = %v, want error: %t", err, tc.wantErr != "")
+			}
+			if err == nil {
+				return
+			}
+			if got := strings.Count(err.Error(), tc.wantErr); got != 1 {
+				t.Errorf("CheckDuplicateIDs = %v, want error naming %q once", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateResources(t *testing.T) {
+	cases := []struct {
+		name        string
+		rs          []Resource
+		wantErrs    int
+		wantWarning string
+	}{
+		{
+			name: "valid",
+			rs: []Resource{
+				&ServiceAccount{AccountID: "foo-account"},
+				&StorageBucket{Name: "foo-bucket", Location: "US"},
+			},
+		},
+		{
+			name: "resource_and_cross_resource_errors",
+			rs: []Resource{
+				&ServiceAccount{AccountID: "foo-account", Description: strings.Repeat("a", 257)},
+				&ServiceAccount{AccountID: "foo-account"},
+			},
+			wantErrs: 2,
+		},
+		{
+			name: "iam_binding_conflict",
+			rs: []Resource{
+				&ProjectIAMBinding{Role: "roles/viewer", Members: []string{"group:foo@my-domain.com"}},
+				&ProjectIAMMembers{Members: []*ProjectIAMMember{{Role: "roles/viewer", Member: "group:bar@my-domain.com"}}},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "bigquery_access_conflict",
+			rs: []Resource{
+				&BigqueryDataset{DatasetID: "foo_dataset", Location: "US", Accesses: []*Access{{Role: "OWNER", GroupByEmail: "owners@my-domain.com"}}},
+				&BigqueryDatasetIAMMembers{Members: []*BigqueryDatasetIAMMember{{
+					DatasetID: "${google_bigquery_dataset.foo_dataset.dataset_id}",
+					Role:      "roles/bigquery.dataViewer",
+					Member:    "group:readers@my-domain.com",
+				}}},
+			},
+			wantWarning: `Dataset "foo_dataset" sets access and IAM members`,
+		},
+		{
+			name: "firestore_native_databases",
+			rs: []Resource{
+				&FirestoreDatabase{Name: "foo-db", LocationID: "nam5", Type: "FIRESTORE_NATIVE"},
+				&FirestoreDatabase{Name: "bar-db", LocationID: "nam5", Type: "FIRESTORE_NATIVE"},
+			},
+			wantWarning: "Found 2 Firestore databases in native mode",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, r := range tc.rs {
+				if err := r.Init("my-project"); err != nil {
+					t.Fatalf("%s.Init = %v", address(r), err)
+				}
+			}
+			var err error
+			got := captureLog(t, func() { err = ValidateResources(tc.rs) })
+			if !strings.Contains(got, tc.wantWarning) {
+				t.Errorf("ValidateResources logged %q, want warning %q", got, tc.wantWarning)
+			}
+			if tc.wantErrs == 0 {
+				if err != nil {
+					t.Fatalf("ValidateResources = %v, want nil", err)
+				}
+				return
+			}
+			errs, ok := err.(*MultiError)
+			if !ok {
+				t.Fatalf("ValidateResources = %v, want MultiError", err)
+			}
+			if len(errs.Errors) != tc.wantErrs {
+				t.Errorf("ValidateResources = %v, want %d errors", err, tc.wantErrs)
+			}
+		})
+	}
+}
+
+func TestProvider(t *testing.T) {
+	cases := []struct {
+		name         string
+		r            Resource
+		wantProvider string
+	}{
+		{
+			name: "default",
+			r:    &RedisInstance{Name: "foo-cache", Region: "us-central1", MemorySizeGb: 1},
+		},
+		{
+			name:         "beta",
+			r:            &RedisInstance{Name: "foo-cache", Region: "us-central1", MemorySizeGb: 1, Provider: "google-beta"},
+			wantProvider: "google-beta",
+		},
+		{
+			name:         "raw",
+			r:            unmarshalResource(t, &ComputeNetwork{}, `{"name": "foo-network", "provider": "google-beta"}`),
+			wantProvider: "google-beta",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.r.Init("foo-project"); err != nil {
+				t.Fatalf("%T.Init = %v", tc.r, err)
+			}
+			b, err := json.Marshal(tc.r)
+			if err != nil {
+				t.Fatalf("json.Marshal = %v", err)
+			}
+			var got map[string]interface{}
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatalf("json.Unmarshal = %v", err)
+			}
+			provider, ok := got["provider"]
+			if tc.wantProvider == "" && ok {
+				t.Errorf("provider = %v, want unset", provider)
+			}
+			if tc.wantProvider != "" && provider != tc.wantProvider {
+				t.Errorf("provider = %v, want %q", provider, tc.wantProvider)
+			}
+			wantRef := fmt.Sprintf("${%s.%s.name}", tc.r.ResourceType(), tc.r.ID())
+			if got := Ref(tc.r, "name"); got != wantRef {
+				t.Errorf("Ref = %q, want %q", got, wantRef)
+			}
+		})
+	}
+}
+
+func unmarshalResource(t *testing.T, r Resource, data string) Resource {
+	t.Helper()
+	if err := json.Unmarshal([]byte(data), r); err != nil {
+		t.Fatalf("json.Unmarshal = %v", err)
+	}
+	return r
+}
@@ -0,0 +1,155 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
+)
+
+// ContainerCluster represents a Terraform GKE cluster.
+type ContainerCluster struct {
+	Name     string `json:"name"`
+	Project  string `json:"project"`
+	Provider string `json:"provider,omitempty"`
+	Location string `json:"location"`
+
+	// Network and Subnetwork are commonly references to compute resources,
+	// e.g. "${google_compute_network.my-network.self_link}".
+	Network    string `json:"network,omitempty"`
+	Subnetwork string `json:"subnetwork,omitempty"`
+
+	// RemoveDefaultNodePool defaults to true so node pools are managed as separate resources.
+	RemoveDefaultNodePool *bool `json:"remove_default_node_pool,omitempty"`
+	InitialNodeCount      int   `json:"initial_node_count,omitempty"`
+
+	WorkloadIdentityConfig         *WorkloadIdentityConfig         `json:"workload_identity_config,omitempty"`
+	PrivateClusterConfig           *PrivateClusterConfig           `json:"private_cluster_config,omitempty"`
+	MasterAuthorizedNetworksConfig *MasterAuthorizedNetworksConfig `json:"master_authorized_networks_config,omitempty"`
+	ReleaseChannel                 *ReleaseChannel                 `json:"release_channel,omitempty"`
+
+	Timeouts *Timeouts `json:"timeouts,omitempty"`
+}
+
+// WorkloadIdentityConfig configures workload identity for a cluster.
+type WorkloadIdentityConfig struct {
+	// WorkloadPool defaults to {project}.svc.id.goog.
+	WorkloadPool string `json:"workload_pool"`
+}
+
+// PrivateClusterConfig configures a private cluster.
+type PrivateClusterConfig struct {
+	EnablePrivateNodes    bool   `json:"enable_private_nodes"`
+	EnablePrivateEndpoint bool   `json:"enable_private_endpoint,omitempty"`
+	MasterIPV4CIDRBlock   string `json:"master_ipv4_cidr_block,omitempty"`
+}
+
+// MasterAuthorizedNetworksConfig configures the networks allowed to access the cluster master.
+type MasterAuthorizedNetworksConfig struct {
+	CIDRBlocks []*CIDRBlock `json:"cidr_blocks,omitempty"`
+}
+
+// CIDRBlock is a network allowed to access the cluster master.
+type CIDRBlock struct {
+	CIDRBlock   string `json:"cidr_block"`
+	DisplayName string `json:"display_name,omitempty"`
+}
+
+// ReleaseChannel is the release channel the cluster is subscribed to.
+type ReleaseChannel struct {
+	Channel string `json:"channel"`
+}
+
+// releaseChannels are the valid release channels of a cluster.
+var releaseChannels = map[string]bool{
+	"UNSPECIFIED": true,
+	"RAPID":       true,
+	"REGULAR":     true,
+	"STABLE":      true,
+}
+
+// Init initializes the resource.
+func (c *ContainerCluster) Init(projectID string) error {
+	if c.Name == "" {
+		return errors.New("name must be set")
+	}
+	if c.Location == "" {
+		return errors.New("location must be set")
+	}
+	if c.Project != "" {
+		return fmt.Errorf("project must be unset: %v", c.Project)
+	}
+	c.Project = projectID
+	if c.RemoveDefaultNodePool == nil {
+		remove := true
+		c.RemoveDefaultNodePool = &remove
+	}
+	// Terraform requires the default node pool to be created, even if it is removed right after.
+	if *c.RemoveDefaultNodePool && c.InitialNodeCount == 0 {
+		c.InitialNodeCount = 1
+	}
+	if c.WorkloadIdentityConfig != nil && c.WorkloadIdentityConfig.WorkloadPool == "" {
+		c.WorkloadIdentityConfig.WorkloadPool = projectID + ".svc.id.goog"
+	}
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (c *ContainerCluster) Validate() error {
+	if c.Timeouts != nil {
+		if err := c.Timeouts.Validate(); err != nil {
+			return fmt.Errorf("invalid timeouts for cluster %q: %v", c.Name, err)
+		}
+	}
+	if pc := c.PrivateClusterConfig; pc != nil && pc.EnablePrivateNodes {
+		if c.MasterAuthorizedNetworksConfig == nil || len(c.MasterAuthorizedNetworksConfig.CIDRBlocks) == 0 {
+			return fmt.Errorf("private cluster %q must set at least one master authorized network", c.Name)
+		}
+		if pc.MasterIPV4CIDRBlock != "" {
+			if _, _, err := net.ParseCIDR(pc.MasterIPV4CIDRBlock); err != nil {
+				return fmt.Errorf("invalid master_ipv4_cidr_block for cluster %q: %v", c.Name, err)
+			}
+		}
+	}
+	if c.MasterAuthorizedNetworksConfig != nil {
+		for _, b := range c.MasterAuthorizedNetworksConfig.CIDRBlocks {
+			if _, _, err := net.ParseCIDR(b.CIDRBlock); err != nil {
+				return fmt.Errorf("invalid master authorized network for cluster %q: %v", c.Name, err)
+			}
+		}
+	}
+	if c.ReleaseChannel != nil && !releaseChannels[c.ReleaseChannel.Channel] {
+		return fmt.Errorf("invalid release channel %q for cluster %q: must be one of UNSPECIFIED, RAPID, REGULAR or STABLE", c.ReleaseChannel.Channel, c.Name)
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (c *ContainerCluster) ID() string {
+	return c.Name
+}
+
+// ResourceType returns the resource terraform provider type.
+func (*ContainerCluster) ResourceType() string {
+	return "google_container_cluster"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (c *ContainerCluster) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("projects/%s/locations/%s/clusters/%s", c.Project, c.Location, c.Name), nil
+}
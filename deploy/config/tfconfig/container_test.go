@@ -0,0 +1,175 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"testing"
+)
+
+func TestContainerClusterWorkloadIdentity(t *testing.T) {
+	c := &ContainerCluster{
+		Name:                   "processing-cluster",
+		Location:               "us-central1",
+		Network:                "${google_compute_network.private.self_link}",
+		Subnetwork:             "${google_compute_subnetwork.private.self_link}",
+		WorkloadIdentityConfig: &WorkloadIdentityConfig{},
+		PrivateClusterConfig: &PrivateClusterConfig{
+			EnablePrivateNodes:  true,
+			MasterIPV4CIDRBlock: "172.16.0.0/28",
+		},
+		MasterAuthorizedNetworksConfig: &MasterAuthorizedNetworksConfig{
+			CIDRBlocks: []*CIDRBlock{{CIDRBlock: "10.0.0.0/8", DisplayName: "corp"}},
+		},
+		ReleaseChannel: &ReleaseChannel{Channel: "REGULAR"},
+	}
+	if err := c.Init("my-project"); err != nil {
+		t.Fatalf("c.Init = %v", err)
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("c.Validate = %v", err)
+	}
+
+	want := `{
+  "name": "processing-cluster",
+  "project": "my-project",
+  "location": "us-central1",
+  "network": "${google_compute_network.private.self_link}",
+  "subnetwork": "${google_compute_subnetwork.private.self_link}",
+  "remove_default_node_pool": true,
+  "initial_node_count": 1,
+  "workload_identity_config": {
+    "workload_pool": "my-project.svc.id.goog"
+  },
+  "private_cluster_config": {
+    "enable_private_nodes": true,
+    "master_ipv4_cidr_block": "172.16.0.0/28"
+  },
+  "master_authorized_networks_config": {
+    "cidr_blocks": [{
+      "cidr_block": "10.0.0.0/8",
+      "display_name": "corp"
+    }]
+  },
+  "release_channel": {
+    "channel": "REGULAR"
+  }
+}`
+	checkJSON(t, c, want)
+}
+
+func TestContainerClusterKeepDefaultNodePool(t *testing.T) {
+	keep := false
+	c := &ContainerCluster{
+		Name:                   "foo-cluster",
+		Location:               "us-central1-a",
+		RemoveDefaultNodePool:  &keep,
+		WorkloadIdentityConfig: &WorkloadIdentityConfig{WorkloadPool: "other-project.svc.id.goog"},
+	}
+	if err := c.Init("my-project"); err != nil {
+		t.Fatalf("c.Init = %v", err)
+	}
+
+	want := `{
+  "name": "foo-cluster",
+  "project": "my-project",
+  "location": "us-central1-a",
+  "remove_default_node_pool": false,
+  "workload_identity_config": {
+    "workload_pool": "other-project.svc.id.goog"
+  }
+}`
+	checkJSON(t, c, want)
+}
+
+func TestContainerClusterValidate(t *testing.T) {
+	authorized := &MasterAuthorizedNetworksConfig{
+		CIDRBlocks: []*CIDRBlock{{CIDRBlock: "10.0.0.0/8"}},
+	}
+	tests := []struct {
+		name    string
+		cluster *ContainerCluster
+		wantErr bool
+	}{
+		{
+			name:    "public",
+			cluster: &ContainerCluster{Name: "foo-cluster"},
+		},
+		{
+			name: "private_with_authorized_network",
+			cluster: &ContainerCluster{
+				Name:                           "foo-cluster",
+				PrivateClusterConfig:           &PrivateClusterConfig{EnablePrivateNodes: true},
+				MasterAuthorizedNetworksConfig: authorized,
+			},
+		},
+		{
+			name: "private_without_authorized_network",
+			cluster: &ContainerCluster{
+				Name:                 "foo-cluster",
+				PrivateClusterConfig: &PrivateClusterConfig{EnablePrivateNodes: true},
+			},
+			wantErr: true,
+		},
+		{
+			name: "private_with_empty_authorized_networks",
+			cluster: &ContainerCluster{
+				Name:                           "foo-cluster",
+				PrivateClusterConfig:           &PrivateClusterConfig{EnablePrivateNodes: true},
+				MasterAuthorizedNetworksConfig: &MasterAuthorizedNetworksConfig{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid_authorized_network",
+			cluster: &ContainerCluster{
+				Name: "foo-cluster",
+				MasterAuthorizedNetworksConfig: &MasterAuthorizedNetworksConfig{
+					CIDRBlocks: []*CIDRBlock{{CIDRBlock: "10.0.0.0"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid_release_channel",
+			cluster: &ContainerCluster{
+				Name:           "foo-cluster",
+				ReleaseChannel: &ReleaseChannel{Channel: "BETA"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "timeouts",
+			cluster: &ContainerCluster{
+				Name:     "foo-cluster",
+				Timeouts: &Timeouts{Create: "45m", Update: "1h"},
+			},
+		},
+		{
+			name: "invalid_timeouts",
+			cluster: &ContainerCluster{
+				Name:     "foo-cluster",
+				Timeouts: &Timeouts{Delete: "forever"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.cluster.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("c.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"errors"
+)
+
+// DataGoogleProject represents a Terraform google project data source.
+type DataGoogleProject struct {
+	ProjectID string `json:"project_id"`
+}
+
+func (*DataGoogleProject) isDataResource() {}
+
+// Init initializes the resource.
+// The project ID defaults to the deployment's project.
+func (p *DataGoogleProject) Init(projectID string) error {
+	if p.ProjectID == "" {
+		p.ProjectID = projectID
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (p *DataGoogleProject) ID() string {
+	return standardizeID(p.ProjectID)
+}
+
+// ResourceType returns the resource terraform provider type.
+func (p *DataGoogleProject) ResourceType() string {
+	return "google_project"
+}
+
+// DataGoogleServiceAccount represents a Terraform google service account data source.
+type DataGoogleServiceAccount struct {
+	// AccountID can be the account ID, email or unique ID of the service account.
+	AccountID string `json:"account_id"`
+	Project   string `json:"project,omitempty"`
+}
+
+func (*DataGoogleServiceAccount) isDataResource() {}
+
+// Init initializes the resource.
+// The project defaults to the deployment's project.
+func (a *DataGoogleServiceAccount) Init(projectID string) error {
+	if a.AccountID == "" {
+		return errors.New("account_id must be set")
+	}
+	if a.Project == "" {
+		a.Project = projectID
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (a *DataGoogleServiceAccount) ID() string {
+	return standardizeID(a.AccountID)
+}
+
+// ResourceType returns the resource terraform provider type.
+func (a *DataGoogleServiceAccount) ResourceType() string {
+	return "google_service_account"
+}
@@ -0,0 +1,48 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"testing"
+)
+
+func TestDataGoogleProject(t *testing.T) {
+	p := new(DataGoogleProject)
+	if err := p.Init("my-project"); err != nil {
+		t.Fatalf("p.Init = %v", err)
+	}
+	checkJSON(t, p, `{"project_id": "my-project"}`)
+	if got, want := Ref(p, "number"), "${data.google_project.my-project.number}"; got != want {
+		t.Errorf("Ref(p, %q) = %q, want %q", "number", got, want)
+	}
+}
+
+func TestDataGoogleServiceAccount(t *testing.T) {
+	a := &DataGoogleServiceAccount{AccountID: "foo@other-project.iam.gserviceaccount.com", Project: "other-project"}
+	if err := a.Init("my-project"); err != nil {
+		t.Fatalf("a.Init = %v", err)
+	}
+	checkJSON(t, a, `{
+  "account_id": "foo@other-project.iam.gserviceaccount.com",
+  "project": "other-project"
+}`)
+	if got, want := Ref(a, "email"), "${data.google_service_account.foo_other-project_iam_gserviceaccount_com.email}"; got != want {
+		t.Errorf("Ref(a, %q) = %q, want %q", "email", got, want)
+	}
+
+	if err := new(DataGoogleServiceAccount).Init("my-project"); err == nil {
+		t.Error("Init with empty account_id = nil, want error")
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// dependent is implemented by resources that can set terraform's depends_on meta-argument.
+type dependent interface {
+	dependsOn() []string
+}
+
+// dependsOnRE matches the "<type>.<id>" form of depends_on entries, with an optional "data." prefix for data resources.
+var dependsOnRE = regexp.MustCompile(`^(data\.)?[a-z][a-z0-9_]*\.[a-zA-Z0-9_-]+$`)
+
+// moduleDependsOnRE matches depends_on entries referencing a module or a resource within a module,
+// e.g. "module.network" or "module.network.google_compute_network.vpc".
+var moduleDependsOnRE = regexp.MustCompile(`^module\.[a-zA-Z0-9_-]+(\.[a-zA-Z0-9_-]+)*$`)
+
+// DependsOn returns the normalized depends_on entries of the given resource.
+// Resources that cannot set depends_on have no entries.
+func DependsOn(r Resource) []string {
+	d, ok := r.(dependent)
+	if !ok {
+		return nil
+	}
+	return normalizeDependsOn(d.dependsOn())
+}
+
+// normalizeDependsOn returns the depends_on entries with surrounding whitespace removed and duplicates dropped.
+// The order of the first occurrence of each entry is kept.
+func normalizeDependsOn(deps []string) []string {
+	var res []string
+	seen := make(map[string]bool)
+	for _, d := range deps {
+		d = strings.TrimSpace(d)
+		if seen[d] {
+			continue
+		}
+		seen[d] = true
+		res = append(res, d)
+	}
+	return res
+}
+
+// CheckDependsOn checks that the depends_on entries of the given resources are of the form "<type>.<id>"
+// (or reference a module) and reference one of the given resources (or their dependent resources).
+// Data resource and module entries are only checked for their form as they are never part of the given resources.
+// All malformed and dangling entries are listed in the returned error.
+func CheckDependsOn(rs []Resource) error {
+	addrs := make(map[string]bool)
+	for _, r := range withDependentResources(rs) {
+		addrs[address(r)] = true
+	}
+
+	var errs []string
+	for _, r := range rs {
+		d, ok := r.(dependent)
+		if !ok {
+			continue
+		}
+		for _, dep := range normalizeDependsOn(d.dependsOn()) {
+			switch {
+			case moduleDependsOnRE.MatchString(dep):
+				// Modules are never part of the given resources.
+			case !dependsOnRE.MatchString(dep):
+				errs = append(errs, fmt.Sprintf("%s.%s: malformed depends_on entry %q: must be of the form <type>.<id>", r.ResourceType(), r.ID(), dep))
+			case !strings.HasPrefix(dep, "data.") && !addrs[dep]:
+				errs = append(errs, fmt.Sprintf("%s.%s: dangling depends_on entry %q: does not reference a resource in the deployment", r.ResourceType(), r.ID(), dep))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid depends_on entries:\n%v", strings.Join(errs, "\n"))
+	}
+	return nil
+}
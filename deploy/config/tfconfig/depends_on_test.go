@@ -0,0 +1,117 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNormalizeDependsOn(t *testing.T) {
+	got := normalizeDependsOn([]string{" google_storage_bucket.foo-bucket", "google_service_account.foo-account", "google_storage_bucket.foo-bucket "})
+	want := []string{"google_storage_bucket.foo-bucket", "google_service_account.foo-account"}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("normalizeDependsOn differs (-got +want):\n%v", diff)
+	}
+}
+
+func TestDependsOn(t *testing.T) {
+	b := &StorageBucket{Name: "foo-bucket", DependsOn: []string{" google_service_account.foo ", "google_service_account.foo"}}
+	if diff := cmp.Diff([]string{"google_service_account.foo"}, DependsOn(b)); diff != "" {
+		t.Errorf("DependsOn(b) differs (-want +got):\n%v", diff)
+	}
+	if got := DependsOn(&StorageIAMMember{}); got != nil {
+		t.Errorf("DependsOn(m) = %v, want nil", got)
+	}
+}
+
+func TestProjectIAMMembersDependsOnDuplicates(t *testing.T) {
+	ms := &ProjectIAMMembers{
+		Members:   []*ProjectIAMMember{{Role: "roles/viewer", Member: "group:foo@my-domain.com"}},
+		DependsOn: []string{"google_service_account.foo-account", " google_service_account.foo-account"},
+	}
+	if err := ms.Init("my-project"); err != nil {
+		t.Fatalf("ms.Init = %v", err)
+	}
+	checkJSON(t, ms, `{
+  "for_each": {
+    "roles/viewer group:foo@my-domain.com": {
+      "role": "roles/viewer",
+      "member": "group:foo@my-domain.com"
+    }
+  },
+  "project": "my-project",
+  "role": "${each.value.role}",
+  "member": "${each.value.member}",
+  "depends_on": ["google_service_account.foo-account"]
+}`)
+}
+
+func TestCheckDependsOn(t *testing.T) {
+	sa := &ServiceAccount{AccountID: "foo-account"}
+	cases := []struct {
+		name      string
+		dependsOn []string
+		wantErrs  []string
+	}{
+		{
+			name:      "valid",
+			dependsOn: []string{"google_service_account.foo-account", "data.google_project.project"},
+		},
+		{
+			name:      "duplicates",
+			dependsOn: []string{"google_service_account.foo-account", "google_service_account.foo-account "},
+		},
+		{
+			name:      "modules",
+			dependsOn: []string{"module.network", "module.network.google_compute_network.vpc"},
+		},
+		{
+			name:      "dangling",
+			dependsOn: []string{"google_service_account.bar-account"},
+			wantErrs:  []string{`dangling depends_on entry "google_service_account.bar-account"`},
+		},
+		{
+			name:      "malformed",
+			dependsOn: []string{"foo-account"},
+			wantErrs:  []string{`malformed depends_on entry "foo-account"`},
+		},
+		{
+			name:      "multiple",
+			dependsOn: []string{"google_service_account.bar-account", "google_service_account.foo-account.email", "baz"},
+			wantErrs: []string{
+				`dangling depends_on entry "google_service_account.bar-account"`,
+				`malformed depends_on entry "google_service_account.foo-account.email"`,
+				`malformed depends_on entry "baz"`,
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ms := &ProjectIAMMembers{DependsOn: tc.dependsOn}
+			err := CheckDependsOn([]Resource{sa, ms})
+			if gotErr := err != nil; gotErr != (len(tc.wantErrs) > 0) {
+				t.Fatalf("CheckDependsOn = %v, want error: %t", err, len(tc.wantErrs) > 0)
+			}
+			for _, want := range tc.wantErrs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("CheckDependsOn = %v, want error containing %q", err, want)
+				}
+			}
+		})
+	}
+}
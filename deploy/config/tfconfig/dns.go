@@ -0,0 +1,171 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
+)
+
+// DNSManagedZone represents a Terraform DNS managed zone.
+type DNSManagedZone struct {
+	Name     string `json:"name"`
+	Project  string `json:"project"`
+	Provider string `json:"provider,omitempty"`
+
+	// DNSName is the fully qualified DNS name of the zone, which must end with a dot, e.g. "example.com.".
+	DNSName                 string                   `json:"dns_name"`
+	Description             string                   `json:"description,omitempty"`
+	Visibility              string                   `json:"visibility,omitempty"`
+	PrivateVisibilityConfig *PrivateVisibilityConfig `json:"private_visibility_config,omitempty"`
+}
+
+// PrivateVisibilityConfig are the networks a private zone is visible from.
+type PrivateVisibilityConfig struct {
+	Networks []*DNSNetwork `json:"networks"`
+}
+
+// DNSNetwork is a network a private zone is visible from.
+type DNSNetwork struct {
+	// NetworkURL is the self link of the network, e.g. "${google_compute_network.private.self_link}".
+	NetworkURL string `json:"network_url"`
+}
+
+// Init initializes the resource.
+func (z *DNSManagedZone) Init(projectID string) error {
+	if z.Name == "" {
+		return errors.New("name must be set")
+	}
+	if z.Project != "" {
+		return fmt.Errorf("project must be unset: %v", z.Project)
+	}
+	z.Project = projectID
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (z *DNSManagedZone) Validate() error {
+	if !strings.HasSuffix(z.DNSName, ".") {
+		return fmt.Errorf("dns_name %q of zone %q must end with a dot", z.DNSName, z.Name)
+	}
+	switch z.Visibility {
+	case "", "public":
+		if z.PrivateVisibilityConfig != nil {
+			return fmt.Errorf("private_visibility_config must be unset for public zone %q", z.Name)
+		}
+	case "private":
+		if z.PrivateVisibilityConfig == nil || len(z.PrivateVisibilityConfig.Networks) == 0 {
+			return fmt.Errorf("private zone %q must set at least one network in private_visibility_config", z.Name)
+		}
+	default:
+		return fmt.Errorf("invalid visibility %q for zone %q: must be public or private", z.Visibility, z.Name)
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (z *DNSManagedZone) ID() string {
+	return z.Name
+}
+
+// ResourceType returns the resource terraform provider type.
+func (*DNSManagedZone) ResourceType() string {
+	return "google_dns_managed_zone"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (z *DNSManagedZone) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("projects/%s/managedZones/%s", z.Project, z.Name), nil
+}
+
+// DNSRecordSet represents a Terraform DNS record set.
+type DNSRecordSet struct {
+	Name     string `json:"name"`
+	Project  string `json:"project"`
+	Provider string `json:"provider,omitempty"`
+
+	// ManagedZone is the name of the zone of the record set.
+	// Names of zones defined in the same config are turned into references to the zone.
+	ManagedZone string   `json:"managed_zone"`
+	Type        string   `json:"type"`
+	TTL         int      `json:"ttl"`
+	Rrdatas     []string `json:"rrdatas"`
+}
+
+// dnsRecordTypes are the valid types of a DNS record set.
+var dnsRecordTypes = map[string]bool{
+	"A":        true,
+	"AAAA":     true,
+	"CAA":      true,
+	"CNAME":    true,
+	"DNSKEY":   true,
+	"DS":       true,
+	"IPSECKEY": true,
+	"MX":       true,
+	"NAPTR":    true,
+	"NS":       true,
+	"PTR":      true,
+	"SOA":      true,
+	"SPF":      true,
+	"SRV":      true,
+	"SSHFP":    true,
+	"TLSA":     true,
+	"TXT":      true,
+}
+
+// Init initializes the resource.
+func (s *DNSRecordSet) Init(projectID string) error {
+	if s.Name == "" {
+		return errors.New("name must be set")
+	}
+	if s.ManagedZone == "" {
+		return errors.New("managed_zone must be set")
+	}
+	if s.Project != "" {
+		return fmt.Errorf("project must be unset: %v", s.Project)
+	}
+	s.Project = projectID
+	s.ManagedZone = nameRef("google_dns_managed_zone", s.ManagedZone, "name")
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (s *DNSRecordSet) Validate() error {
+	if !strings.HasSuffix(s.Name, ".") {
+		return fmt.Errorf("name %q of record set must end with a dot", s.Name)
+	}
+	if !dnsRecordTypes[s.Type] {
+		return fmt.Errorf("invalid type %q for record set %q", s.Type, s.Name)
+	}
+	if len(s.Rrdatas) == 0 {
+		return fmt.Errorf("rrdatas must be set for record set %q", s.Name)
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+// As record set names are DNS names, the ID is the standardized zone name, name and type, e.g. "internal_api_example_com_a".
+// The zone is part of the ID as the same name can be set in different zones, e.g. in split horizon DNS.
+func (s *DNSRecordSet) ID() string {
+	return standardizeID(refName(s.ManagedZone) + "_" + strings.TrimSuffix(s.Name, ".") + "_" + s.Type)
+}
+
+// ResourceType returns the resource terraform provider type.
+func (*DNSRecordSet) ResourceType() string {
+	return "google_dns_record_set"
+}
@@ -0,0 +1,168 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"testing"
+)
+
+func TestDNSManagedZonePrivate(t *testing.T) {
+	z := &DNSManagedZone{
+		Name:       "internal",
+		DNSName:    "internal.example.com.",
+		Visibility: "private",
+		PrivateVisibilityConfig: &PrivateVisibilityConfig{
+			Networks: []*DNSNetwork{
+				{NetworkURL: "${google_compute_network.private.self_link}"},
+				{NetworkURL: "${google_compute_network.processing.self_link}"},
+			},
+		},
+	}
+	if err := z.Init("my-project"); err != nil {
+		t.Fatalf("z.Init = %v", err)
+	}
+	if err := z.Validate(); err != nil {
+		t.Fatalf("z.Validate = %v", err)
+	}
+
+	want := `{
+  "name": "internal",
+  "project": "my-project",
+  "dns_name": "internal.example.com.",
+  "visibility": "private",
+  "private_visibility_config": {
+    "networks": [
+      {"network_url": "${google_compute_network.private.self_link}"},
+      {"network_url": "${google_compute_network.processing.self_link}"}
+    ]
+  }
+}`
+	checkJSON(t, z, want)
+}
+
+func TestDNSManagedZoneValidate(t *testing.T) {
+	networks := &PrivateVisibilityConfig{Networks: []*DNSNetwork{{NetworkURL: "default"}}}
+	tests := []struct {
+		name    string
+		zone    *DNSManagedZone
+		wantErr bool
+	}{
+		{
+			name: "public",
+			zone: &DNSManagedZone{Name: "foo", DNSName: "example.com."},
+		},
+		{
+			name:    "no_trailing_dot",
+			zone:    &DNSManagedZone{Name: "foo", DNSName: "example.com"},
+			wantErr: true,
+		},
+		{
+			name:    "private_without_networks",
+			zone:    &DNSManagedZone{Name: "foo", DNSName: "example.com.", Visibility: "private"},
+			wantErr: true,
+		},
+		{
+			name:    "public_with_networks",
+			zone:    &DNSManagedZone{Name: "foo", DNSName: "example.com.", Visibility: "public", PrivateVisibilityConfig: networks},
+			wantErr: true,
+		},
+		{
+			name:    "invalid_visibility",
+			zone:    &DNSManagedZone{Name: "foo", DNSName: "example.com.", Visibility: "internal", PrivateVisibilityConfig: networks},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.zone.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("z.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestDNSRecordSet(t *testing.T) {
+	s := &DNSRecordSet{
+		Name:        "api.internal.example.com.",
+		ManagedZone: "internal",
+		Type:        "A",
+		TTL:         300,
+		Rrdatas:     []string{"10.0.0.2", "10.0.0.3"},
+	}
+	if err := s.Init("my-project"); err != nil {
+		t.Fatalf("s.Init = %v", err)
+	}
+	if err := s.Validate(); err != nil {
+		t.Fatalf("s.Validate = %v", err)
+	}
+	if got, want := s.ID(), "internal_api_internal_example_com_a"; got != want {
+		t.Errorf("s.ID() = %q, want %q", got, want)
+	}
+
+	public := &DNSRecordSet{Name: s.Name, ManagedZone: "public", Type: s.Type}
+	if got := public.ID(); got == s.ID() {
+		t.Errorf("public.ID() = %q, want different ID than record set in other zone", got)
+	}
+
+	want := `{
+  "name": "api.internal.example.com.",
+  "project": "my-project",
+  "managed_zone": "${google_dns_managed_zone.internal.name}",
+  "type": "A",
+  "ttl": 300,
+  "rrdatas": ["10.0.0.2", "10.0.0.3"]
+}`
+	checkJSON(t, s, want)
+}
+
+func TestDNSRecordSetValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     *DNSRecordSet
+		wantErr bool
+	}{
+		{
+			name: "txt",
+			set:  &DNSRecordSet{Name: "example.com.", Type: "TXT", Rrdatas: []string{"v=spf1 -all"}},
+		},
+		{
+			name:    "unknown_type",
+			set:     &DNSRecordSet{Name: "example.com.", Type: "ALIAS", Rrdatas: []string{"foo.example.com."}},
+			wantErr: true,
+		},
+		{
+			name:    "lowercase_type",
+			set:     &DNSRecordSet{Name: "example.com.", Type: "a", Rrdatas: []string{"10.0.0.2"}},
+			wantErr: true,
+		},
+		{
+			name:    "no_trailing_dot",
+			set:     &DNSRecordSet{Name: "example.com", Type: "A", Rrdatas: []string{"10.0.0.2"}},
+			wantErr: true,
+		},
+		{
+			name:    "no_rrdatas",
+			set:     &DNSRecordSet{Name: "example.com.", Type: "A"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.set.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("s.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
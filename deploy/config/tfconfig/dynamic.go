@@ -0,0 +1,53 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"errors"
+)
+
+// DynamicBlock represents a Terraform dynamic block, which generates a nested block for each element of a collection.
+// Resources carry them through a "dynamic" field keyed by the name of the nested block they generate.
+// See https://www.terraform.io/docs/configuration/expressions.html#dynamic-blocks.
+type DynamicBlock struct {
+	// ForEach is a terraform expression of the collection to iterate on, e.g. "${var.allowed_ports}".
+	ForEach string `json:"for_each"`
+
+	// Iterator names the variable holding the current element. Terraform defaults it to the name of the block.
+	Iterator string `json:"iterator,omitempty"`
+
+	// Content holds the fields of each generated block, which commonly reference the current element,
+	// e.g. {"protocol": "${allow.value.protocol}"}.
+	Content map[string]interface{} `json:"content"`
+}
+
+// Validate checks that the block is valid.
+func (b *DynamicBlock) Validate() error {
+	if b.ForEach == "" {
+		return errors.New("for_each must be set")
+	}
+	if len(b.Content) == 0 {
+		return errors.New("content must be set")
+	}
+	return nil
+}
+
+// iterator returns the name of the variable holding the current element of the block with the given name.
+func (b *DynamicBlock) iterator(name string) string {
+	if b.Iterator != "" {
+		return b.Iterator
+	}
+	return name
+}
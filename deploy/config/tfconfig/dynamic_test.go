@@ -0,0 +1,82 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"testing"
+)
+
+func TestDynamicBlock(t *testing.T) {
+	cases := []struct {
+		name string
+		b    *DynamicBlock
+		want string
+	}{
+		{
+			name: "default_iterator",
+			b: &DynamicBlock{
+				ForEach: "${var.rules}",
+				Content: map[string]interface{}{"protocol": "${allow.value.protocol}"},
+			},
+			want: `{
+  "for_each": "${var.rules}",
+  "content": {
+    "protocol": "${allow.value.protocol}"
+  }
+}`,
+		},
+		{
+			name: "iterator",
+			b: &DynamicBlock{
+				ForEach:  "${var.rules}",
+				Iterator: "rule",
+				Content:  map[string]interface{}{"protocol": "${rule.value.protocol}", "ports": "${rule.value.ports}"},
+			},
+			want: `{
+  "for_each": "${var.rules}",
+  "iterator": "rule",
+  "content": {
+    "protocol": "${rule.value.protocol}",
+    "ports": "${rule.value.ports}"
+  }
+}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.b.Validate(); err != nil {
+				t.Fatalf("b.Validate = %v", err)
+			}
+			checkJSON(t, tc.b, tc.want)
+		})
+	}
+}
+
+func TestDynamicBlockValidate(t *testing.T) {
+	cases := []struct {
+		name string
+		b    *DynamicBlock
+	}{
+		{name: "no_for_each", b: &DynamicBlock{Content: map[string]interface{}{"protocol": "tcp"}}},
+		{name: "no_content", b: &DynamicBlock{ForEach: "${var.rules}"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.b.Validate(); err == nil {
+				t.Error("b.Validate = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// EssentialContactsContact represents a Terraform Essential Contacts contact of a project.
+// Contacts receive Google Cloud notifications of the categories they are subscribed to.
+type EssentialContactsContact struct {
+	Email string `json:"email"`
+
+	// LanguageTag is the preferred language of notifications, e.g. "en-US". Defaults to "en".
+	LanguageTag                       string   `json:"language_tag"`
+	NotificationCategorySubscriptions []string `json:"notification_category_subscriptions"`
+
+	// Parent is set to the project during Init and should not be set by users.
+	Parent string `json:"parent"`
+}
+
+// contactEmailRE loosely matches an email address.
+var contactEmailRE = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+
+// notificationCategories are the supported notification categories of a contact.
+var notificationCategories = map[string]bool{
+	"ALL":                 true,
+	"SUSPENSION":          true,
+	"SECURITY":            true,
+	"TECHNICAL":           true,
+	"BILLING":             true,
+	"LEGAL":               true,
+	"PRODUCT_UPDATES":     true,
+	"TECHNICAL_INCIDENTS": true,
+}
+
+// Init initializes the resource.
+func (c *EssentialContactsContact) Init(projectID string) error {
+	if c.Email == "" {
+		return errors.New("email must be set")
+	}
+	if c.Parent != "" {
+		return fmt.Errorf("parent must be unset: %v", c.Parent)
+	}
+	c.Parent = "projects/" + projectID
+	if c.LanguageTag == "" {
+		c.LanguageTag = "en"
+	}
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (c *EssentialContactsContact) Validate() error {
+	if !contactEmailRE.MatchString(c.Email) {
+		return fmt.Errorf("invalid email %q of contact", c.Email)
+	}
+	if len(c.NotificationCategorySubscriptions) == 0 {
+		return fmt.Errorf("contact %q must subscribe to at least one notification category", c.Email)
+	}
+	for _, cat := range c.NotificationCategorySubscriptions {
+		if !notificationCategories[cat] {
+			return fmt.Errorf("invalid notification category %q for contact %q: must be one of ALL, SUSPENSION, SECURITY, TECHNICAL, BILLING, LEGAL, PRODUCT_UPDATES or TECHNICAL_INCIDENTS", cat, c.Email)
+		}
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+// It is the standardized email, e.g. "security_my-domain_com" for "security@my-domain.com".
+func (c *EssentialContactsContact) ID() string {
+	return standardizeID(c.Email)
+}
+
+// ResourceType returns the resource terraform provider type.
+func (*EssentialContactsContact) ResourceType() string {
+	return "google_essential_contacts_contact"
+}
@@ -0,0 +1,99 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"testing"
+)
+
+func TestEssentialContactsContact(t *testing.T) {
+	c := &EssentialContactsContact{
+		Email:                             "security@my-domain.com",
+		NotificationCategorySubscriptions: []string{"SECURITY", "TECHNICAL_INCIDENTS"},
+	}
+	if err := c.Init("my-project"); err != nil {
+		t.Fatalf("c.Init = %v", err)
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("c.Validate = %v", err)
+	}
+
+	want := `{
+  "email": "security@my-domain.com",
+  "language_tag": "en",
+  "notification_category_subscriptions": ["SECURITY", "TECHNICAL_INCIDENTS"],
+  "parent": "projects/my-project"
+}`
+	checkJSON(t, c, want)
+
+	if got, want := c.ID(), "security_my-domain_com"; got != want {
+		t.Errorf("c.ID() = %v, want %v", got, want)
+	}
+}
+
+func TestEssentialContactsContactValidate(t *testing.T) {
+	cases := []struct {
+		name       string
+		email      string
+		categories []string
+		wantErr    bool
+	}{
+		{
+			name:       "all",
+			email:      "billing@my-domain.com",
+			categories: []string{"ALL"},
+		},
+		{
+			name:       "unknown_category",
+			email:      "billing@my-domain.com",
+			categories: []string{"BILLING", "INVOICES"},
+			wantErr:    true,
+		},
+		{
+			name:    "no_categories",
+			email:   "billing@my-domain.com",
+			wantErr: true,
+		},
+		{
+			name:       "invalid_email",
+			email:      "billing",
+			categories: []string{"BILLING"},
+			wantErr:    true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &EssentialContactsContact{Email: tc.email, NotificationCategorySubscriptions: tc.categories}
+			if err := c.Init("my-project"); err != nil {
+				t.Fatalf("c.Init = %v", err)
+			}
+			err := c.Validate()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("c.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestEssentialContactsContactInitErrors(t *testing.T) {
+	for _, c := range []*EssentialContactsContact{
+		{NotificationCategorySubscriptions: []string{"ALL"}},
+		{Email: "security@my-domain.com", Parent: "projects/other-project"},
+	} {
+		if err := c.Init("my-project"); err == nil {
+			t.Errorf("c.Init(%+v) = nil, want error", c)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
+)
+
+// EventarcTrigger represents a Terraform Eventarc trigger.
+type EventarcTrigger struct {
+	Name             string               `json:"name"`
+	Project          string               `json:"project"`
+	Provider         string               `json:"provider,omitempty"`
+	Location         string               `json:"location"`
+	MatchingCriteria []*EventarcCriteria  `json:"matching_criteria"`
+	Destination      *EventarcDestination `json:"destination"`
+
+	// ServiceAccount is the email of the service account events are delivered as.
+	// It can be a reference to a service account, e.g. ServiceAccount.Ref("email").
+	ServiceAccount string `json:"service_account,omitempty"`
+}
+
+// EventarcCriteria is a filter events must match, e.g. attribute "type" with value "google.cloud.storage.object.v1.finalized".
+type EventarcCriteria struct {
+	Attribute string `json:"attribute"`
+	Value     string `json:"value"`
+	Operator  string `json:"operator,omitempty"`
+}
+
+// EventarcDestination is where matching events are sent.
+// Exactly one of CloudRunService and Workflow must be set.
+type EventarcDestination struct {
+	CloudRunService *EventarcCloudRunService `json:"cloud_run_service,omitempty"`
+
+	// Workflow is the workflow's terraform reference or full resource name.
+	Workflow string `json:"workflow,omitempty"`
+}
+
+// EventarcCloudRunService is a Cloud Run service events are sent to.
+type EventarcCloudRunService struct {
+	// Service is the name of the service, e.g. CloudRunService.Ref("name").
+	Service string `json:"service"`
+	Path    string `json:"path,omitempty"`
+	Region  string `json:"region,omitempty"`
+}
+
+// Init initializes the resource.
+func (t *EventarcTrigger) Init(projectID string) error {
+	if t.Name == "" {
+		return errors.New("name must be set")
+	}
+	if t.Location == "" {
+		return errors.New("location must be set")
+	}
+	if t.Destination == nil {
+		return errors.New("destination must be set")
+	}
+	if t.Project != "" {
+		return fmt.Errorf("project must be unset: %v", t.Project)
+	}
+	t.Project = projectID
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (t *EventarcTrigger) Validate() error {
+	if len(t.MatchingCriteria) == 0 {
+		return fmt.Errorf("at least one matching criteria must be set for trigger %q", t.Name)
+	}
+	for _, c := range t.MatchingCriteria {
+		if c.Attribute == "" || c.Value == "" {
+			return fmt.Errorf("attribute and value must be set for all matching criteria of trigger %q", t.Name)
+		}
+	}
+	d := t.Destination
+	if (d.CloudRunService == nil) == (d.Workflow == "") {
+		return fmt.Errorf("exactly one of cloud_run_service and workflow must be set in destination of trigger %q", t.Name)
+	}
+	if d.CloudRunService != nil && d.CloudRunService.Service == "" {
+		return fmt.Errorf("service must be set for cloud_run_service destination of trigger %q", t.Name)
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (t *EventarcTrigger) ID() string {
+	return t.Name
+}
+
+// ResourceType returns the resource terraform provider type.
+func (*EventarcTrigger) ResourceType() string {
+	return "google_eventarc_trigger"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (t *EventarcTrigger) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("projects/%s/locations/%s/triggers/%s", t.Project, t.Location, t.Name), nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"testing"
+)
+
+func TestEventarcTrigger(t *testing.T) {
+	svc := &CloudRunService{Name: "fhir-importer"}
+	sa := &ServiceAccount{AccountID: "eventarc-invoker"}
+	tr := &EventarcTrigger{
+		Name:     "fhir-import-on-upload",
+		Location: "us-central1",
+		MatchingCriteria: []*EventarcCriteria{
+			{Attribute: "type", Value: "google.cloud.storage.object.v1.finalized"},
+			{Attribute: "bucket", Value: "my-project-fhir-uploads"},
+		},
+		Destination: &EventarcDestination{
+			CloudRunService: &EventarcCloudRunService{
+				Service: svc.Ref("name"),
+				Path:    "/import",
+				Region:  "us-central1",
+			},
+		},
+		ServiceAccount: sa.Ref("email"),
+	}
+	if err := tr.Init("my-project"); err != nil {
+		t.Fatalf("tr.Init = %v", err)
+	}
+	if err := tr.Validate(); err != nil {
+		t.Fatalf("tr.Validate = %v", err)
+	}
+
+	want := `{
+  "name": "fhir-import-on-upload",
+  "project": "my-project",
+  "location": "us-central1",
+  "matching_criteria": [
+    {"attribute": "type", "value": "google.cloud.storage.object.v1.finalized"},
+    {"attribute": "bucket", "value": "my-project-fhir-uploads"}
+  ],
+  "destination": {
+    "cloud_run_service": {
+      "service": "${google_cloud_run_service.fhir-importer.name}",
+      "path": "/import",
+      "region": "us-central1"
+    }
+  },
+  "service_account": "${google_service_account.eventarc-invoker.email}"
+}`
+	checkJSON(t, tr, want)
+}
+
+func TestEventarcTriggerValidate(t *testing.T) {
+	criteria := []*EventarcCriteria{{Attribute: "type", Value: "google.cloud.storage.object.v1.finalized"}}
+	run := &EventarcCloudRunService{Service: "fhir-importer"}
+	tests := []struct {
+		name    string
+		trigger *EventarcTrigger
+		wantErr bool
+	}{
+		{
+			name: "workflow",
+			trigger: &EventarcTrigger{
+				MatchingCriteria: criteria,
+				Destination:      &EventarcDestination{Workflow: "${google_workflows_workflow.import.id}"},
+			},
+		},
+		{
+			name:    "no_criteria",
+			trigger: &EventarcTrigger{Destination: &EventarcDestination{CloudRunService: run}},
+			wantErr: true,
+		},
+		{
+			name: "criteria_without_value",
+			trigger: &EventarcTrigger{
+				MatchingCriteria: []*EventarcCriteria{{Attribute: "type"}},
+				Destination:      &EventarcDestination{CloudRunService: run},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "no_destination_target",
+			trigger: &EventarcTrigger{MatchingCriteria: criteria, Destination: &EventarcDestination{}},
+			wantErr: true,
+		},
+		{
+			name: "multiple_destination_targets",
+			trigger: &EventarcTrigger{
+				MatchingCriteria: criteria,
+				Destination:      &EventarcDestination{CloudRunService: run, Workflow: "import"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.trigger.Name = "foo-trigger"
+			if err := tc.trigger.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("tr.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
+)
+
+// FirestoreDatabase represents a Terraform Firestore database.
+type FirestoreDatabase struct {
+	// Name is usually "(default)".
+	Name            string `json:"name"`
+	Project         string `json:"project"`
+	Provider        string `json:"provider,omitempty"`
+	LocationID      string `json:"location_id"`
+	Type            string `json:"type"`
+	ConcurrencyMode string `json:"concurrency_mode,omitempty"`
+}
+
+// firestoreConcurrencyModes are the valid concurrency modes of a Firestore database.
+var firestoreConcurrencyModes = map[string]bool{
+	"OPTIMISTIC":                    true,
+	"PESSIMISTIC":                   true,
+	"OPTIMISTIC_WITH_ENTITY_GROUPS": true,
+}
+
+// Init initializes the resource.
+func (d *FirestoreDatabase) Init(projectID string) error {
+	if d.Name == "" {
+		return errors.New("name must be set")
+	}
+	if d.LocationID == "" {
+		return errors.New("location_id must be set")
+	}
+	if d.Project != "" {
+		return fmt.Errorf("project must be unset: %v", d.Project)
+	}
+	d.Project = projectID
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (d *FirestoreDatabase) Validate() error {
+	switch d.Type {
+	case "FIRESTORE_NATIVE", "DATASTORE_MODE":
+	default:
+		return fmt.Errorf("invalid type %q for database %q: must be FIRESTORE_NATIVE or DATASTORE_MODE", d.Type, d.Name)
+	}
+	if d.ConcurrencyMode != "" && !firestoreConcurrencyModes[d.ConcurrencyMode] {
+		return fmt.Errorf("invalid concurrency_mode %q for database %q: must be one of OPTIMISTIC, PESSIMISTIC or OPTIMISTIC_WITH_ENTITY_GROUPS", d.ConcurrencyMode, d.Name)
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+// The name is standardized as it commonly contains characters not allowed in terraform names, e.g. "(default)" becomes "default".
+func (d *FirestoreDatabase) ID() string {
+	return standardizeID(strings.Trim(d.Name, "()"))
+}
+
+// ResourceType returns the resource terraform provider type.
+func (*FirestoreDatabase) ResourceType() string {
+	return "google_firestore_database"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (d *FirestoreDatabase) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("projects/%s/databases/%s", d.Project, d.Name), nil
+}
+
+// WarnFirestoreNativeDatabases logs a warning if the given resources contain more than one Firestore database in native mode,
+// as only one native database is allowed per project.
+func WarnFirestoreNativeDatabases(rs []Resource) {
+	var names []string
+	for _, r := range rs {
+		if d, ok := r.(*FirestoreDatabase); ok && d.Type == "FIRESTORE_NATIVE" {
+			names = append(names, d.Name)
+		}
+	}
+	if len(names) > 1 {
+		log.Printf("Found %d Firestore databases in native mode (%s): only one is allowed per project", len(names), strings.Join(names, ", "))
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFirestoreDatabase(t *testing.T) {
+	d := &FirestoreDatabase{
+		Name:            "(default)",
+		LocationID:      "nam5",
+		Type:            "FIRESTORE_NATIVE",
+		ConcurrencyMode: "OPTIMISTIC",
+	}
+	if err := d.Init("my-project"); err != nil {
+		t.Fatalf("d.Init = %v", err)
+	}
+	if err := d.Validate(); err != nil {
+		t.Fatalf("d.Validate = %v", err)
+	}
+	if got, want := d.ID(), "default"; got != want {
+		t.Errorf("d.ID() = %q, want %q", got, want)
+	}
+
+	want := `{
+  "name": "(default)",
+  "project": "my-project",
+  "location_id": "nam5",
+  "type": "FIRESTORE_NATIVE",
+  "concurrency_mode": "OPTIMISTIC"
+}`
+	checkJSON(t, d, want)
+}
+
+func TestFirestoreDatabaseValidate(t *testing.T) {
+	cases := []struct {
+		name string
+		d    *FirestoreDatabase
+	}{
+		{name: "no_type", d: &FirestoreDatabase{}},
+		{name: "invalid_type", d: &FirestoreDatabase{Type: "NATIVE"}},
+		{name: "invalid_concurrency_mode", d: &FirestoreDatabase{Type: "DATASTORE_MODE", ConcurrencyMode: "LOCKING"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.d.Name = "foo-db"
+			if err := tc.d.Validate(); err == nil {
+				t.Error("d.Validate = nil, want error")
+			}
+		})
+	}
+}
+
+func TestWarnFirestoreNativeDatabases(t *testing.T) {
+	tests := []struct {
+		name     string
+		rs       []Resource
+		wantWarn bool
+	}{
+		{
+			name: "single_native",
+			rs: []Resource{
+				&FirestoreDatabase{Name: "(default)", Type: "FIRESTORE_NATIVE"},
+				&FirestoreDatabase{Name: "legacy", Type: "DATASTORE_MODE"},
+			},
+		},
+		{
+			name: "multiple_native",
+			rs: []Resource{
+				&FirestoreDatabase{Name: "(default)", Type: "FIRESTORE_NATIVE"},
+				&FirestoreDatabase{Name: "other", Type: "FIRESTORE_NATIVE"},
+			},
+			wantWarn: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := captureLog(t, func() { WarnFirestoreNativeDatabases(tc.rs) })
+			if gotWarn := strings.Contains(got, "only one is allowed per project"); gotWarn != tc.wantWarn {
+				t.Errorf("WarnFirestoreNativeDatabases logged %q, want warning: %t", got, tc.wantWarn)
+			}
+		})
+	}
+}
@@ -15,9 +15,12 @@
 package tfconfig
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
 )
@@ -28,6 +31,9 @@ type HealthcareDataset struct {
 	Project  string `json:"project"`
 	Provider string `json:"provider,omitempty"`
 	Location string `json:"location"`
+	TimeZone string `json:"time_zone,omitempty"`
+
+	EncryptionSpec *HealthcareEncryptionSpec `json:"encryption_spec,omitempty"`
 
 	IAMMembers []*HealthcareDatasetIAMMember `json:"_iam_members"`
 
@@ -35,9 +41,17 @@ type HealthcareDataset struct {
 	FHIRStores  []*HealthcareFHIRStore  `json:"_fhir_stores"`
 	HL7V2Stores []*HealthcareHL7V2Store `json:"_hl7_v2_stores"`
 
+	ConsentStores []*HealthcareConsentStore `json:"_consent_stores"`
+
 	raw json.RawMessage
 }
 
+// HealthcareEncryptionSpec is the CMEK configuration of a dataset, which applies to all its stores.
+type HealthcareEncryptionSpec struct {
+	// KMSKeyName is commonly a reference to a crypto key of the deployment, e.g. Ref(key, "id").
+	KMSKeyName string `json:"kms_key_name"`
+}
+
 // Init initializes the resource.
 func (d *HealthcareDataset) Init(projectID string) error {
 	if d.Name == "" {
@@ -46,34 +60,54 @@ func (d *HealthcareDataset) Init(projectID string) error {
 	if d.Location == "" {
 		return errors.New("location must be set")
 	}
+	if d.Project != "" {
+		return fmt.Errorf("project must be unset: %v", d.Project)
+	}
 	d.Project = projectID
 	d.Provider = "google-beta"
 
-	ref := fmt.Sprintf("${google_healthcare_dataset.%s.id}", d.ID())
+	datasetRef := Ref(d, "id")
 	for _, s := range d.DICOMStores {
 		if err := s.Init(projectID); err != nil {
 			return fmt.Errorf("failed to init dicom store %q: %v", s.Name, err)
 		}
-		s.Dataset = ref
+		s.Dataset = datasetRef
 		s.id = fmt.Sprintf("%s_%s", d.Name, s.Name)
 	}
 	for _, s := range d.FHIRStores {
 		if err := s.Init(projectID); err != nil {
 			return fmt.Errorf("failed to init fhir store %q: %v", s.Name, err)
 		}
-		s.Dataset = ref
+		s.Dataset = datasetRef
 		s.id = fmt.Sprintf("%s_%s", d.Name, s.Name)
 	}
 	for _, s := range d.HL7V2Stores {
 		if err := s.Init(projectID); err != nil {
 			return fmt.Errorf("failed to init hl7 v2 store %q: %v", s.Name, err)
 		}
-		s.Dataset = ref
+		s.Dataset = datasetRef
+		s.id = fmt.Sprintf("%s_%s", d.Name, s.Name)
+	}
+	for _, s := range d.ConsentStores {
+		if err := s.Init(projectID); err != nil {
+			return fmt.Errorf("failed to init consent store %q: %v", s.Name, err)
+		}
+		s.Dataset = datasetRef
 		s.id = fmt.Sprintf("%s_%s", d.Name, s.Name)
 	}
 	return nil
 }
 
+// Validate checks that the resource is valid.
+func (d *HealthcareDataset) Validate() error {
+	if d.EncryptionSpec != nil {
+		if err := validateKMSCryptoKeyName(d.EncryptionSpec.KMSKeyName); err != nil {
+			return fmt.Errorf("invalid encryption_spec for dataset %q: %v", d.Name, err)
+		}
+	}
+	return nil
+}
+
 // ID returns the resource unique identifier.
 func (d *HealthcareDataset) ID() string {
 	return d.Name
@@ -100,7 +134,7 @@ func (d *HealthcareDataset) DependentResources() []Resource {
 		}
 		rs = append(rs, &HealthcareDatasetIAMMember{
 			ForEach:   forEach,
-			DatasetID: fmt.Sprintf("${google_healthcare_dataset.%s.id}", d.ID()),
+			DatasetID: Ref(d, "id"),
 			Role:      "${each.value.role}",
 			Member:    "${each.value.member}",
 			Provider:  "google-beta",
@@ -117,6 +151,9 @@ func (d *HealthcareDataset) DependentResources() []Resource {
 	for _, s := range d.HL7V2Stores {
 		rs = append(rs, s)
 	}
+	for _, s := range d.ConsentStores {
+		rs = append(rs, s)
+	}
 	return rs
 }
 
@@ -157,10 +194,21 @@ type HealthcareDatasetIAMMember struct {
 	// DatsetID should be written as a terraform reference to a dataset to create an implicit dependency.
 	DatasetID string `json:"dataset_id,omitempty"`
 
+	DependsOn []string `json:"depends_on,omitempty"`
+
 	// id should be the dataset's literal name.
 	id string
 }
 
+func (m *HealthcareDatasetIAMMember) forEachKey() string {
+	return resourceMemberKey(m.DatasetID, m.Role, m.Member, nil)
+}
+
+// iamCondition returns nil as healthcare dataset IAM members do not support conditions.
+func (m *HealthcareDatasetIAMMember) iamCondition() *IAMCondition {
+	return nil
+}
+
 // Init initializes the resource.
 func (m *HealthcareDatasetIAMMember) Init(string) error {
 	return nil
@@ -176,12 +224,71 @@ func (m *HealthcareDatasetIAMMember) ResourceType() string {
 	return "google_healthcare_dataset_iam_member"
 }
 
+// HealthcareDatasetIAMMembers represents multiple Terraform healthcare dataset IAM members across one or more datasets.
+// It is used to wrap and merge multiple IAM members into a single IAM member when being marshalled to JSON.
+// Unlike the IAM members set on a HealthcareDataset, each member sets the dataset it should be granted on,
+// which allows granting access on datasets not defined in the deployment.
+type HealthcareDatasetIAMMembers struct {
+	Members   []*HealthcareDatasetIAMMember
+	DependsOn []string
+}
+
+// Init initializes the resource.
+// Dataset IAM members do not have a project field so the project ID is unused.
+func (ms *HealthcareDatasetIAMMembers) Init(string) error {
+	for _, m := range ms.Members {
+		if m.DatasetID == "" {
+			return fmt.Errorf("dataset_id must be set for role %q and member %q", m.Role, m.Member)
+		}
+		if err := validateIAMMember(m.Member); err != nil {
+			return fmt.Errorf("invalid member for role %q on dataset %q: %v", m.Role, m.DatasetID, err)
+		}
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+// It is hardcoded to return "datasets" as there is at most one of this resource in a deployment.
+func (ms *HealthcareDatasetIAMMembers) ID() string {
+	return "datasets"
+}
+
+// ResourceType returns the resource terraform provider type.
+func (ms *HealthcareDatasetIAMMembers) ResourceType() string {
+	return "google_healthcare_dataset_iam_member"
+}
+
+func (ms *HealthcareDatasetIAMMembers) dependsOn() []string {
+	return ms.DependsOn
+}
+
+// MarshalJSON marshals the list of members into a single member.
+// The single member will set a for_each block to expand to multiple iam members in the terraform call.
+// See forEachKeys for details on how the for_each keys are built.
+func (ms *HealthcareDatasetIAMMembers) MarshalJSON() ([]byte, error) {
+	return marshalForEachMembers(&HealthcareDatasetIAMMember{
+		DatasetID: "${each.value.dataset_id}",
+		Role:      "${each.value.role}",
+		Member:    "${each.value.member}",
+		Provider:  "google-beta",
+		DependsOn: normalizeDependsOn(ms.DependsOn),
+	}, ms.Members)
+}
+
+// UnmarshalJSON unmarshals the bytes to a list of members.
+func (ms *HealthcareDatasetIAMMembers) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &ms.Members)
+}
+
 // HealthcareDICOMStore represents a terraform DICOM store.
 type HealthcareDICOMStore struct {
 	Name     string `json:"name"`
 	Dataset  string `json:"dataset"`
 	Provider string `json:"provider,omitempty"`
 
+	NotificationConfig *HealthcareNotificationConfig `json:"notification_config,omitempty"`
+	Labels             map[string]string             `json:"labels,omitempty"`
+
 	IAMMembers []*HealthcareDICOMStoreIAMMember `json:"_iam_members"`
 
 	// id should be a literal unique name to use as the terraform resource name.
@@ -194,12 +301,17 @@ func (s *HealthcareDICOMStore) Init(string) error {
 	if s.Name == "" {
 		return errors.New("name must be set")
 	}
+	s.Dataset = datasetRef(s.Dataset)
 	s.Provider = "google-beta"
 	return nil
 }
 
 // ID returns the resource unique identifier.
+// Stores defined in a dataset are prefixed by the dataset name to keep them unique across datasets.
 func (s *HealthcareDICOMStore) ID() string {
+	if s.id == "" {
+		return s.Name
+	}
 	return s.id
 }
 
@@ -247,8 +359,17 @@ func (s *HealthcareDICOMStore) UnmarshalJSON(data []byte) error {
 
 // MarshalJSON provides a custom JSON marshaller.
 // It is used to merge the original (raw) user JSON definition with the struct.
+// The notification config is dropped if it does not set a topic.
 func (s *HealthcareDICOMStore) MarshalJSON() ([]byte, error) {
-	return interfacePair{s.raw, aliasHealthcareDICOMStore(*s)}.MarshalJSON()
+	merged, err := interfacePair{s.raw, aliasHealthcareDICOMStore(*s)}.MergedMap()
+	if err != nil {
+		return nil, err
+	}
+	// Notifications are only sent to a topic, so drop the config entirely if none is set.
+	if s.NotificationConfig == nil || s.NotificationConfig.PubsubTopic == "" {
+		delete(merged, "notification_config")
+	}
+	return json.Marshal(merged)
 }
 
 // HealthcareDICOMStoreIAMMember represents a terraform DICOM store IAM member.
@@ -285,12 +406,111 @@ func (m *HealthcareDICOMStoreIAMMember) ResourceType() string {
 	return "google_healthcare_dicom_store_iam_member"
 }
 
+// HealthcareNotificationConfig represents a terraform healthcare store notification config.
+type HealthcareNotificationConfig struct {
+	PubsubTopic string `json:"pubsub_topic"`
+}
+
+// datasetRef returns a terraform reference to the given dataset.
+func datasetRef(dataset string) string {
+	return nameRef("google_healthcare_dataset", dataset, "id")
+}
+
+// fhirVersions are the supported FHIR store versions.
+var fhirVersions = map[string]bool{
+	"DSTU2": true,
+	"STU3":  true,
+	"R4":    true,
+}
+
+// fhirReferenceParsingModes are the supported modes of parsing references within complex FHIR data types.
+var fhirReferenceParsingModes = map[string]bool{
+	"ENABLED":  true,
+	"DISABLED": true,
+}
+
+// FHIRValidationConfig configures how resources are validated against their profiles when written to a FHIR store.
+type FHIRValidationConfig struct {
+	DisableProfileValidation       bool     `json:"disable_profile_validation,omitempty"`
+	DisableRequiredFieldValidation bool     `json:"disable_required_field_validation,omitempty"`
+	DisableReferenceTypeValidation bool     `json:"disable_reference_type_validation,omitempty"`
+	DisableFHIRPathValidation      bool     `json:"disable_fhirpath_validation,omitempty"`
+	EnabledImplementationGuides    []string `json:"enabled_implementation_guides,omitempty"`
+}
+
+// fhirResourceTypeRE matches FHIR resource type names, e.g. "Patient" or "MedicationRequest".
+var fhirResourceTypeRE = regexp.MustCompile(`^[A-Z][A-Za-z]+$`)
+
+// fhirSchemaTypes are the supported BigQuery schema types of streamed FHIR resources.
+var fhirSchemaTypes = map[string]bool{
+	"ANALYTICS":    true,
+	"ANALYTICS_V2": true,
+	"LOSSLESS":     true,
+}
+
+// FHIRStreamConfig streams the changes of a FHIR store's resources to BigQuery.
+type FHIRStreamConfig struct {
+	// ResourceTypes filters the streamed resources by type. All resources are streamed if empty.
+	ResourceTypes       []string                 `json:"resource_types,omitempty"`
+	BigQueryDestination *FHIRBigQueryDestination `json:"bigquery_destination"`
+}
+
+// FHIRBigQueryDestination is the BigQuery dataset FHIR resources are streamed to.
+type FHIRBigQueryDestination struct {
+	// DatasetURI is of the form "bq://<project>.<dataset>".
+	DatasetURI   string            `json:"dataset_uri"`
+	SchemaConfig *FHIRSchemaConfig `json:"schema_config"`
+}
+
+// FHIRSchemaConfig configures the BigQuery schema of streamed FHIR resources.
+type FHIRSchemaConfig struct {
+	SchemaType              string `json:"schema_type,omitempty"`
+	RecursiveStructureDepth int    `json:"recursive_structure_depth"`
+}
+
+// Validate checks that the stream config is valid.
+func (c *FHIRStreamConfig) Validate() error {
+	for _, t := range c.ResourceTypes {
+		if !fhirResourceTypeRE.MatchString(t) {
+			return fmt.Errorf("invalid resource type %q: must be a FHIR resource type such as Patient", t)
+		}
+	}
+	d := c.BigQueryDestination
+	if d == nil {
+		return errors.New("bigquery_destination must be set")
+	}
+	if d.DatasetURI == "" {
+		return errors.New("bigquery_destination.dataset_uri must be set")
+	}
+	if !strings.HasPrefix(d.DatasetURI, "bq://") || len(d.DatasetURI) == len("bq://") {
+		return fmt.Errorf("invalid dataset_uri %q: must be of the form bq://<project>.<dataset>", d.DatasetURI)
+	}
+	if d.SchemaConfig == nil {
+		return errors.New("bigquery_destination.schema_config must be set")
+	}
+	if t := d.SchemaConfig.SchemaType; t != "" && !fhirSchemaTypes[t] {
+		return fmt.Errorf("invalid schema_type %q: must be one of ANALYTICS, ANALYTICS_V2 or LOSSLESS", t)
+	}
+	return nil
+}
+
 // HealthcareFHIRStore represents a terraform FHIR store.
 type HealthcareFHIRStore struct {
 	Name     string `json:"name"`
 	Dataset  string `json:"dataset"`
 	Provider string `json:"provider,omitempty"`
 
+	Version                     string                        `json:"version,omitempty"`
+	EnableUpdateCreate          bool                          `json:"enable_update_create,omitempty"`
+	DisableReferentialIntegrity bool                          `json:"disable_referential_integrity,omitempty"`
+	NotificationConfig          *HealthcareNotificationConfig `json:"notification_config,omitempty"`
+	StreamConfigs               []*FHIRStreamConfig           `json:"stream_configs,omitempty"`
+	ValidationConfig            *FHIRValidationConfig         `json:"validation_config,omitempty"`
+
+	// ComplexDataTypeReferenceParsing is one of ENABLED or DISABLED.
+	ComplexDataTypeReferenceParsing string            `json:"complex_data_type_reference_parsing,omitempty"`
+	Labels                          map[string]string `json:"labels,omitempty"`
+
 	IAMMembers []*HealthcareFHIRStoreIAMMember `json:"_iam_members"`
 
 	// id should be a literal unique name to use as the terraform resource name.
@@ -303,12 +523,33 @@ func (s *HealthcareFHIRStore) Init(string) error {
 	if s.Name == "" {
 		return errors.New("name must be set")
 	}
+	s.Dataset = datasetRef(s.Dataset)
 	s.Provider = "google-beta"
 	return nil
 }
 
+// Validate checks that the resource is valid.
+func (s *HealthcareFHIRStore) Validate() error {
+	if s.Version != "" && !fhirVersions[s.Version] {
+		return fmt.Errorf("invalid version %q for fhir store %q: must be one of DSTU2, STU3 or R4", s.Version, s.Name)
+	}
+	for i, c := range s.StreamConfigs {
+		if err := c.Validate(); err != nil {
+			return fmt.Errorf("invalid stream config %d for fhir store %q: %v", i, s.Name, err)
+		}
+	}
+	if p := s.ComplexDataTypeReferenceParsing; p != "" && !fhirReferenceParsingModes[p] {
+		return fmt.Errorf("invalid complex_data_type_reference_parsing %q for fhir store %q: must be one of ENABLED or DISABLED", p, s.Name)
+	}
+	return nil
+}
+
 // ID returns the resource unique identifier.
+// Stores defined in a dataset are prefixed by the dataset name to keep them unique across datasets.
 func (s *HealthcareFHIRStore) ID() string {
+	if s.id == "" {
+		return s.Name
+	}
 	return s.id
 }
 
@@ -356,8 +597,17 @@ func (s *HealthcareFHIRStore) UnmarshalJSON(data []byte) error {
 
 // MarshalJSON provides a custom JSON marshaller.
 // It is used to merge the original (raw) user JSON definition with the struct.
+// The notification config is dropped if it does not set a topic.
 func (s *HealthcareFHIRStore) MarshalJSON() ([]byte, error) {
-	return interfacePair{s.raw, aliasHealthcareFHIRStore(*s)}.MarshalJSON()
+	merged, err := interfacePair{s.raw, aliasHealthcareFHIRStore(*s)}.MergedMap()
+	if err != nil {
+		return nil, err
+	}
+	// Notifications are only sent to a topic, so drop the config entirely if none is set.
+	if s.NotificationConfig == nil || s.NotificationConfig.PubsubTopic == "" {
+		delete(merged, "notification_config")
+	}
+	return json.Marshal(merged)
 }
 
 // HealthcareFHIRStoreIAMMember represents a terraform FHIR store IAM member.
@@ -400,6 +650,9 @@ type HealthcareHL7V2Store struct {
 	Dataset  string `json:"dataset"`
 	Provider string `json:"provider,omitempty"`
 
+	ParserConfig        *HL7V2ParserConfig         `json:"parser_config,omitempty"`
+	NotificationConfigs []*HL7V2NotificationConfig `json:"notification_configs,omitempty"`
+
 	IAMMembers []*HealthcareHL7V2StoreIAMMember `json:"_iam_members"`
 
 	// id should be a literal unique name to use as the terraform resource name.
@@ -407,17 +660,53 @@ type HealthcareHL7V2Store struct {
 	raw json.RawMessage
 }
 
+// HL7V2ParserConfig represents a terraform HL7V2 store parser config.
+type HL7V2ParserConfig struct {
+	AllowNullHeader bool   `json:"allow_null_header,omitempty"`
+	Schema          string `json:"schema,omitempty"`
+
+	// SegmentTerminator is the base64 encoded byte to use as the segment terminator.
+	SegmentTerminator string `json:"segment_terminator,omitempty"`
+}
+
+// HL7V2NotificationConfig represents a terraform HL7V2 store notification config.
+// Messages are published to the topic only if they match the filter.
+type HL7V2NotificationConfig struct {
+	PubsubTopic string `json:"pubsub_topic"`
+	Filter      string `json:"filter,omitempty"`
+}
+
 // Init initializes the resource.
 func (s *HealthcareHL7V2Store) Init(string) error {
 	if s.Name == "" {
 		return errors.New("name must be set")
 	}
+	s.Dataset = datasetRef(s.Dataset)
 	s.Provider = "google-beta"
 	return nil
 }
 
+// Validate checks that the resource is valid.
+func (s *HealthcareHL7V2Store) Validate() error {
+	if s.ParserConfig == nil || s.ParserConfig.SegmentTerminator == "" {
+		return nil
+	}
+	b, err := base64.StdEncoding.DecodeString(s.ParserConfig.SegmentTerminator)
+	if err != nil {
+		return fmt.Errorf("segment terminator of hl7 v2 store %q must be base64 encoded: %v", s.Name, err)
+	}
+	if len(b) > 1 {
+		return fmt.Errorf("segment terminator of hl7 v2 store %q must be a single byte, got %d bytes", s.Name, len(b))
+	}
+	return nil
+}
+
 // ID returns the resource unique identifier.
+// Stores defined in a dataset are prefixed by the dataset name to keep them unique across datasets.
 func (s *HealthcareHL7V2Store) ID() string {
+	if s.id == "" {
+		return s.Name
+	}
 	return s.id
 }
 
@@ -502,3 +791,72 @@ func (m *HealthcareHL7V2StoreIAMMember) ID() string {
 func (m *HealthcareHL7V2StoreIAMMember) ResourceType() string {
 	return "google_healthcare_hl7_v2_store_iam_member"
 }
+
+// HealthcareConsentStore represents a terraform consent store.
+type HealthcareConsentStore struct {
+	Name     string `json:"name"`
+	Dataset  string `json:"dataset"`
+	Provider string `json:"provider,omitempty"`
+
+	EnableConsentCreateOnUpdate bool              `json:"enable_consent_create_on_update,omitempty"`
+	DefaultConsentTTL           string            `json:"default_consent_ttl,omitempty"`
+	Labels                      map[string]string `json:"labels,omitempty"`
+
+	// id should be a literal unique name to use as the terraform resource name.
+	id  string
+	raw json.RawMessage
+}
+
+// Init initializes the resource.
+func (s *HealthcareConsentStore) Init(string) error {
+	if s.Name == "" {
+		return errors.New("name must be set")
+	}
+	s.Dataset = datasetRef(s.Dataset)
+	s.Provider = "google-beta"
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (s *HealthcareConsentStore) Validate() error {
+	if s.DefaultConsentTTL != "" && !durationRE.MatchString(s.DefaultConsentTTL) {
+		return fmt.Errorf("default consent TTL %q of consent store %q must be a duration in seconds, e.g. \"90000s\"", s.DefaultConsentTTL, s.Name)
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+// Stores defined in a dataset are prefixed by the dataset name to keep them unique across datasets.
+func (s *HealthcareConsentStore) ID() string {
+	if s.id == "" {
+		return s.Name
+	}
+	return s.id
+}
+
+// ResourceType returns the resource terraform provider type.
+func (*HealthcareConsentStore) ResourceType() string {
+	return "google_healthcare_consent_store"
+}
+
+// aliasHealthcareConsentStore is used to prevent infinite recursion when dealing with json marshaling.
+// https://stackoverflow.com/q/52433467
+type aliasHealthcareConsentStore HealthcareConsentStore
+
+// UnmarshalJSON provides a custom JSON unmarshaller.
+// It is used to store the original (raw) user JSON definition,
+// which can have more fields than what is defined in this struct.
+func (s *HealthcareConsentStore) UnmarshalJSON(data []byte) error {
+	var alias aliasHealthcareConsentStore
+	if err := unmarshalJSONMany(data, &alias, &alias.raw); err != nil {
+		return fmt.Errorf("failed to unmarshal to parsed alias: %v", err)
+	}
+	*s = HealthcareConsentStore(alias)
+	return nil
+}
+
+// MarshalJSON provides a custom JSON marshaller.
+// It is used to merge the original (raw) user JSON definition with the struct.
+func (s *HealthcareConsentStore) MarshalJSON() ([]byte, error) {
+	return interfacePair{s.raw, aliasHealthcareConsentStore(*s)}.MarshalJSON()
+}
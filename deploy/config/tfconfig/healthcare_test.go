@@ -0,0 +1,619 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestHealthcareDataset(t *testing.T) {
+	d := &HealthcareDataset{
+		Name:     "foo-dataset",
+		Location: "us-central1",
+		TimeZone: "America/New_York",
+	}
+	if err := d.Init("my-project"); err != nil {
+		t.Fatalf("d.Init = %v", err)
+	}
+
+	want := `{
+  "name": "foo-dataset",
+  "project": "my-project",
+  "provider": "google-beta",
+  "location": "us-central1",
+  "time_zone": "America/New_York"
+}`
+	checkJSON(t, d, want)
+
+	if got, want := Ref(d, "id"), "${google_healthcare_dataset.foo-dataset.id}"; got != want {
+		t.Errorf("Ref(d, %q) = %v, want %v", "id", got, want)
+	}
+}
+
+func TestHealthcareDatasetEncryptionSpec(t *testing.T) {
+	key := &KMSCryptoKey{Name: "dataset-key"}
+	d := &HealthcareDataset{
+		Name:           "foo-dataset",
+		Location:       "us-central1",
+		EncryptionSpec: &HealthcareEncryptionSpec{KMSKeyName: Ref(key, "id")},
+	}
+	if err := d.Init("my-project"); err != nil {
+		t.Fatalf("d.Init = %v", err)
+	}
+	if err := d.Validate(); err != nil {
+		t.Fatalf("d.Validate = %v", err)
+	}
+
+	want := `{
+  "name": "foo-dataset",
+  "project": "my-project",
+  "provider": "google-beta",
+  "location": "us-central1",
+  "encryption_spec": {
+    "kms_key_name": "${google_kms_crypto_key.dataset-key.id}"
+  }
+}`
+	checkJSON(t, d, want)
+}
+
+func TestHealthcareDatasetValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		spec    *HealthcareEncryptionSpec
+		wantErr bool
+	}{
+		{name: "no_encryption_spec"},
+		{name: "reference", spec: &HealthcareEncryptionSpec{KMSKeyName: "${google_kms_crypto_key.dataset-key.id}"}},
+		{name: "full_name", spec: &HealthcareEncryptionSpec{KMSKeyName: "projects/my-project/locations/us-central1/keyRings/foo-ring/cryptoKeys/dataset-key"}},
+		{name: "empty", spec: &HealthcareEncryptionSpec{}, wantErr: true},
+		{name: "key_ring", spec: &HealthcareEncryptionSpec{KMSKeyName: "projects/my-project/locations/us-central1/keyRings/foo-ring"}, wantErr: true},
+		{name: "key_version", spec: &HealthcareEncryptionSpec{KMSKeyName: "projects/my-project/locations/us-central1/keyRings/foo-ring/cryptoKeys/dataset-key/cryptoKeyVersions/1"}, wantErr: true},
+		{name: "bare_name", spec: &HealthcareEncryptionSpec{KMSKeyName: "dataset-key"}, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := &HealthcareDataset{Name: "foo-dataset", Location: "us-central1", EncryptionSpec: tc.spec}
+			if err := d.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("d.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestHealthcareDatasetInitErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		d    *HealthcareDataset
+	}{
+		{
+			name: "no_name",
+			d:    &HealthcareDataset{Location: "us-central1"},
+		},
+		{
+			name: "no_location",
+			d:    &HealthcareDataset{Name: "foo-dataset"},
+		},
+		{
+			name: "project_set",
+			d:    &HealthcareDataset{Name: "foo-dataset", Location: "us-central1", Project: "other-project"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.d.Init("my-project"); err == nil {
+				t.Error("d.Init = nil, want error")
+			}
+		})
+	}
+}
+
+func TestHealthcareFHIRStoreValidate(t *testing.T) {
+	cases := []struct {
+		version string
+		wantErr bool
+	}{
+		{version: ""},
+		{version: "DSTU2"},
+		{version: "STU3"},
+		{version: "R4"},
+		{version: "R5", wantErr: true},
+		{version: "r4", wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.version, func(t *testing.T) {
+			s := &HealthcareFHIRStore{Name: "foo-store", Version: tc.version}
+			err := s.Validate()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("s.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestHealthcareFHIRStoreStreamConfigs(t *testing.T) {
+	data := `{
+  "name": "foo-store",
+  "dataset": "foo-dataset",
+  "version": "R4",
+  "stream_configs": [{
+    "resource_types": ["Patient", "Observation"],
+    "bigquery_destination": {
+      "dataset_uri": "bq://my-project.fhir_analytics",
+      "schema_config": {"schema_type": "ANALYTICS_V2", "recursive_structure_depth": 3}
+    }
+  }]
+}`
+	s := new(HealthcareFHIRStore)
+	if err := json.Unmarshal([]byte(data), s); err != nil {
+		t.Fatalf("json.Unmarshal = %v", err)
+	}
+	if err := s.Init("my-project"); err != nil {
+		t.Fatalf("s.Init = %v", err)
+	}
+	if err := s.Validate(); err != nil {
+		t.Fatalf("s.Validate = %v", err)
+	}
+
+	want := `{
+  "name": "foo-store",
+  "dataset": "${google_healthcare_dataset.foo-dataset.id}",
+  "provider": "google-beta",
+  "version": "R4",
+  "stream_configs": [{
+    "resource_types": ["Patient", "Observation"],
+    "bigquery_destination": {
+      "dataset_uri": "bq://my-project.fhir_analytics",
+      "schema_config": {
+        "schema_type": "ANALYTICS_V2",
+        "recursive_structure_depth": 3
+      }
+    }
+  }]
+}`
+	checkJSON(t, s, want)
+}
+
+func TestHealthcareFHIRStoreValidationConfig(t *testing.T) {
+	s := &HealthcareFHIRStore{
+		Name:    "foo-store",
+		Dataset: "foo-dataset",
+		Version: "R4",
+		ValidationConfig: &FHIRValidationConfig{
+			DisableProfileValidation:    true,
+			EnabledImplementationGuides: []string{"http://hl7.org/fhir/us/core/ImplementationGuide/hl7.fhir.us.core"},
+		},
+		ComplexDataTypeReferenceParsing: "ENABLED",
+	}
+	if err := s.Init("my-project"); err != nil {
+		t.Fatalf("s.Init = %v", err)
+	}
+	if err := s.Validate(); err != nil {
+		t.Fatalf("s.Validate = %v", err)
+	}
+
+	want := `{
+  "name": "foo-store",
+  "dataset": "${google_healthcare_dataset.foo-dataset.id}",
+  "provider": "google-beta",
+  "version": "R4",
+  "validation_config": {
+    "disable_profile_validation": true,
+    "enabled_implementation_guides": ["http://hl7.org/fhir/us/core/ImplementationGuide/hl7.fhir.us.core"]
+  },
+  "complex_data_type_reference_parsing": "ENABLED"
+}`
+	checkJSON(t, s, want)
+
+	s.ComplexDataTypeReferenceParsing = "PARTIAL"
+	if err := s.Validate(); err == nil {
+		t.Error("s.Validate = nil, want error for invalid complex_data_type_reference_parsing")
+	}
+}
+
+func TestHealthcareFHIRStoreLabels(t *testing.T) {
+	s := &HealthcareFHIRStore{
+		Name:    "foo-store",
+		Dataset: "${google_healthcare_dataset.foo-dataset.id}",
+		Labels: map[string]string{
+			"team":        "clinical",
+			"environment": "prod",
+			"cost-center": "records",
+		},
+	}
+	if err := s.Init("my-project"); err != nil {
+		t.Fatalf("s.Init = %v", err)
+	}
+
+	want := `{"dataset":"${google_healthcare_dataset.foo-dataset.id}",` +
+		`"labels":{"cost-center":"records","environment":"prod","team":"clinical"},` +
+		`"name":"foo-store","provider":"google-beta"}`
+	for i := 0; i < 5; i++ {
+		b, err := json.Marshal(s)
+		if err != nil {
+			t.Fatalf("json.Marshal = %v", err)
+		}
+		if got := string(b); got != want {
+			t.Errorf("json.Marshal = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestFHIRStreamConfigValidate(t *testing.T) {
+	schema := &FHIRSchemaConfig{RecursiveStructureDepth: 2}
+	cases := []struct {
+		name    string
+		c       *FHIRStreamConfig
+		wantErr bool
+	}{
+		{
+			name: "all_resource_types",
+			c:    &FHIRStreamConfig{BigQueryDestination: &FHIRBigQueryDestination{DatasetURI: "bq://my-project.fhir", SchemaConfig: schema}},
+		},
+		{
+			name:    "no_destination",
+			c:       &FHIRStreamConfig{},
+			wantErr: true,
+		},
+		{
+			name:    "empty_dataset_uri",
+			c:       &FHIRStreamConfig{BigQueryDestination: &FHIRBigQueryDestination{SchemaConfig: schema}},
+			wantErr: true,
+		},
+		{
+			name:    "dataset_uri_without_scheme",
+			c:       &FHIRStreamConfig{BigQueryDestination: &FHIRBigQueryDestination{DatasetURI: "my-project.fhir", SchemaConfig: schema}},
+			wantErr: true,
+		},
+		{
+			name:    "no_schema_config",
+			c:       &FHIRStreamConfig{BigQueryDestination: &FHIRBigQueryDestination{DatasetURI: "bq://my-project.fhir"}},
+			wantErr: true,
+		},
+		{
+			name: "invalid_schema_type",
+			c: &FHIRStreamConfig{BigQueryDestination: &FHIRBigQueryDestination{
+				DatasetURI:   "bq://my-project.fhir",
+				SchemaConfig: &FHIRSchemaConfig{SchemaType: "FLAT", RecursiveStructureDepth: 2},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "invalid_resource_type",
+			c: &FHIRStreamConfig{
+				ResourceTypes:       []string{"Patient", "patient"},
+				BigQueryDestination: &FHIRBigQueryDestination{DatasetURI: "bq://my-project.fhir", SchemaConfig: schema},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &HealthcareFHIRStore{Name: "foo-store", StreamConfigs: []*FHIRStreamConfig{tc.c}}
+			if err := s.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("s.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestHealthcareFHIRStoreNotificationConfig(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "topic",
+			data: `{
+  "name": "foo-store",
+  "dataset": "foo-dataset",
+  "version": "R4",
+  "notification_config": {"pubsub_topic": "${google_pubsub_topic.foo-topic.id}"}
+}`,
+			want: `{
+  "name": "foo-store",
+  "dataset": "${google_healthcare_dataset.foo-dataset.id}",
+  "provider": "google-beta",
+  "version": "R4",
+  "notification_config": {"pubsub_topic": "${google_pubsub_topic.foo-topic.id}"}
+}`,
+		},
+		{
+			name: "empty_topic",
+			data: `{
+  "name": "foo-store",
+  "dataset": "foo-dataset",
+  "version": "R4",
+  "notification_config": {"pubsub_topic": ""}
+}`,
+			want: `{
+  "name": "foo-store",
+  "dataset": "${google_healthcare_dataset.foo-dataset.id}",
+  "provider": "google-beta",
+  "version": "R4"
+}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := new(HealthcareFHIRStore)
+			if err := json.Unmarshal([]byte(tc.data), s); err != nil {
+				t.Fatalf("json.Unmarshal = %v", err)
+			}
+			if err := s.Init("my-project"); err != nil {
+				t.Fatalf("s.Init = %v", err)
+			}
+			checkJSON(t, s, tc.want)
+		})
+	}
+}
+
+func TestHealthcareDICOMStore(t *testing.T) {
+	s := &HealthcareDICOMStore{
+		Name:    "foo-store",
+		Dataset: "${google_healthcare_dataset.foo-dataset.id}",
+		Labels: map[string]string{
+			"team":        "imaging",
+			"environment": "prod",
+			"cost-center": "radiology",
+		},
+	}
+	if err := s.Init("my-project"); err != nil {
+		t.Fatalf("s.Init = %v", err)
+	}
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("json.Marshal = %v", err)
+	}
+	want := `{"dataset":"${google_healthcare_dataset.foo-dataset.id}",` +
+		`"labels":{"cost-center":"radiology","environment":"prod","team":"imaging"},` +
+		`"name":"foo-store","provider":"google-beta"}`
+	if got := string(b); got != want {
+		t.Errorf("json.Marshal = %v, want %v", got, want)
+	}
+}
+
+func TestHealthcareDICOMStoreNotificationConfig(t *testing.T) {
+	cases := []struct {
+		name   string
+		config *HealthcareNotificationConfig
+		want   string
+	}{
+		{
+			name:   "topic",
+			config: &HealthcareNotificationConfig{PubsubTopic: "${google_pubsub_topic.foo-topic.id}"},
+			want: `{
+  "name": "foo-store",
+  "dataset": "${google_healthcare_dataset.foo-dataset.id}",
+  "provider": "google-beta",
+  "notification_config": {"pubsub_topic": "${google_pubsub_topic.foo-topic.id}"}
+}`,
+		},
+		{
+			name:   "empty_topic",
+			config: &HealthcareNotificationConfig{},
+			want: `{
+  "name": "foo-store",
+  "dataset": "${google_healthcare_dataset.foo-dataset.id}",
+  "provider": "google-beta"
+}`,
+		},
+		{
+			name: "no_config",
+			want: `{
+  "name": "foo-store",
+  "dataset": "${google_healthcare_dataset.foo-dataset.id}",
+  "provider": "google-beta"
+}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &HealthcareDICOMStore{Name: "foo-store", Dataset: "foo-dataset", NotificationConfig: tc.config}
+			if err := s.Init("my-project"); err != nil {
+				t.Fatalf("s.Init = %v", err)
+			}
+			checkJSON(t, s, tc.want)
+		})
+	}
+}
+
+func TestHealthcareHL7V2Store(t *testing.T) {
+	s := &HealthcareHL7V2Store{
+		Name:    "foo-store",
+		Dataset: "foo-dataset",
+		ParserConfig: &HL7V2ParserConfig{
+			AllowNullHeader:   true,
+			SegmentTerminator: "Cg==",
+		},
+		NotificationConfigs: []*HL7V2NotificationConfig{
+			{PubsubTopic: "${google_pubsub_topic.adt.id}", Filter: `messageType = "ADT"`},
+			{PubsubTopic: "${google_pubsub_topic.all.id}"},
+			{PubsubTopic: "${google_pubsub_topic.orm.id}", Filter: `messageType = "ORM"`},
+		},
+	}
+	if err := s.Init("my-project"); err != nil {
+		t.Fatalf("s.Init = %v", err)
+	}
+	if err := s.Validate(); err != nil {
+		t.Fatalf("s.Validate = %v", err)
+	}
+
+	want := `{
+  "name": "foo-store",
+  "dataset": "${google_healthcare_dataset.foo-dataset.id}",
+  "provider": "google-beta",
+  "parser_config": {
+    "allow_null_header": true,
+    "segment_terminator": "Cg=="
+  },
+  "notification_configs": [
+    {"pubsub_topic": "${google_pubsub_topic.adt.id}", "filter": "messageType = \"ADT\""},
+    {"pubsub_topic": "${google_pubsub_topic.all.id}"},
+    {"pubsub_topic": "${google_pubsub_topic.orm.id}", "filter": "messageType = \"ORM\""}
+  ]
+}`
+	checkJSON(t, s, want)
+}
+
+func TestHealthcareHL7V2StoreValidate(t *testing.T) {
+	cases := []struct {
+		name       string
+		terminator string
+		wantErr    bool
+	}{
+		{name: "unset"},
+		{name: "single_byte", terminator: "Cg=="},
+		{name: "multiple_bytes", terminator: "DQo=", wantErr: true},
+		{name: "not_base64", terminator: "|", wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &HealthcareHL7V2Store{
+				Name:         "foo-store",
+				ParserConfig: &HL7V2ParserConfig{SegmentTerminator: tc.terminator},
+			}
+			err := s.Validate()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("s.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestHealthcareConsentStore(t *testing.T) {
+	cases := []struct {
+		name string
+		s    *HealthcareConsentStore
+		want string
+	}{
+		{
+			name: "minimal",
+			s:    &HealthcareConsentStore{Name: "foo-store", Dataset: "foo-dataset"},
+			want: `{"dataset":"${google_healthcare_dataset.foo-dataset.id}","name":"foo-store","provider":"google-beta"}`,
+		},
+		{
+			name: "all_fields",
+			s: &HealthcareConsentStore{
+				Name:                        "foo-store",
+				Dataset:                     "foo-dataset",
+				EnableConsentCreateOnUpdate: true,
+				DefaultConsentTTL:           "90000s",
+				Labels:                      map[string]string{"team": "consent", "app": "portal"},
+			},
+			want: `{"dataset":"${google_healthcare_dataset.foo-dataset.id}","default_consent_ttl":"90000s",` +
+				`"enable_consent_create_on_update":true,"labels":{"app":"portal","team":"consent"},` +
+				`"name":"foo-store","provider":"google-beta"}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.s.Init("my-project"); err != nil {
+				t.Fatalf("s.Init = %v", err)
+			}
+			b, err := json.Marshal(tc.s)
+			if err != nil {
+				t.Fatalf("json.Marshal = %v", err)
+			}
+			if got := string(b); got != tc.want {
+				t.Errorf("json.Marshal = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHealthcareConsentStoreValidate(t *testing.T) {
+	cases := []struct {
+		ttl     string
+		wantErr bool
+	}{
+		{ttl: ""},
+		{ttl: "90000s"},
+		{ttl: "1.5s"},
+		{ttl: "90000", wantErr: true},
+		{ttl: "25h", wantErr: true},
+		{ttl: "-1s", wantErr: true},
+		{ttl: "1.0000000001s", wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.ttl, func(t *testing.T) {
+			s := &HealthcareConsentStore{Name: "foo-store", DefaultConsentTTL: tc.ttl}
+			err := s.Validate()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("s.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestHealthcareDatasetIAMMembers(t *testing.T) {
+	ms := &HealthcareDatasetIAMMembers{
+		Members: []*HealthcareDatasetIAMMember{
+			{DatasetID: "${google_healthcare_dataset.foo-dataset.id}", Role: "roles/healthcare.fhirResourceReader", Member: "group:readers@my-domain.com"},
+			{DatasetID: "${google_healthcare_dataset.foo-dataset.id}", Role: "roles/healthcare.datasetViewer", Member: "group:readers@my-domain.com"},
+		},
+		DependsOn: []string{"google_project_service.healthcare"},
+	}
+	if err := ms.Init("my-project"); err != nil {
+		t.Fatalf("ms.Init = %v", err)
+	}
+
+	want := `{
+  "for_each": {
+    "foo-dataset roles/healthcare.fhirResourceReader group:readers@my-domain.com": {
+      "dataset_id": "${google_healthcare_dataset.foo-dataset.id}",
+      "role": "roles/healthcare.fhirResourceReader",
+      "member": "group:readers@my-domain.com"
+    },
+    "foo-dataset roles/healthcare.datasetViewer group:readers@my-domain.com": {
+      "dataset_id": "${google_healthcare_dataset.foo-dataset.id}",
+      "role": "roles/healthcare.datasetViewer",
+      "member": "group:readers@my-domain.com"
+    }
+  },
+  "dataset_id": "${each.value.dataset_id}",
+  "role": "${each.value.role}",
+  "member": "${each.value.member}",
+  "provider": "google-beta",
+  "depends_on": ["google_project_service.healthcare"]
+}`
+	checkJSON(t, ms, want)
+}
+
+func TestHealthcareDatasetIAMMembersInitErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		m    *HealthcareDatasetIAMMember
+	}{
+		{
+			name: "no_dataset",
+			m:    &HealthcareDatasetIAMMember{Role: "roles/healthcare.datasetViewer", Member: "group:readers@my-domain.com"},
+		},
+		{
+			name: "invalid_member",
+			m:    &HealthcareDatasetIAMMember{DatasetID: "foo", Role: "roles/healthcare.datasetViewer", Member: "readers@my-domain.com"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ms := &HealthcareDatasetIAMMembers{Members: []*HealthcareDatasetIAMMember{tc.m}}
+			if err := ms.Init("my-project"); err == nil {
+				t.Error("ms.Init = nil, want error")
+			}
+		})
+	}
+}
@@ -15,8 +15,16 @@
 package tfconfig
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
 )
@@ -30,11 +38,22 @@ type ProjectIAMAuditConfig struct {
 
 // AuditLogConfig represents a terraform audit log config.
 type AuditLogConfig struct {
-	LogType string `json:"log_type"`
+	LogType         string   `json:"log_type"`
+	ExemptedMembers []string `json:"exempted_members,omitempty"`
+}
+
+// auditLogTypes are the valid values of an audit log config's log type.
+var auditLogTypes = map[string]bool{
+	"ADMIN_READ": true,
+	"DATA_READ":  true,
+	"DATA_WRITE": true,
 }
 
 // Init initializes the resource.
 func (c *ProjectIAMAuditConfig) Init(projectID string) error {
+	if c.Service == "" {
+		return errors.New("service must be set")
+	}
 	if c.Project != "" {
 		return fmt.Errorf("project must be unset: %v", c.Project)
 	}
@@ -42,10 +61,30 @@ func (c *ProjectIAMAuditConfig) Init(projectID string) error {
 	return nil
 }
 
+// Validate checks that the resource is valid.
+func (c *ProjectIAMAuditConfig) Validate() error {
+	for _, alc := range c.AuditLogConfigs {
+		if !auditLogTypes[alc.LogType] {
+			return fmt.Errorf("invalid log type %q for service %q: must be one of ADMIN_READ, DATA_READ or DATA_WRITE", alc.LogType, c.Service)
+		}
+		for _, m := range alc.ExemptedMembers {
+			if err := validateIAMMember(m); err != nil {
+				return fmt.Errorf("invalid exempted member for service %q: %v", c.Service, err)
+			}
+		}
+	}
+	return nil
+}
+
 // ID returns the resource unique identifier.
-// It is hardcoded to return "project" as there is at most one of this resource in a deployment.
+// There can be at most one audit config per service in a project so the service name is used.
+// The audit config of all services keeps the "project" ID it had when it was the only audit config of a project,
+// so existing deployments keep the same terraform state address.
 func (c *ProjectIAMAuditConfig) ID() string {
-	return "project"
+	if c.Service == "allServices" {
+		return "project"
+	}
+	return standardizeID(c.Service)
 }
 
 // ResourceType returns the resource terraform provider type.
@@ -55,8 +94,12 @@ func (c *ProjectIAMAuditConfig) ResourceType() string {
 
 // ProjectIAMCustomRole represents a terraform project iam custom role.
 type ProjectIAMCustomRole struct {
-	RoleID  string `json:"role_id"`
-	Project string `json:"project"`
+	RoleID      string   `json:"role_id"`
+	Project     string   `json:"project"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Permissions []string `json:"permissions"`
+	Stage       string   `json:"stage,omitempty"`
 
 	raw json.RawMessage
 }
@@ -70,8 +113,21 @@ func (r *ProjectIAMCustomRole) Init(projectID string) error {
 	return nil
 }
 
+// Validate checks that the resource is valid.
+func (r *ProjectIAMCustomRole) Validate() error {
+	if len(r.Permissions) == 0 {
+		return fmt.Errorf("permissions of custom role %q must be set", r.RoleID)
+	}
+	for _, p := range r.Permissions {
+		// Permissions are of the form service.resource.verb.
+		if !strings.Contains(p, ".") {
+			return fmt.Errorf("permission %q of custom role %q must be of the form service.resource.verb", p, r.RoleID)
+		}
+	}
+	return nil
+}
+
 // ID returns the resource unique identifier.
-// It is hardcoded to return "project" as there is at most one of this resource in a deployment.
 func (r *ProjectIAMCustomRole) ID() string {
 	return r.RoleID
 }
@@ -113,29 +169,340 @@ func (r *ProjectIAMCustomRole) MarshalJSON() ([]byte, error) {
 type ProjectIAMMembers struct {
 	Members   []*ProjectIAMMember
 	DependsOn []string
-	project   string
+
+	// UseCount expands the merged member with count instead of for_each, giving members plain indexes in plan output.
+	// Count indexes are positional: removing a member from the middle of the list shifts every member after it,
+	// so terraform removes and recreates them instead of only removing the deleted member.
+	UseCount bool
+
+	project string
 }
 
 // ProjectIAMMember represents a Terraform project IAM member.
 type ProjectIAMMember struct {
-	Role   string `json:"role"`
-	Member string `json:"member"`
+	Role      string        `json:"role"`
+	Member    string        `json:"member"`
+	Condition *IAMCondition `json:"condition,omitempty"`
+
+	// ProjectOverride grants the role in another project than the deployment's, e.g. to a cross-project service agent.
+	ProjectOverride string `json:"project_override,omitempty"`
 
 	// The following fields should not be set by users.
 
 	// ForEach is used to let a single iam member expand to reference multiple iam members
 	// through the use of terraform's for_each iterator.
-	ForEach   map[string]*ProjectIAMMember `json:"for_each,omitempty"`
-	Project   string                       `json:"project,omitempty"`
-	DependsOn []string                     `json:"depends_on,omitempty"`
+	ForEach map[string]*ProjectIAMMember `json:"for_each,omitempty"`
+
+	// Count is used instead of ForEach when members are expanded by index.
+	Count string `json:"count,omitempty"`
+
+	Project   string   `json:"project,omitempty"`
+	DependsOn []string `json:"depends_on,omitempty"`
+
+	// Dynamic holds the condition dynamic block of the member, see memberConditionDynamicBlock.
+	Dynamic map[string]interface{} `json:"dynamic,omitempty"`
+}
+
+// IAMCondition represents a Terraform IAM condition.
+// See https://cloud.google.com/iam/docs/conditions-overview.
+type IAMCondition struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Expression  string `json:"expression"`
+}
+
+// iamMemberPrefixes are the prefixes of IAM principals that are followed by an identifier.
+var iamMemberPrefixes = []string{"user:", "group:", "serviceAccount:", "domain:", "principal://", "principalSet://"}
+
+// validateIAMMember checks that the member is a well formed IAM principal.
+// See https://cloud.google.com/iam/docs/overview#concepts_related_identity.
+func validateIAMMember(member string) error {
+	if member == "allUsers" || member == "allAuthenticatedUsers" {
+		return nil
+	}
+	for _, p := range iamMemberPrefixes {
+		if strings.HasPrefix(member, p) && len(member) > len(p) {
+			return nil
+		}
+	}
+	return fmt.Errorf("member %q must be one of allUsers, allAuthenticatedUsers or start with one of %v", member, iamMemberPrefixes)
+}
+
+// forEachMember is implemented by iam members that are merged into a single member through a for_each iterator.
+type forEachMember interface {
+	// forEachKey returns the key of the member in the for_each iterator.
+	forEachKey() string
+
+	// iamCondition returns the condition of the member, or nil if the member is unconditional.
+	iamCondition() *IAMCondition
+}
+
+// iamMemberKey returns the for_each key of an iam member.
+// The key is "<role> <member>", with the condition title appended as "<role> <member> <title>" for members with a condition,
+// so the same role and member can be granted under different conditions.
+// The role and member are used verbatim (in particular, case is preserved) so keys are stable across runs and
+// members whose roles only differ by case get distinct keys.
+// Changing this format changes the terraform addresses of existing members, so it must be kept stable.
+func iamMemberKey(role, member string, c *IAMCondition) string {
+	key := fmt.Sprintf("%s %s", role, member)
+	if c != nil {
+		key = fmt.Sprintf("%s %s", key, c.Title)
+	}
+	return key
+}
+
+// resourceMemberKey returns the for_each key of an iam member granted on the given resource.
+// The key is "<resource> <iam member key>", see iamMemberKey.
+// Terraform JSON object keys are templates and for_each keys must be known at plan time,
+// so resources set as terraform references are keyed by the name of the referenced resource.
+func resourceMemberKey(resource, role, member string, c *IAMCondition) string {
+	return fmt.Sprintf("%s %s", refName(resource), iamMemberKey(role, member, c))
+}
+
+// marshalForEachMembers marshals the given members into the given merged member, which expands to all of them through for_each.
+// members must be a slice of pointers to member structs implementing forEachMember and merged a pointer to a member struct
+// of the same type with a ForEach field, and a Dynamic field if its members can set a condition.
+// The other fields of merged (e.g. the role set to "${each.value.role}") should already be set.
+// See forEachKeys for details on how the for_each keys are built.
+func marshalForEachMembers(merged interface{}, members interface{}) ([]byte, error) {
+	ms := toForEachMembers(members)
+	v := reflect.ValueOf(merged).Elem()
+	forEach := reflect.MakeMap(v.FieldByName("ForEach").Type())
+	for key, i := range forEachKeys(ms) {
+		forEach.SetMapIndex(reflect.ValueOf(key), reflect.ValueOf(ms[i]))
+	}
+	v.FieldByName("ForEach").Set(forEach)
+	for _, m := range ms {
+		if m.iamCondition() != nil {
+			v.FieldByName("Dynamic").Set(reflect.ValueOf(conditionDynamicBlock()))
+			break
+		}
+	}
+	return json.Marshal(merged)
+}
+
+// toForEachMembers converts the given slice of members to a slice of forEachMember.
+func toForEachMembers(members interface{}) []forEachMember {
+	v := reflect.ValueOf(members)
+	ms := make([]forEachMember, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		ms = append(ms, v.Index(i).Interface().(forEachMember))
+	}
+	return ms
+}
+
+// forEachKeys returns a unique for_each key for each of the given members mapped to the member's index.
+// Identical members are only kept once (the first occurrence is kept) and a warning is logged for each dropped member.
+// Distinct members that would otherwise share a key (e.g. members that only differ in depends_on)
+// are given a numbered suffix based on their order in the list to keep all of them.
+func forEachKeys(members []forEachMember) map[string]int {
+	keys := make(map[string]int)
+	var kept []forEachMember
+	for i, m := range members {
+		if containsMember(kept, m) {
+			log.Printf("Dropping duplicate IAM member %q", m.forEachKey())
+			continue
+		}
+		kept = append(kept, m)
+
+		key := m.forEachKey()
+		base := key
+		for n := 2; ; n++ {
+			if _, ok := keys[key]; !ok {
+				break
+			}
+			key = fmt.Sprintf("%s #%d", base, n)
+		}
+		keys[key] = i
+	}
+	return keys
+}
+
+func containsMember(members []forEachMember, m forEachMember) bool {
+	for _, o := range members {
+		if reflect.DeepEqual(o, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// conditionDynamicBlock returns a dynamic block that sets the condition of a for_each expanded iam member.
+// The block is empty for members that do not set a condition.
+func conditionDynamicBlock() map[string]interface{} {
+	return memberConditionDynamicBlock("each.value")
+}
+
+// memberConditionDynamicBlock returns a dynamic block that sets the condition of the iam member the given expression evaluates to.
+// Members merged into a single member through for_each set it as their Dynamic field, as a static condition block
+// could not differ between the members, and members without a condition must not get one.
+func memberConditionDynamicBlock(member string) map[string]interface{} {
+	return map[string]interface{}{
+		"condition": &DynamicBlock{
+			ForEach: fmt.Sprintf(`${lookup(%s, "condition", null) == null ? [] : [%s.condition]}`, member, member),
+			Content: map[string]interface{}{
+				"title":       "${condition.value.title}",
+				"description": `${lookup(condition.value, "description", null)}`,
+				"expression":  "${condition.value.expression}",
+			},
+		},
+	}
 }
 
 // Init initializes the resource.
 func (ms *ProjectIAMMembers) Init(projectID string) error {
+	for _, m := range ms.Members {
+		if err := validateIAMMember(m.Member); err != nil {
+			return fmt.Errorf("invalid member for role %q: %v", m.Role, err)
+		}
+	}
 	ms.project = projectID
 	return nil
 }
 
+// Add adds an unconditional member for the given role.
+// It is a no-op if the members already hold the same unconditional role and member pair.
+func (ms *ProjectIAMMembers) Add(role, member string) {
+	ms.AddWithCondition(role, member, nil)
+}
+
+// AddWithCondition adds a member for the given role under the given condition, which can be nil.
+// It is a no-op if the members already hold the same role and member pair with an equal condition.
+func (ms *ProjectIAMMembers) AddWithCondition(role, member string, c *IAMCondition) {
+	for _, m := range ms.Members {
+		if m.Role == role && m.Member == member && reflect.DeepEqual(m.Condition, c) {
+			return
+		}
+	}
+	ms.Members = append(ms.Members, &ProjectIAMMember{Role: role, Member: member, Condition: c})
+}
+
+// AddServiceAccount adds the given service account of the deployment as an unconditional member for the given role.
+// The member references the service account's email and the members depend on the service account,
+// so the grant is only made once the service account exists.
+func (ms *ProjectIAMMembers) AddServiceAccount(role string, sa *ServiceAccount) {
+	ms.Add(role, "serviceAccount:"+sa.EmailRef())
+	ms.DependsOn = normalizeDependsOn(append(ms.DependsOn, address(sa)))
+}
+
+// Sort orders the members by role, then member, then condition title (unconditional members first),
+// then project override. Members that compare equal keep their relative order.
+// MarshalJSON does not sort members as the for_each form is already ordered by key
+// and reordering members with UseCount set changes their indexes.
+func (ms *ProjectIAMMembers) Sort() {
+	sort.SliceStable(ms.Members, func(i, j int) bool {
+		a, b := ms.Members[i], ms.Members[j]
+		if a.Role != b.Role {
+			return a.Role < b.Role
+		}
+		if a.Member != b.Member {
+			return a.Member < b.Member
+		}
+		if at, bt := conditionTitle(a.Condition), conditionTitle(b.Condition); at != bt {
+			return at < bt
+		}
+		return a.ProjectOverride < b.ProjectOverride
+	})
+}
+
+func conditionTitle(c *IAMCondition) string {
+	if c == nil {
+		return ""
+	}
+	return c.Title
+}
+
+// Remove removes all members for the given role and member pair, regardless of their condition.
+func (ms *ProjectIAMMembers) Remove(role, member string) {
+	var kept []*ProjectIAMMember
+	for _, m := range ms.Members {
+		if m.Role != role || m.Member != member {
+			kept = append(kept, m)
+		}
+	}
+	ms.Members = kept
+}
+
+// MergeProjectIAMMembers merges the given member sets into a single set.
+// Members and depends_on entries are unioned in order, dropping identical duplicates.
+// Sets that were initialized must be for the same project, which the merged set is initialized with.
+// All sets must also agree on whether to use count.
+func MergeProjectIAMMembers(sets ...*ProjectIAMMembers) (*ProjectIAMMembers, error) {
+	merged := new(ProjectIAMMembers)
+	first := true
+	for _, ms := range sets {
+		if ms == nil {
+			continue
+		}
+		if ms.project != "" {
+			if merged.project != "" && merged.project != ms.project {
+				return nil, fmt.Errorf("cannot merge IAM members of different projects: %q and %q", merged.project, ms.project)
+			}
+			merged.project = ms.project
+		}
+		if !first && ms.UseCount != merged.UseCount {
+			return nil, errors.New("cannot merge IAM members using count with IAM members using for_each")
+		}
+		merged.UseCount = ms.UseCount
+		first = false
+
+		for _, m := range ms.Members {
+			if !containsProjectIAMMember(merged.Members, m) {
+				merged.Members = append(merged.Members, m)
+			}
+		}
+		merged.DependsOn = normalizeDependsOn(append(merged.DependsOn, ms.DependsOn...))
+	}
+	return merged, nil
+}
+
+func containsProjectIAMMember(members []*ProjectIAMMember, m *ProjectIAMMember) bool {
+	for _, o := range members {
+		if reflect.DeepEqual(o, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that the resource is valid.
+func (ms *ProjectIAMMembers) Validate() error {
+	for _, m := range ms.Members {
+		if err := m.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (m *ProjectIAMMember) Validate() error {
+	if m.Role == "" {
+		return fmt.Errorf("role must be set for member %q", m.Member)
+	}
+	if err := validateIAMMember(m.Member); err != nil {
+		return fmt.Errorf("invalid member for role %q: %v", m.Role, err)
+	}
+	if c := m.Condition; c != nil && (c.Title == "" || c.Expression == "") {
+		return fmt.Errorf("condition of role %q and member %q must set both title and expression", m.Role, m.Member)
+	}
+	return nil
+}
+
+// forEachKey returns the for_each key of the member.
+// Members with a project override have the project appended so the same grant can be made in several projects.
+func (m *ProjectIAMMember) forEachKey() string {
+	key := iamMemberKey(m.Role, m.Member, m.Condition)
+	if m.ProjectOverride != "" {
+		key = fmt.Sprintf("%s %s", key, m.ProjectOverride)
+	}
+	return key
+}
+
+func (m *ProjectIAMMember) iamCondition() *IAMCondition {
+	return m.Condition
+}
+
 // ID returns the resource unique identifier.
 // It is hardcoded to return "project" as there is at most one of this resource in a deployment.
 func (ms *ProjectIAMMembers) ID() string {
@@ -147,42 +514,395 @@ func (ms *ProjectIAMMembers) ResourceType() string {
 	return "google_project_iam_member"
 }
 
+func (ms *ProjectIAMMembers) dependsOn() []string {
+	return ms.DependsOn
+}
+
 // MarshalJSON marshals the list of members into a single member.
-// The single member will set a for_each block to expand to multiple iam members in the terraform call.
+// The single member will set a for_each block to expand to multiple iam members in the terraform call,
+// or a count if UseCount is set.
+// See forEachKeys for details on how the for_each keys are built.
 func (ms *ProjectIAMMembers) MarshalJSON() ([]byte, error) {
-	forEach := make(map[string]*ProjectIAMMember)
+	if ms.UseCount {
+		return ms.marshalCount(forEachKeys(toForEachMembers(ms.Members)))
+	}
+	return marshalForEachMembers(&ProjectIAMMember{
+		Project:   ms.projectExpr("each.value"),
+		Role:      "${each.value.role}",
+		Member:    "${each.value.member}",
+		DependsOn: normalizeDependsOn(ms.DependsOn),
+	}, ms.Members)
+}
+
+// marshalCount marshals the members kept by forEachKeys into a single member expanded with count.
+// The members are inlined as a tuple in the order they were given and each instance indexes it with count.index.
+func (ms *ProjectIAMMembers) marshalCount(keys map[string]int) ([]byte, error) {
+	var idxs []int
+	for _, i := range keys {
+		idxs = append(idxs, i)
+	}
+	sort.Ints(idxs)
+
+	var list []*ProjectIAMMember
+	hasCondition := false
+	for _, i := range idxs {
+		m := ms.Members[i]
+		list = append(list, &ProjectIAMMember{Role: m.Role, Member: m.Member, Condition: m.Condition, ProjectOverride: m.ProjectOverride})
+		hasCondition = hasCondition || m.Condition != nil
+	}
+	if list == nil {
+		list = []*ProjectIAMMember{}
+	}
+	// JSON arrays and objects are also valid HCL tuple and object expressions.
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(list); err != nil {
+		return nil, fmt.Errorf("failed to marshal members: %v", err)
+	}
+	b := strings.TrimSpace(buf.String())
+	elem := fmt.Sprintf("%s[count.index]", b)
+
+	merged := &ProjectIAMMember{
+		Count:     fmt.Sprintf("${length(%s)}", b),
+		Project:   ms.projectExpr(elem),
+		Role:      fmt.Sprintf("${%s.role}", elem),
+		Member:    fmt.Sprintf("${%s.member}", elem),
+		DependsOn: normalizeDependsOn(ms.DependsOn),
+	}
+	if hasCondition {
+		merged.Dynamic = memberConditionDynamicBlock(elem)
+	}
+	return json.Marshal(merged)
+}
+
+// projectOverrideRE matches the project expression written by projectExpr, capturing the quoted default project.
+var projectOverrideRE = regexp.MustCompile(`^\$\{lookup\(.*, "project_override", ("(?:[^"\\]|\\.)*")\)\}$`)
+
+// projectExpr returns the project of the merged member given the expression of the current member.
+// It is the deployment's project unless a member overrides it, in which case each member looks its override up,
+// falling back to the deployment's project.
+func (ms *ProjectIAMMembers) projectExpr(member string) string {
+	for _, m := range ms.Members {
+		if m.ProjectOverride != "" {
+			return fmt.Sprintf(`${lookup(%s, "project_override", %q)}`, member, ms.project)
+		}
+	}
+	return ms.project
+}
+
+// ToBindings groups the members by role into authoritative bindings, one per role in the order the roles first appear.
+// Each binding depends on the union of the dependencies of the members and of the member set itself.
+// Members with a condition or a project override are not included as bindings are unconditional and only cover
+// the deployment's project, so they should be kept as additive members.
+// The returned bindings still need to be initialized.
+func (ms *ProjectIAMMembers) ToBindings() []*ProjectIAMBinding {
+	var bindings []*ProjectIAMBinding
+	byRole := make(map[string]*ProjectIAMBinding)
 	for _, m := range ms.Members {
-		key := fmt.Sprintf("%s %s", m.Role, m.Member)
-		forEach[key] = m
+		if m.Condition != nil {
+			log.Printf("Not converting conditional member %q for role %q to a binding", m.Member, m.Role)
+			continue
+		}
+		if m.ProjectOverride != "" {
+			log.Printf("Not converting member %q for role %q in project %q to a binding", m.Member, m.Role, m.ProjectOverride)
+			continue
+		}
+		b, ok := byRole[m.Role]
+		if !ok {
+			b = &ProjectIAMBinding{Role: m.Role, DependsOn: append([]string(nil), ms.DependsOn...)}
+			byRole[m.Role] = b
+			bindings = append(bindings, b)
+		}
+		if !containsString(b.Members, m.Member) {
+			b.Members = append(b.Members, m.Member)
+		}
+		b.DependsOn = normalizeDependsOn(append(b.DependsOn, m.DependsOn...))
+	}
+	return bindings
+}
+
+func containsString(ss []string, s string) bool {
+	for _, o := range ss {
+		if o == s {
+			return true
+		}
+	}
+	return false
+}
+
+// UnmarshalJSON unmarshals the bytes to a list of members.
+// It also accepts the merged member written by MarshalJSON, recovering the members, project and depends_on
+// so that marshalling the result writes the same config again.
+// Members recovered from a for_each map are ordered by their for_each key.
+// Derived fields such as the condition dynamic block are ignored as they are rebuilt when marshalling.
+func (ms *ProjectIAMMembers) UnmarshalJSON(b []byte) error {
+	return ms.unmarshal(b, json.Unmarshal)
+}
+
+// UnmarshalStrict is like UnmarshalJSON but returns an error for fields that members do not define,
+// e.g. a misspelled "membr" key in hand-edited config.
+func (ms *ProjectIAMMembers) UnmarshalStrict(b []byte) error {
+	return ms.unmarshal(b, unmarshalStrict)
+}
+
+func (ms *ProjectIAMMembers) unmarshal(b []byte, unmarshal func([]byte, interface{}) error) error {
+	if b = bytes.TrimSpace(b); len(b) == 0 || b[0] != '{' {
+		return unmarshal(b, &ms.Members)
+	}
+
+	var merged ProjectIAMMember
+	if err := unmarshal(b, &merged); err != nil {
+		return fmt.Errorf("failed to unmarshal merged member: %v", err)
+	}
+	ms.Members = nil
+	if merged.Count != "" {
+		list := strings.TrimSuffix(strings.TrimPrefix(merged.Count, "${length("), ")}")
+		if list == merged.Count {
+			return fmt.Errorf("invalid count %q: must be the length of the members", merged.Count)
+		}
+		if err := unmarshal([]byte(list), &ms.Members); err != nil {
+			return fmt.Errorf("failed to unmarshal counted members: %v", err)
+		}
+		ms.UseCount = true
+	} else {
+		var keys []string
+		for k := range merged.ForEach {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			ms.Members = append(ms.Members, merged.ForEach[k])
+		}
+		ms.UseCount = false
+	}
+	ms.DependsOn = merged.DependsOn
+	ms.project = merged.Project
+	if match := projectOverrideRE.FindStringSubmatch(merged.Project); match != nil {
+		p, err := strconv.Unquote(match[1])
+		if err != nil {
+			return fmt.Errorf("failed to unquote project %q: %v", match[1], err)
+		}
+		ms.project = p
+	}
+	return nil
+}
+
+// ProjectIAMBinding represents a Terraform project IAM binding.
+// Bindings are authoritative for the role: members not in the binding will be removed from the role.
+// Thus, a role set in a binding must not also be granted through ProjectIAMMembers.
+type ProjectIAMBinding struct {
+	Role      string   `json:"role"`
+	Members   []string `json:"members"`
+	Project   string   `json:"project"`
+	DependsOn []string `json:"depends_on,omitempty"`
+}
+
+// Init initializes the resource.
+func (b *ProjectIAMBinding) Init(projectID string) error {
+	if b.Role == "" {
+		return errors.New("role must be set")
+	}
+	if b.Project != "" {
+		return fmt.Errorf("project must be unset: %v", b.Project)
+	}
+	b.Project = projectID
+	b.DependsOn = normalizeDependsOn(b.DependsOn)
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (b *ProjectIAMBinding) Validate() error {
+	for _, m := range b.Members {
+		if err := validateIAMMember(m); err != nil {
+			return fmt.Errorf("invalid member for role %q: %v", b.Role, err)
+		}
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (b *ProjectIAMBinding) ID() string {
+	return standardizeID(b.Role)
+}
+
+// ResourceType returns the resource terraform provider type.
+func (b *ProjectIAMBinding) ResourceType() string {
+	return "google_project_iam_binding"
+}
+
+func (b *ProjectIAMBinding) dependsOn() []string {
+	return b.DependsOn
+}
+
+// CheckIAMBindingConflicts checks that no role is set both authoritatively through a binding and additively through members
+// in the given resources. Mixing the two causes terraform to continuously remove and re-add the additive members.
+// Service account roles are checked per service account, matching accounts by name whether they are set
+// as a terraform reference or literally (e.g. "projects/my-project/serviceAccounts/foo@my-project.iam.gserviceaccount.com").
+func CheckIAMBindingConflicts(rs []Resource) error {
+	bindings := make(map[string]bool)
+	saBindings := make(map[[2]string]bool)
+	for _, r := range rs {
+		switch b := r.(type) {
+		case *ProjectIAMBinding:
+			bindings[b.Role] = true
+		case *ServiceAccountIAMBinding:
+			saBindings[[2]string{serviceAccountName(b.ServiceAccountID), b.Role}] = true
+		}
+	}
+	for _, r := range rs {
+		switch ms := r.(type) {
+		case *ProjectIAMMembers:
+			for _, m := range ms.Members {
+				if bindings[m.Role] {
+					return fmt.Errorf("role %q is set in both a project IAM binding and project IAM members (member %q)", m.Role, m.Member)
+				}
+			}
+		case *ServiceAccountIAMMembers:
+			for _, m := range ms.Members {
+				if saBindings[[2]string{serviceAccountName(m.ServiceAccountID), m.Role}] {
+					return fmt.Errorf("role %q on service account %q is set in both a service account IAM binding and service account IAM members (member %q)", m.Role, m.ServiceAccountID, m.Member)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// serviceAccountName returns the account ID of the given service account, which can be a terraform reference
+// (e.g. "${google_service_account.foo.name}"), a fully qualified name or an email.
+func serviceAccountName(id string) string {
+	name := refName(id)
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, "@"); i >= 0 {
+		name = name[:i]
 	}
+	return name
+}
 
-	return json.Marshal(&ProjectIAMMember{
-		ForEach:   forEach,
-		Project:   ms.project,
+// FolderIAMMembers represents multiple Terraform folder IAM members.
+// It is used to wrap and merge multiple IAM members into a single IAM member when being marshalled to JSON.
+type FolderIAMMembers struct {
+	Members   []*FolderIAMMember
+	DependsOn []string
+	folder    string
+}
+
+// FolderIAMMember represents a Terraform folder IAM member.
+type FolderIAMMember struct {
+	Role      string        `json:"role"`
+	Member    string        `json:"member"`
+	Condition *IAMCondition `json:"condition,omitempty"`
+
+	// The following fields should not be set by users.
+
+	// ForEach is used to let a single iam member expand to reference multiple iam members
+	// through the use of terraform's for_each iterator.
+	ForEach   map[string]*FolderIAMMember `json:"for_each,omitempty"`
+	Folder    string                      `json:"folder,omitempty"`
+	DependsOn []string                    `json:"depends_on,omitempty"`
+
+	// Dynamic holds the condition dynamic block of the member, see memberConditionDynamicBlock.
+	Dynamic map[string]interface{} `json:"dynamic,omitempty"`
+}
+
+// Init initializes the resource.
+// The folder ID should be in the form "folders/{folder_id}".
+func (ms *FolderIAMMembers) Init(folderID string) error {
+	for _, m := range ms.Members {
+		if err := validateIAMMember(m.Member); err != nil {
+			return fmt.Errorf("invalid member for role %q: %v", m.Role, err)
+		}
+	}
+	ms.folder = folderID
+	return nil
+}
+
+func (m *FolderIAMMember) forEachKey() string {
+	return iamMemberKey(m.Role, m.Member, m.Condition)
+}
+
+func (m *FolderIAMMember) iamCondition() *IAMCondition {
+	return m.Condition
+}
+
+// ID returns the resource unique identifier.
+// It is hardcoded to return "folder" as there is at most one of this resource in a deployment.
+func (ms *FolderIAMMembers) ID() string {
+	return "folder"
+}
+
+// ResourceType returns the resource terraform provider type.
+func (ms *FolderIAMMembers) ResourceType() string {
+	return "google_folder_iam_member"
+}
+
+func (ms *FolderIAMMembers) dependsOn() []string {
+	return ms.DependsOn
+}
+
+// MarshalJSON marshals the list of members into a single member.
+// The single member will set a for_each block to expand to multiple iam members in the terraform call.
+// See forEachKeys for details on how the for_each keys are built.
+func (ms *FolderIAMMembers) MarshalJSON() ([]byte, error) {
+	return marshalForEachMembers(&FolderIAMMember{
+		Folder:    ms.folder,
 		Role:      "${each.value.role}",
 		Member:    "${each.value.member}",
-		DependsOn: ms.DependsOn,
-	})
+		DependsOn: normalizeDependsOn(ms.DependsOn),
+	}, ms.Members)
 }
 
 // UnmarshalJSON unmarshals the bytes to a list of members.
-func (ms *ProjectIAMMembers) UnmarshalJSON(b []byte) error {
+func (ms *FolderIAMMembers) UnmarshalJSON(b []byte) error {
 	return json.Unmarshal(b, &ms.Members)
 }
 
 // ServiceAccount represents a Terraform service account.
 type ServiceAccount struct {
-	AccountID   string `json:"account_id"`
-	Project     string `json:"project"`
+	AccountID string `json:"account_id"`
+
+	// Project can only be set along with an aliased provider (e.g. "google.project_a")
+	// to create the service account in another project than the deployment's.
+	Project  string `json:"project"`
+	Provider string `json:"provider,omitempty"`
+
 	DisplayName string `json:"display_name"`
+	Description string `json:"description,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
 }
 
+// accountIDRE is the format of service account IDs enforced by GCP.
+var accountIDRE = regexp.MustCompile(`^[a-z]([-a-z0-9]{4,28}[a-z0-9])$`)
+
 // Init initializes the resource.
 func (a *ServiceAccount) Init(projectID string) error {
-	if a.Project != "" {
+	if !accountIDRE.MatchString(a.AccountID) {
+		return fmt.Errorf("invalid account_id %q: must be 6-30 lowercase letters, digits or hyphens, starting with a letter and not ending with a hyphen", a.AccountID)
+	}
+	if a.Project != "" && !isProviderAlias(a.Provider) {
 		return fmt.Errorf("project must not be set: %v", a.Project)
 	}
-	a.Project = projectID
+	if a.Project == "" {
+		a.Project = projectID
+	}
+	return nil
+}
+
+// Validate checks that the resource is valid.
+// See https://cloud.google.com/iam/docs/reference/rest/v1/projects.serviceAccounts for the length limits.
+func (a *ServiceAccount) Validate() error {
+	if a.Project == "" {
+		return fmt.Errorf("project of service account %q must be set", a.AccountID)
+	}
+	if len(a.DisplayName) > 100 {
+		return fmt.Errorf("display_name of service account %q must be at most 100 bytes, got %d", a.AccountID, len(a.DisplayName))
+	}
+	if len(a.Description) > 256 {
+		return fmt.Errorf("description of service account %q must be at most 256 bytes, got %d", a.AccountID, len(a.Description))
+	}
 	return nil
 }
 
@@ -196,7 +916,322 @@ func (a *ServiceAccount) ResourceType() string {
 	return "google_service_account"
 }
 
+// UnmarshalStrict unmarshals the bytes to the service account,
+// returning an error for fields that the service account does not define.
+func (a *ServiceAccount) UnmarshalStrict(b []byte) error {
+	return unmarshalStrict(b, a)
+}
+
+// Ref returns the terraform interpolation string referencing the attribute of the service account,
+// e.g. Ref("email") for the service account's email.
+func (a *ServiceAccount) Ref(attr string) string {
+	return Ref(a, attr)
+}
+
+// Email returns the email of the service account.
+// It is empty if the project is not set yet, i.e. before the service account is initialized.
+func (a *ServiceAccount) Email() string {
+	if a.Project == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s@%s.iam.gserviceaccount.com", a.AccountID, a.Project)
+}
+
+// EmailRef returns the terraform interpolation string referencing the email of the service account.
+// Unlike Email, it also makes resources using it depend on the service account.
+func (a *ServiceAccount) EmailRef() string {
+	return a.Ref("email")
+}
+
 // ImportID returns the ID to use for terraform imports.
 func (a *ServiceAccount) ImportID(runner.Runner) (string, error) {
-	return fmt.Sprintf("projects/%s/serviceAccounts/%s@%s.iam.gserviceaccount.com", a.Project, a.AccountID, a.Project), nil
+	return fmt.Sprintf("projects/%s/serviceAccounts/%s", a.Project, a.Email()), nil
+}
+
+// ServiceAccountKey represents a Terraform service account key.
+// Exported keys are long lived credentials and should only be used when no key-less alternative exists.
+type ServiceAccountKey struct {
+	ServiceAccountID string `json:"service_account_id"`
+	KeyAlgorithm     string `json:"key_algorithm"`
+	PublicKeyType    string `json:"public_key_type,omitempty"`
+
+	// Name distinguishes the keys of a service account that has several, e.g. "rotated".
+	// It is only used in the resource ID and is not part of the terraform config.
+	Name string `json:"-"`
+}
+
+// Init initializes the resource.
+// Service account keys do not have a project field so the project ID is unused.
+func (k *ServiceAccountKey) Init(string) error {
+	if k.ServiceAccountID == "" {
+		return errors.New("service_account_id must be set")
+	}
+	if k.KeyAlgorithm == "" {
+		k.KeyAlgorithm = "KEY_ALG_RSA_2048"
+	}
+	return nil
+}
+
+// Validate checks that the resource is valid.
+// Keys are always valid but a warning is logged as there are safer alternatives.
+func (k *ServiceAccountKey) Validate() error {
+	log.Printf("Service account key for %q is sensitive: consider workload identity or service account impersonation instead", k.ServiceAccountID)
+	return nil
+}
+
+// ID returns the resource unique identifier.
+// It is derived from the service account ID, which may be a terraform reference, suffixed by the name of the key if set.
+func (k *ServiceAccountKey) ID() string {
+	id := strings.TrimSuffix(strings.TrimPrefix(k.ServiceAccountID, "${"), "}")
+	if k.Name != "" {
+		id = fmt.Sprintf("%s_%s", id, k.Name)
+	}
+	return standardizeID(id)
+}
+
+// ResourceType returns the resource terraform provider type.
+func (k *ServiceAccountKey) ResourceType() string {
+	return "google_service_account_key"
+}
+
+// String returns the key config for logging.
+// The private key is only an attribute exported by terraform and never part of the config, so no input field needs redacting.
+// It is implemented so keys follow the same logging convention as other sensitive resources.
+func (k *ServiceAccountKey) String() string {
+	return redactedString(k)
+}
+
+// OrganizationIAMMembers represents multiple Terraform organization IAM members.
+// It is used to wrap and merge multiple IAM members into a single IAM member when being marshalled to JSON.
+type OrganizationIAMMembers struct {
+	Members   []*OrganizationIAMMember
+	DependsOn []string
+	orgID     string
+}
+
+// OrganizationIAMMember represents a Terraform organization IAM member.
+type OrganizationIAMMember struct {
+	Role      string        `json:"role"`
+	Member    string        `json:"member"`
+	Condition *IAMCondition `json:"condition,omitempty"`
+
+	// The following fields should not be set by users.
+
+	// ForEach is used to let a single iam member expand to reference multiple iam members
+	// through the use of terraform's for_each iterator.
+	ForEach   map[string]*OrganizationIAMMember `json:"for_each,omitempty"`
+	OrgID     string                            `json:"org_id,omitempty"`
+	DependsOn []string                          `json:"depends_on,omitempty"`
+
+	// Dynamic holds the condition dynamic block of the member, see memberConditionDynamicBlock.
+	Dynamic map[string]interface{} `json:"dynamic,omitempty"`
+}
+
+// Init initializes the resource.
+func (ms *OrganizationIAMMembers) Init(orgID string) error {
+	for _, m := range ms.Members {
+		if err := validateIAMMember(m.Member); err != nil {
+			return fmt.Errorf("invalid member for role %q: %v", m.Role, err)
+		}
+	}
+	ms.orgID = orgID
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (ms *OrganizationIAMMembers) Validate() error {
+	if ms.orgID == "" {
+		return errors.New("org_id must be set")
+	}
+	return nil
+}
+
+func (m *OrganizationIAMMember) forEachKey() string {
+	return iamMemberKey(m.Role, m.Member, m.Condition)
+}
+
+func (m *OrganizationIAMMember) iamCondition() *IAMCondition {
+	return m.Condition
+}
+
+// ID returns the resource unique identifier.
+// It is hardcoded to return "organization" as there is at most one of this resource in a deployment.
+func (ms *OrganizationIAMMembers) ID() string {
+	return "organization"
+}
+
+// ResourceType returns the resource terraform provider type.
+func (ms *OrganizationIAMMembers) ResourceType() string {
+	return "google_organization_iam_member"
+}
+
+func (ms *OrganizationIAMMembers) dependsOn() []string {
+	return ms.DependsOn
+}
+
+// MarshalJSON marshals the list of members into a single member.
+// The single member will set a for_each block to expand to multiple iam members in the terraform call.
+// See forEachKeys for details on how the for_each keys are built.
+func (ms *OrganizationIAMMembers) MarshalJSON() ([]byte, error) {
+	return marshalForEachMembers(&OrganizationIAMMember{
+		OrgID:     ms.orgID,
+		Role:      "${each.value.role}",
+		Member:    "${each.value.member}",
+		DependsOn: normalizeDependsOn(ms.DependsOn),
+	}, ms.Members)
+}
+
+// UnmarshalJSON unmarshals the bytes to a list of members.
+func (ms *OrganizationIAMMembers) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &ms.Members)
+}
+
+// ServiceAccountIAMMembers represents multiple Terraform service account IAM members across one or more service accounts.
+// It is used to wrap and merge multiple IAM members into a single IAM member when being marshalled to JSON.
+type ServiceAccountIAMMembers struct {
+	Members   []*ServiceAccountIAMMember
+	DependsOn []string
+}
+
+// ServiceAccountIAMMember represents a Terraform service account IAM member.
+type ServiceAccountIAMMember struct {
+	// ServiceAccountID is the fully-qualified name of the service account to grant the role on.
+	// e.g. projects/my-project/serviceAccounts/foo@my-project.iam.gserviceaccount.com or ${google_service_account.foo.name}.
+	ServiceAccountID string        `json:"service_account_id"`
+	Role             string        `json:"role"`
+	Member           string        `json:"member"`
+	Condition        *IAMCondition `json:"condition,omitempty"`
+
+	// The following fields should not be set by users.
+
+	// ForEach is used to let a single iam member expand to reference multiple iam members
+	// through the use of terraform's for_each iterator.
+	ForEach   map[string]*ServiceAccountIAMMember `json:"for_each,omitempty"`
+	DependsOn []string                            `json:"depends_on,omitempty"`
+
+	// Dynamic holds the condition dynamic block of the member, see memberConditionDynamicBlock.
+	Dynamic map[string]interface{} `json:"dynamic,omitempty"`
+}
+
+// serviceAccountNameRE matches the fully-qualified name of a service account.
+var serviceAccountNameRE = regexp.MustCompile(`^projects/[^/]+/serviceAccounts/[^/]+$`)
+
+// Init initializes the resource.
+// Service account IAM members do not have a project field so the project ID is unused.
+func (ms *ServiceAccountIAMMembers) Init(string) error {
+	for _, m := range ms.Members {
+		if err := validateIAMMember(m.Member); err != nil {
+			return fmt.Errorf("invalid member for role %q on service account %q: %v", m.Role, m.ServiceAccountID, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks that the resource is valid.
+// Service account IDs that are terraform references are not checked as they are only known at apply time.
+func (ms *ServiceAccountIAMMembers) Validate() error {
+	for _, m := range ms.Members {
+		if strings.HasPrefix(m.ServiceAccountID, "${") {
+			continue
+		}
+		if !serviceAccountNameRE.MatchString(m.ServiceAccountID) {
+			return fmt.Errorf("service_account_id %q for role %q and member %q must be of the form projects/{project}/serviceAccounts/{email}", m.ServiceAccountID, m.Role, m.Member)
+		}
+	}
+	return nil
+}
+
+func (m *ServiceAccountIAMMember) forEachKey() string {
+	return resourceMemberKey(m.ServiceAccountID, m.Role, m.Member, m.Condition)
+}
+
+func (m *ServiceAccountIAMMember) iamCondition() *IAMCondition {
+	return m.Condition
+}
+
+// ID returns the resource unique identifier.
+// It is hardcoded to return "service_accounts" as there is at most one of this resource in a deployment.
+func (ms *ServiceAccountIAMMembers) ID() string {
+	return "service_accounts"
+}
+
+// ResourceType returns the resource terraform provider type.
+func (ms *ServiceAccountIAMMembers) ResourceType() string {
+	return "google_service_account_iam_member"
+}
+
+func (ms *ServiceAccountIAMMembers) dependsOn() []string {
+	return ms.DependsOn
+}
+
+// MarshalJSON marshals the list of members into a single member.
+// The single member will set a for_each block to expand to multiple iam members in the terraform call.
+// See forEachKeys for details on how the for_each keys are built.
+func (ms *ServiceAccountIAMMembers) MarshalJSON() ([]byte, error) {
+	return marshalForEachMembers(&ServiceAccountIAMMember{
+		ServiceAccountID: "${each.value.service_account_id}",
+		Role:             "${each.value.role}",
+		Member:           "${each.value.member}",
+		DependsOn:        normalizeDependsOn(ms.DependsOn),
+	}, ms.Members)
+}
+
+// UnmarshalJSON unmarshals the bytes to a list of members.
+func (ms *ServiceAccountIAMMembers) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &ms.Members)
+}
+
+// ServiceAccountIAMBinding represents a Terraform service account IAM binding.
+// Bindings are authoritative for the role on the service account: members not in the binding will be removed from the role.
+// Thus, a role set in a binding must not also be granted on the same service account through ServiceAccountIAMMembers.
+type ServiceAccountIAMBinding struct {
+	// ServiceAccountID is the fully-qualified name of the service account to grant the role on.
+	// e.g. projects/my-project/serviceAccounts/foo@my-project.iam.gserviceaccount.com or ${google_service_account.foo.name}.
+	ServiceAccountID string   `json:"service_account_id"`
+	Role             string   `json:"role"`
+	Members          []string `json:"members"`
+	DependsOn        []string `json:"depends_on,omitempty"`
+}
+
+// Init initializes the resource.
+// Service account IAM bindings do not have a project field so the project ID is unused.
+func (b *ServiceAccountIAMBinding) Init(string) error {
+	if b.ServiceAccountID == "" {
+		return errors.New("service_account_id must be set")
+	}
+	if b.Role == "" {
+		return errors.New("role must be set")
+	}
+	b.DependsOn = normalizeDependsOn(b.DependsOn)
+	return nil
+}
+
+// Validate checks that the resource is valid.
+// Service account IDs that are terraform references are not checked as they are only known at apply time.
+func (b *ServiceAccountIAMBinding) Validate() error {
+	if !strings.HasPrefix(b.ServiceAccountID, "${") && !serviceAccountNameRE.MatchString(b.ServiceAccountID) {
+		return fmt.Errorf("service_account_id %q for role %q must be of the form projects/{project}/serviceAccounts/{email}", b.ServiceAccountID, b.Role)
+	}
+	for _, m := range b.Members {
+		if err := validateIAMMember(m); err != nil {
+			return fmt.Errorf("invalid member for role %q on service account %q: %v", b.Role, b.ServiceAccountID, err)
+		}
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+// It combines the service account and role as a service account has at most one binding per role.
+// Service accounts referenced through terraform use the referenced resource's name.
+func (b *ServiceAccountIAMBinding) ID() string {
+	return standardizeID(fmt.Sprintf("%s_%s", refName(b.ServiceAccountID), b.Role))
+}
+
+// ResourceType returns the resource terraform provider type.
+func (b *ServiceAccountIAMBinding) ResourceType() string {
+	return "google_service_account_iam_binding"
+}
+
+func (b *ServiceAccountIAMBinding) dependsOn() []string {
+	return b.DependsOn
 }
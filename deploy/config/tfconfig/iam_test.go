@@ -0,0 +1,1491 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// checkJSON checks that the JSON marshalled form of v is equivalent to the given JSON string.
+func checkJSON(t *testing.T, v interface{}, wantJSON string) {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("json.Marshal = %v", err)
+	}
+	var got, want interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("json.Unmarshal got = %v", err)
+	}
+	if err := json.Unmarshal([]byte(wantJSON), &want); err != nil {
+		t.Fatalf("json.Unmarshal want = %v", err)
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("marshalled JSON differs (-got +want):\n%v", diff)
+	}
+}
+
+func TestProjectIAMMembers(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name: "no_condition",
+			input: `[
+  {"role": "roles/viewer", "member": "group:foo@my-domain.com"}
+]`,
+			want: `{
+  "for_each": {
+    "roles/viewer group:foo@my-domain.com": {
+      "role": "roles/viewer",
+      "member": "group:foo@my-domain.com"
+    }
+  },
+  "project": "my-project",
+  "role": "${each.value.role}",
+  "member": "${each.value.member}"
+}`,
+		},
+		{
+			name: "condition",
+			input: `[
+  {"role": "roles/viewer", "member": "group:foo@my-domain.com"},
+  {
+    "role": "roles/viewer",
+    "member": "group:bar@my-domain.com",
+    "condition": {
+      "title": "expires_2020",
+      "description": "Expires at the start of 2020",
+      "expression": "request.time < timestamp(\"2020-01-01T00:00:00Z\")"
+    }
+  }
+]`,
+			want: `{
+  "for_each": {
+    "roles/viewer group:foo@my-domain.com": {
+      "role": "roles/viewer",
+      "member": "group:foo@my-domain.com"
+    },
+    "roles/viewer group:bar@my-domain.com expires_2020": {
+      "role": "roles/viewer",
+      "member": "group:bar@my-domain.com",
+      "condition": {
+        "title": "expires_2020",
+        "description": "Expires at the start of 2020",
+        "expression": "request.time < timestamp(\"2020-01-01T00:00:00Z\")"
+      }
+    }
+  },
+  "project": "my-project",
+  "role": "${each.value.role}",
+  "member": "${each.value.member}",
+  "dynamic": {
+    "condition": {
+      "for_each": "${lookup(each.value, \"condition\", null) == null ? [] : [each.value.condition]}",
+      "content": {
+        "title": "${condition.value.title}",
+        "description": "${lookup(condition.value, \"description\", null)}",
+        "expression": "${condition.value.expression}"
+      }
+    }
+  }
+}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ms := new(ProjectIAMMembers)
+			if err := json.Unmarshal([]byte(tc.input), ms); err != nil {
+				t.Fatalf("json.Unmarshal = %v", err)
+			}
+			if err := ms.Init("my-project"); err != nil {
+				t.Fatalf("ms.Init = %v", err)
+			}
+			checkJSON(t, ms, tc.want)
+		})
+	}
+}
+
+func TestProjectIAMMembersUseCount(t *testing.T) {
+	input := `[
+  {"role": "roles/viewer", "member": "group:foo@my-domain.com"},
+  {"role": "roles/editor", "member": "group:bar@my-domain.com"},
+  {"role": "roles/viewer", "member": "group:foo@my-domain.com"}
+]`
+	list := `[{"role":"roles/viewer","member":"group:foo@my-domain.com"},{"role":"roles/editor","member":"group:bar@my-domain.com"}]`
+	cases := []struct {
+		name     string
+		useCount bool
+		want     string
+	}{
+		{
+			name: "for_each",
+			want: `{
+  "for_each": {
+    "roles/viewer group:foo@my-domain.com": {
+      "role": "roles/viewer",
+      "member": "group:foo@my-domain.com"
+    },
+    "roles/editor group:bar@my-domain.com": {
+      "role": "roles/editor",
+      "member": "group:bar@my-domain.com"
+    }
+  },
+  "project": "my-project",
+  "role": "${each.value.role}",
+  "member": "${each.value.member}"
+}`,
+		},
+		{
+			name:     "count",
+			useCount: true,
+			want: fmt.Sprintf(`{
+  "count": "${length(%[1]s)}",
+  "project": "my-project",
+  "role": "${%[1]s[count.index].role}",
+  "member": "${%[1]s[count.index].member}"
+}`, strings.ReplaceAll(list, `"`, `\"`)),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ms := &ProjectIAMMembers{UseCount: tc.useCount}
+			if err := json.Unmarshal([]byte(input), ms); err != nil {
+				t.Fatalf("json.Unmarshal = %v", err)
+			}
+			if err := ms.Init("my-project"); err != nil {
+				t.Fatalf("ms.Init = %v", err)
+			}
+			checkJSON(t, ms, tc.want)
+		})
+	}
+}
+
+func TestProjectIAMMembersUseCountCondition(t *testing.T) {
+	ms := &ProjectIAMMembers{
+		Members: []*ProjectIAMMember{{
+			Role:      "roles/viewer",
+			Member:    "group:foo@my-domain.com",
+			Condition: &IAMCondition{Title: "expires_2020", Expression: `request.time < timestamp("2020-01-01T00:00:00Z")`},
+		}},
+		UseCount: true,
+	}
+	if err := ms.Init("my-project"); err != nil {
+		t.Fatalf("ms.Init = %v", err)
+	}
+	b, err := json.Marshal(ms)
+	if err != nil {
+		t.Fatalf("json.Marshal = %v", err)
+	}
+	var got struct {
+		Dynamic struct {
+			Condition struct {
+				ForEach string `json:"for_each"`
+			} `json:"condition"`
+		} `json:"dynamic"`
+	}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("json.Unmarshal = %v", err)
+	}
+	elem := `[{"role":"roles/viewer","member":"group:foo@my-domain.com","condition":{"title":"expires_2020","expression":"request.time < timestamp(\"2020-01-01T00:00:00Z\")"}}][count.index]`
+	want := fmt.Sprintf(`${lookup(%s, "condition", null) == null ? [] : [%s.condition]}`, elem, elem)
+	if got.Dynamic.Condition.ForEach != want {
+		t.Errorf("dynamic condition for_each = %v, want %v", got.Dynamic.Condition.ForEach, want)
+	}
+}
+
+func TestProjectIAMMembersRoundTrip(t *testing.T) {
+	members := []*ProjectIAMMember{
+		{Role: "roles/viewer", Member: "group:foo@my-domain.com"},
+		{
+			Role:      "roles/editor",
+			Member:    "group:bar@my-domain.com",
+			Condition: &IAMCondition{Title: "expires_2020", Expression: `request.time < timestamp("2020-01-01T00:00:00Z")`},
+		},
+	}
+	cases := []struct {
+		name string
+		ms   *ProjectIAMMembers
+	}{
+		{
+			name: "for_each",
+			ms:   &ProjectIAMMembers{Members: members, DependsOn: []string{"google_project_service.iam"}},
+		},
+		{
+			name: "count",
+			ms:   &ProjectIAMMembers{Members: members, DependsOn: []string{"google_project_service.iam"}, UseCount: true},
+		},
+		{
+			name: "project_override",
+			ms: &ProjectIAMMembers{Members: append([]*ProjectIAMMember{
+				{Role: "roles/pubsub.publisher", Member: "serviceAccount:agent@other-project.iam.gserviceaccount.com", ProjectOverride: "other-project"},
+			}, members...)},
+		},
+		{
+			name: "empty",
+			ms:   &ProjectIAMMembers{},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.ms.Init("my-project"); err != nil {
+				t.Fatalf("ms.Init = %v", err)
+			}
+			want, err := json.Marshal(tc.ms)
+			if err != nil {
+				t.Fatalf("json.Marshal = %v", err)
+			}
+
+			// Unmarshalled without Init, so the project must be recovered from the merged member.
+			got := new(ProjectIAMMembers)
+			if err := json.Unmarshal(want, got); err != nil {
+				t.Fatalf("json.Unmarshal = %v", err)
+			}
+			if got.UseCount != tc.ms.UseCount {
+				t.Errorf("UseCount = %t, want %t", got.UseCount, tc.ms.UseCount)
+			}
+			if diff := cmp.Diff(got.DependsOn, tc.ms.DependsOn); diff != "" {
+				t.Errorf("DependsOn differs (-got +want):\n%v", diff)
+			}
+			checkJSON(t, got, string(want))
+		})
+	}
+}
+
+func TestProjectIAMMembersAdd(t *testing.T) {
+	cond := &IAMCondition{Title: "expires_2020", Expression: `request.time < timestamp("2020-01-01T00:00:00Z")`}
+	ms := new(ProjectIAMMembers)
+	ms.Add("roles/viewer", "group:foo@my-domain.com")
+	ms.Add("roles/viewer", "group:foo@my-domain.com")
+	ms.Add("roles/editor", "group:foo@my-domain.com")
+	ms.AddWithCondition("roles/viewer", "group:foo@my-domain.com", cond)
+	ms.AddWithCondition("roles/viewer", "group:foo@my-domain.com", &IAMCondition{Title: cond.Title, Expression: cond.Expression})
+
+	want := []*ProjectIAMMember{
+		{Role: "roles/viewer", Member: "group:foo@my-domain.com"},
+		{Role: "roles/editor", Member: "group:foo@my-domain.com"},
+		{Role: "roles/viewer", Member: "group:foo@my-domain.com", Condition: cond},
+	}
+	if diff := cmp.Diff(ms.Members, want); diff != "" {
+		t.Errorf("members differ (-got +want):\n%v", diff)
+	}
+}
+
+func TestProjectIAMMembersSort(t *testing.T) {
+	input := `[
+  {"role": "roles/viewer", "member": "group:foo@my-domain.com", "condition": {"title": "weekdays", "expression": "e"}},
+  {"role": "roles/viewer", "member": "group:foo@my-domain.com", "project_override": "other-project"},
+  {"role": "roles/viewer", "member": "group:bar@my-domain.com"},
+  {"role": "roles/editor", "member": "user:baz@my-domain.com"},
+  {"role": "roles/viewer", "member": "group:foo@my-domain.com", "condition": {"title": "expires_2020", "expression": "e"}},
+  {"role": "roles/viewer", "member": "group:foo@my-domain.com"},
+  {"role": "roles/editor", "member": "group:foo@my-domain.com"}
+]`
+	ms := new(ProjectIAMMembers)
+	if err := json.Unmarshal([]byte(input), ms); err != nil {
+		t.Fatalf("json.Unmarshal = %v", err)
+	}
+	ms.Sort()
+
+	var got []string
+	for _, m := range ms.Members {
+		got = append(got, strings.TrimSpace(fmt.Sprintf("%s %s %s %s", m.Role, m.Member, conditionTitle(m.Condition), m.ProjectOverride)))
+	}
+	want := []string{
+		"roles/editor group:foo@my-domain.com",
+		"roles/editor user:baz@my-domain.com",
+		"roles/viewer group:bar@my-domain.com",
+		"roles/viewer group:foo@my-domain.com",
+		"roles/viewer group:foo@my-domain.com  other-project",
+		"roles/viewer group:foo@my-domain.com expires_2020",
+		"roles/viewer group:foo@my-domain.com weekdays",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("sorted members differ (-got +want):\n%v", diff)
+	}
+}
+
+func TestProjectIAMMembersAddServiceAccount(t *testing.T) {
+	sa := &ServiceAccount{AccountID: "foo-account"}
+	ms := &ProjectIAMMembers{DependsOn: []string{"google_storage_bucket.foo-bucket"}}
+	ms.AddServiceAccount("roles/viewer", sa)
+	ms.AddServiceAccount("roles/editor", sa)
+
+	want := []*ProjectIAMMember{
+		{Role: "roles/viewer", Member: "serviceAccount:${google_service_account.foo-account.email}"},
+		{Role: "roles/editor", Member: "serviceAccount:${google_service_account.foo-account.email}"},
+	}
+	if diff := cmp.Diff(ms.Members, want); diff != "" {
+		t.Errorf("members differ (-got +want):\n%v", diff)
+	}
+	wantDeps := []string{"google_storage_bucket.foo-bucket", "google_service_account.foo-account"}
+	if diff := cmp.Diff(ms.DependsOn, wantDeps); diff != "" {
+		t.Errorf("DependsOn differs (-got +want):\n%v", diff)
+	}
+	if err := CheckDependsOn([]Resource{sa, ms, &StorageBucket{Name: "foo-bucket"}}); err != nil {
+		t.Errorf("CheckDependsOn = %v", err)
+	}
+}
+
+func TestProjectIAMMembersRemove(t *testing.T) {
+	ms := &ProjectIAMMembers{Members: []*ProjectIAMMember{
+		{Role: "roles/viewer", Member: "group:foo@my-domain.com"},
+		{Role: "roles/editor", Member: "group:foo@my-domain.com"},
+		{Role: "roles/viewer", Member: "group:bar@my-domain.com"},
+		{Role: "roles/viewer", Member: "group:foo@my-domain.com", Condition: &IAMCondition{Title: "t", Expression: "e"}},
+	}}
+	ms.Remove("roles/viewer", "group:foo@my-domain.com")
+	ms.Remove("roles/owner", "group:foo@my-domain.com")
+
+	want := []*ProjectIAMMember{
+		{Role: "roles/editor", Member: "group:foo@my-domain.com"},
+		{Role: "roles/viewer", Member: "group:bar@my-domain.com"},
+	}
+	if diff := cmp.Diff(ms.Members, want); diff != "" {
+		t.Errorf("members differ (-got +want):\n%v", diff)
+	}
+}
+
+func TestMergeProjectIAMMembers(t *testing.T) {
+	a := &ProjectIAMMembers{
+		Members: []*ProjectIAMMember{
+			{Role: "roles/viewer", Member: "group:foo@my-domain.com"},
+			{Role: "roles/editor", Member: "group:bar@my-domain.com"},
+		},
+		DependsOn: []string{"google_project_service.iam"},
+	}
+	b := &ProjectIAMMembers{
+		Members: []*ProjectIAMMember{
+			{Role: "roles/editor", Member: "group:bar@my-domain.com"},
+			{Role: "roles/owner", Member: "group:admins@my-domain.com"},
+		},
+		DependsOn: []string{"google_project_service.iam", "google_service_account.foo-account"},
+	}
+	for _, ms := range []*ProjectIAMMembers{a, b} {
+		if err := ms.Init("my-project"); err != nil {
+			t.Fatalf("ms.Init = %v", err)
+		}
+	}
+
+	got, err := MergeProjectIAMMembers(a, nil, b, new(ProjectIAMMembers))
+	if err != nil {
+		t.Fatalf("MergeProjectIAMMembers = %v", err)
+	}
+	want := &ProjectIAMMembers{
+		Members: []*ProjectIAMMember{
+			{Role: "roles/viewer", Member: "group:foo@my-domain.com"},
+			{Role: "roles/editor", Member: "group:bar@my-domain.com"},
+			{Role: "roles/owner", Member: "group:admins@my-domain.com"},
+		},
+		DependsOn: []string{"google_project_service.iam", "google_service_account.foo-account"},
+		project:   "my-project",
+	}
+	if diff := cmp.Diff(got, want, cmp.AllowUnexported(ProjectIAMMembers{})); diff != "" {
+		t.Errorf("MergeProjectIAMMembers differs (-got +want):\n%v", diff)
+	}
+}
+
+func TestMergeProjectIAMMembersErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		sets []*ProjectIAMMembers
+	}{
+		{
+			name: "different_projects",
+			sets: []*ProjectIAMMembers{{project: "foo-project"}, {project: "bar-project"}},
+		},
+		{
+			name: "count_and_for_each",
+			sets: []*ProjectIAMMembers{{UseCount: true}, {}},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := MergeProjectIAMMembers(tc.sets...); err == nil {
+				t.Error("MergeProjectIAMMembers = nil, want error")
+			}
+		})
+	}
+}
+
+func TestProjectIAMMembersProjectOverride(t *testing.T) {
+	ms := &ProjectIAMMembers{Members: []*ProjectIAMMember{
+		{Role: "roles/viewer", Member: "group:foo@my-domain.com"},
+		{Role: "roles/viewer", Member: "group:foo@my-domain.com", ProjectOverride: "other-project"},
+	}}
+	if err := ms.Init("my-project"); err != nil {
+		t.Fatalf("ms.Init = %v", err)
+	}
+	want := `{
+  "for_each": {
+    "roles/viewer group:foo@my-domain.com": {
+      "role": "roles/viewer",
+      "member": "group:foo@my-domain.com"
+    },
+    "roles/viewer group:foo@my-domain.com other-project": {
+      "role": "roles/viewer",
+      "member": "group:foo@my-domain.com",
+      "project_override": "other-project"
+    }
+  },
+  "project": "${lookup(each.value, \"project_override\", \"my-project\")}",
+  "role": "${each.value.role}",
+  "member": "${each.value.member}"
+}`
+	checkJSON(t, ms, want)
+
+	if got := len(ms.ToBindings()); got != 1 {
+		t.Errorf("len(ms.ToBindings()) = %d, want 1 binding without the overridden member", got)
+	}
+
+	ms.UseCount = true
+	b, err := json.Marshal(ms)
+	if err != nil {
+		t.Fatalf("json.Marshal = %v", err)
+	}
+	var got struct {
+		Project string `json:"project"`
+	}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("json.Unmarshal = %v", err)
+	}
+	if !strings.HasPrefix(got.Project, "${lookup([") || !strings.HasSuffix(got.Project, `[count.index], "project_override", "my-project")}`) {
+		t.Errorf("project = %v, want lookup of the counted member's project_override", got.Project)
+	}
+}
+
+func TestProjectIAMMembersUnmarshalStrict(t *testing.T) {
+	cases := []struct {
+		name      string
+		input     string
+		wantField string
+	}{
+		{
+			name:  "list",
+			input: `[{"role": "roles/viewer", "member": "group:foo@my-domain.com"}]`,
+		},
+		{
+			name:  "merged",
+			input: `{"for_each": {"roles/viewer group:foo@my-domain.com": {"role": "roles/viewer", "member": "group:foo@my-domain.com"}}, "project": "my-project", "role": "${each.value.role}", "member": "${each.value.member}"}`,
+		},
+		{
+			name:      "misspelled_member",
+			input:     `[{"role": "roles/viewer", "membr": "group:foo@my-domain.com"}]`,
+			wantField: "membr",
+		},
+		{
+			name:      "misspelled_condition_field",
+			input:     `[{"role": "roles/viewer", "member": "group:foo@my-domain.com", "condition": {"title": "t", "expresion": "e"}}]`,
+			wantField: "expresion",
+		},
+		{
+			name:      "misspelled_merged_field",
+			input:     `{"for_each": {}, "depend_on": ["google_service_account.foo-account"]}`,
+			wantField: "depend_on",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// The lenient unmarshaller must keep ignoring unknown fields.
+			if err := json.Unmarshal([]byte(tc.input), new(ProjectIAMMembers)); err != nil {
+				t.Fatalf("json.Unmarshal = %v", err)
+			}
+			err := new(ProjectIAMMembers).UnmarshalStrict([]byte(tc.input))
+			if gotErr := err != nil; gotErr != (tc.wantField != "") {
+				t.Fatalf("ms.UnmarshalStrict = %v, want error: %t", err, tc.wantField != "")
+			}
+			if err != nil && !strings.Contains(err.Error(), fmt.Sprintf("%q", tc.wantField)) {
+				t.Errorf("ms.UnmarshalStrict = %v, want error naming %q", err, tc.wantField)
+			}
+		})
+	}
+}
+
+func TestProjectIAMMembersForEachKeys(t *testing.T) {
+	ms := &ProjectIAMMembers{Members: []*ProjectIAMMember{
+		{Role: "roles/viewer", Member: "group:foo@my-domain.com"},
+		{Role: "roles/Viewer", Member: "group:foo@my-domain.com"},
+		{Role: "roles/editor", Member: "user:bar@my-domain.com"},
+	}}
+	if err := ms.Init("my-project"); err != nil {
+		t.Fatalf("ms.Init = %v", err)
+	}
+	want := `{"role":"${each.value.role}","member":"${each.value.member}","for_each":{` +
+		`"roles/Viewer group:foo@my-domain.com":{"role":"roles/Viewer","member":"group:foo@my-domain.com"},` +
+		`"roles/editor user:bar@my-domain.com":{"role":"roles/editor","member":"user:bar@my-domain.com"},` +
+		`"roles/viewer group:foo@my-domain.com":{"role":"roles/viewer","member":"group:foo@my-domain.com"}},` +
+		`"project":"my-project"}`
+
+	// Marshal multiple times to check the output does not depend on map iteration order.
+	for i := 0; i < 10; i++ {
+		b, err := json.Marshal(ms)
+		if err != nil {
+			t.Fatalf("json.Marshal = %v", err)
+		}
+		if got := string(b); got != want {
+			t.Fatalf("json.Marshal = %s, want %s", got, want)
+		}
+	}
+}
+
+func TestProjectIAMMembersDuplicates(t *testing.T) {
+	cases := []struct {
+		name    string
+		members []*ProjectIAMMember
+		want    string
+	}{
+		{
+			name: "exact_duplicates",
+			members: []*ProjectIAMMember{
+				{Role: "roles/viewer", Member: "group:foo@my-domain.com"},
+				{Role: "roles/viewer", Member: "group:foo@my-domain.com"},
+			},
+			want: `{
+  "for_each": {
+    "roles/viewer group:foo@my-domain.com": {
+      "role": "roles/viewer",
+      "member": "group:foo@my-domain.com"
+    }
+  },
+  "project": "my-project",
+  "role": "${each.value.role}",
+  "member": "${each.value.member}"
+}`,
+		},
+		{
+			name: "near_duplicates",
+			members: []*ProjectIAMMember{
+				{Role: "roles/viewer", Member: "group:foo@my-domain.com"},
+				{Role: "roles/viewer", Member: "group:foo@my-domain.com", DependsOn: []string{"google_project_service.project"}},
+			},
+			want: `{
+  "for_each": {
+    "roles/viewer group:foo@my-domain.com": {
+      "role": "roles/viewer",
+      "member": "group:foo@my-domain.com"
+    },
+    "roles/viewer group:foo@my-domain.com #2": {
+      "role": "roles/viewer",
+      "member": "group:foo@my-domain.com",
+      "depends_on": ["google_project_service.project"]
+    }
+  },
+  "project": "my-project",
+  "role": "${each.value.role}",
+  "member": "${each.value.member}"
+}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ms := &ProjectIAMMembers{Members: tc.members}
+			if err := ms.Init("my-project"); err != nil {
+				t.Fatalf("ms.Init = %v", err)
+			}
+			checkJSON(t, ms, tc.want)
+		})
+	}
+}
+
+func TestValidateIAMMember(t *testing.T) {
+	cases := []struct {
+		member  string
+		wantErr bool
+	}{
+		{member: "user:foo@my-domain.com"},
+		{member: "group:foo@my-domain.com"},
+		{member: "serviceAccount:foo@my-project.iam.gserviceaccount.com"},
+		{member: "domain:my-domain.com"},
+		{member: "allUsers"},
+		{member: "allAuthenticatedUsers"},
+		{member: "principal://iam.googleapis.com/projects/123/locations/global/workloadIdentityPools/foo-pool/subject/foo"},
+		{member: "principalSet://iam.googleapis.com/projects/123/locations/global/workloadIdentityPools/foo-pool/*"},
+		{member: "serviceacount:foo@my-project.iam.gserviceaccount.com", wantErr: true},
+		{member: "foo@my-domain.com", wantErr: true},
+		{member: "user:", wantErr: true},
+		{member: "allUsers:foo", wantErr: true},
+		{member: "", wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.member, func(t *testing.T) {
+			err := validateIAMMember(tc.member)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("validateIAMMember(%q) = %v, want error: %t", tc.member, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestProjectIAMMembersInitInvalidMember(t *testing.T) {
+	ms := &ProjectIAMMembers{Members: []*ProjectIAMMember{
+		{Role: "roles/viewer", Member: "group:foo@my-domain.com"},
+		{Role: "roles/editor", Member: "foo@my-domain.com"},
+	}}
+	err := ms.Init("my-project")
+	if err == nil {
+		t.Fatal("ms.Init = nil, want error")
+	}
+	for _, want := range []string{"roles/editor", "foo@my-domain.com"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("ms.Init = %v, want error containing %q", err, want)
+		}
+	}
+}
+
+func TestProjectIAMMembersValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		m       *ProjectIAMMember
+		wantErr bool
+	}{
+		{name: "valid", m: &ProjectIAMMember{Role: "roles/viewer", Member: "group:foo@my-domain.com"}},
+		{
+			name: "valid_condition",
+			m: &ProjectIAMMember{Role: "roles/viewer", Member: "group:foo@my-domain.com", Condition: &IAMCondition{
+				Title:      "expires",
+				Expression: `request.time < timestamp("2021-01-01T00:00:00Z")`,
+			}},
+		},
+		{name: "no_role", m: &ProjectIAMMember{Member: "group:foo@my-domain.com"}, wantErr: true},
+		{name: "invalid_member", m: &ProjectIAMMember{Role: "roles/viewer", Member: "foo@my-domain.com"}, wantErr: true},
+		{
+			name:    "condition_without_expression",
+			m:       &ProjectIAMMember{Role: "roles/viewer", Member: "group:foo@my-domain.com", Condition: &IAMCondition{Title: "expires"}},
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ms := &ProjectIAMMembers{Members: []*ProjectIAMMember{
+				{Role: "roles/editor", Member: "group:bar@my-domain.com"},
+				tc.m,
+			}}
+			err := ms.Validate()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("ms.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestProjectIAMMembersToBindings(t *testing.T) {
+	ms := &ProjectIAMMembers{
+		Members: []*ProjectIAMMember{
+			{Role: "roles/viewer", Member: "group:foo@my-domain.com", DependsOn: []string{"google_service_account.foo-account"}},
+			{Role: "roles/editor", Member: "user:bar@my-domain.com"},
+			{Role: "roles/viewer", Member: "group:baz@my-domain.com", DependsOn: []string{"google_storage_bucket.foo-bucket"}},
+			{Role: "roles/viewer", Member: "group:qux@my-domain.com", Condition: &IAMCondition{Title: "expires", Expression: "true"}},
+		},
+		DependsOn: []string{"google_project_service.services"},
+	}
+	var got []*ProjectIAMBinding
+	captureLog(t, func() { got = ms.ToBindings() })
+	want := []*ProjectIAMBinding{
+		{
+			Role:      "roles/viewer",
+			Members:   []string{"group:foo@my-domain.com", "group:baz@my-domain.com"},
+			DependsOn: []string{"google_project_service.services", "google_service_account.foo-account", "google_storage_bucket.foo-bucket"},
+		},
+		{
+			Role:      "roles/editor",
+			Members:   []string{"user:bar@my-domain.com"},
+			DependsOn: []string{"google_project_service.services"},
+		},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("ms.ToBindings differs (-got +want):\n%v", diff)
+	}
+}
+
+func TestFolderIAMMembers(t *testing.T) {
+	ms := new(FolderIAMMembers)
+	input := `[
+  {"role": "roles/viewer", "member": "group:foo@my-domain.com"},
+  {"role": "roles/browser", "member": "group:foo@my-domain.com"}
+]`
+	if err := json.Unmarshal([]byte(input), ms); err != nil {
+		t.Fatalf("json.Unmarshal = %v", err)
+	}
+	ms.DependsOn = []string{"google_folder.folder"}
+	if err := ms.Init("folders/123"); err != nil {
+		t.Fatalf("ms.Init = %v", err)
+	}
+
+	want := `{
+  "for_each": {
+    "roles/viewer group:foo@my-domain.com": {
+      "role": "roles/viewer",
+      "member": "group:foo@my-domain.com"
+    },
+    "roles/browser group:foo@my-domain.com": {
+      "role": "roles/browser",
+      "member": "group:foo@my-domain.com"
+    }
+  },
+  "folder": "folders/123",
+  "role": "${each.value.role}",
+  "member": "${each.value.member}",
+  "depends_on": ["google_folder.folder"]
+}`
+	checkJSON(t, ms, want)
+
+	if got, want := ms.ID(), "folder"; got != want {
+		t.Errorf("ms.ID() = %v, want %v", got, want)
+	}
+	if got, want := ms.ResourceType(), "google_folder_iam_member"; got != want {
+		t.Errorf("ms.ResourceType() = %v, want %v", got, want)
+	}
+}
+
+func TestOrganizationIAMMembers(t *testing.T) {
+	ms := &OrganizationIAMMembers{
+		Members: []*OrganizationIAMMember{
+			{Role: "roles/orgpolicy.policyAdmin", Member: "group:foo@my-domain.com"},
+		},
+		DependsOn: []string{"google_project.project"},
+	}
+	if err := ms.Init("12345678"); err != nil {
+		t.Fatalf("ms.Init = %v", err)
+	}
+	if err := ms.Validate(); err != nil {
+		t.Fatalf("ms.Validate = %v", err)
+	}
+
+	want := `{
+  "for_each": {
+    "roles/orgpolicy.policyAdmin group:foo@my-domain.com": {
+      "role": "roles/orgpolicy.policyAdmin",
+      "member": "group:foo@my-domain.com"
+    }
+  },
+  "org_id": "12345678",
+  "role": "${each.value.role}",
+  "member": "${each.value.member}",
+  "depends_on": ["google_project.project"]
+}`
+	checkJSON(t, ms, want)
+}
+
+func TestOrganizationIAMMembersValidateEmptyOrgID(t *testing.T) {
+	ms := &OrganizationIAMMembers{
+		Members: []*OrganizationIAMMember{
+			{Role: "roles/orgpolicy.policyAdmin", Member: "group:foo@my-domain.com"},
+		},
+	}
+	if err := ms.Init(""); err != nil {
+		t.Fatalf("ms.Init = %v", err)
+	}
+	if err := ms.Validate(); err == nil {
+		t.Error("ms.Validate = nil, want error")
+	}
+}
+
+func TestServiceAccountIAMMembers(t *testing.T) {
+	sa := "projects/my-project/serviceAccounts/target@my-project.iam.gserviceaccount.com"
+	ms := &ServiceAccountIAMMembers{Members: []*ServiceAccountIAMMember{
+		{ServiceAccountID: sa, Role: "roles/iam.serviceAccountTokenCreator", Member: "serviceAccount:foo@my-project.iam.gserviceaccount.com"},
+		{ServiceAccountID: sa, Role: "roles/iam.serviceAccountTokenCreator", Member: "serviceAccount:bar@my-project.iam.gserviceaccount.com"},
+	}}
+	if err := ms.Init("my-project"); err != nil {
+		t.Fatalf("ms.Init = %v", err)
+	}
+	if err := ms.Validate(); err != nil {
+		t.Fatalf("ms.Validate = %v", err)
+	}
+
+	want := `{
+  "for_each": {
+    "projects/my-project/serviceAccounts/target@my-project.iam.gserviceaccount.com roles/iam.serviceAccountTokenCreator serviceAccount:foo@my-project.iam.gserviceaccount.com": {
+      "service_account_id": "projects/my-project/serviceAccounts/target@my-project.iam.gserviceaccount.com",
+      "role": "roles/iam.serviceAccountTokenCreator",
+      "member": "serviceAccount:foo@my-project.iam.gserviceaccount.com"
+    },
+    "projects/my-project/serviceAccounts/target@my-project.iam.gserviceaccount.com roles/iam.serviceAccountTokenCreator serviceAccount:bar@my-project.iam.gserviceaccount.com": {
+      "service_account_id": "projects/my-project/serviceAccounts/target@my-project.iam.gserviceaccount.com",
+      "role": "roles/iam.serviceAccountTokenCreator",
+      "member": "serviceAccount:bar@my-project.iam.gserviceaccount.com"
+    }
+  },
+  "service_account_id": "${each.value.service_account_id}",
+  "role": "${each.value.role}",
+  "member": "${each.value.member}"
+}`
+	checkJSON(t, ms, want)
+}
+
+func TestServiceAccountIAMMembersReferenceKey(t *testing.T) {
+	m := &ServiceAccountIAMMember{ServiceAccountID: "${google_service_account.target.name}", Role: "roles/iam.serviceAccountUser", Member: "group:foo@my-domain.com"}
+	if got, want := m.forEachKey(), "target roles/iam.serviceAccountUser group:foo@my-domain.com"; got != want {
+		t.Errorf("m.forEachKey() = %q, want %q", got, want)
+	}
+}
+
+func TestServiceAccountIAMMembersValidate(t *testing.T) {
+	cases := []struct {
+		id      string
+		wantErr bool
+	}{
+		{id: "projects/my-project/serviceAccounts/target@my-project.iam.gserviceaccount.com"},
+		{id: "${google_service_account.target.name}"},
+		{id: "target@my-project.iam.gserviceaccount.com", wantErr: true},
+		{id: "projects/my-project/target@my-project.iam.gserviceaccount.com", wantErr: true},
+		{id: "", wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.id, func(t *testing.T) {
+			ms := &ServiceAccountIAMMembers{Members: []*ServiceAccountIAMMember{
+				{ServiceAccountID: tc.id, Role: "roles/iam.serviceAccountUser", Member: "group:foo@my-domain.com"},
+			}}
+			err := ms.Validate()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("ms.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestProjectIAMCustomRole(t *testing.T) {
+	r := new(ProjectIAMCustomRole)
+	input := `{
+  "role_id": "deidPipeline",
+  "title": "De-identification Pipeline",
+  "description": "Runs healthcare de-identification operations",
+  "permissions": ["healthcare.datasets.deidentify", "healthcare.operations.get"],
+  "stage": "GA"
+}`
+	if err := json.Unmarshal([]byte(input), r); err != nil {
+		t.Fatalf("json.Unmarshal = %v", err)
+	}
+	if err := r.Init("my-project"); err != nil {
+		t.Fatalf("r.Init = %v", err)
+	}
+	if err := r.Validate(); err != nil {
+		t.Fatalf("r.Validate = %v", err)
+	}
+
+	want := `{
+  "role_id": "deidPipeline",
+  "project": "my-project",
+  "title": "De-identification Pipeline",
+  "description": "Runs healthcare de-identification operations",
+  "permissions": ["healthcare.datasets.deidentify", "healthcare.operations.get"],
+  "stage": "GA"
+}`
+	checkJSON(t, r, want)
+
+	ms := &ProjectIAMMembers{Members: []*ProjectIAMMember{{
+		Role:   fmt.Sprintf("${%s.%s.id}", r.ResourceType(), r.ID()),
+		Member: "serviceAccount:deid@my-project.iam.gserviceaccount.com",
+	}}}
+	if err := ms.Init("my-project"); err != nil {
+		t.Fatalf("ms.Init = %v", err)
+	}
+	wantMembers := `{
+  "for_each": {
+    "${google_project_iam_custom_role.deidPipeline.id} serviceAccount:deid@my-project.iam.gserviceaccount.com": {
+      "role": "${google_project_iam_custom_role.deidPipeline.id}",
+      "member": "serviceAccount:deid@my-project.iam.gserviceaccount.com"
+    }
+  },
+  "project": "my-project",
+  "role": "${each.value.role}",
+  "member": "${each.value.member}"
+}`
+	checkJSON(t, ms, wantMembers)
+}
+
+func TestProjectIAMCustomRoleValidate(t *testing.T) {
+	cases := []struct {
+		name        string
+		permissions []string
+		wantErr     bool
+	}{
+		{name: "valid", permissions: []string{"iam.roles.list"}},
+		{name: "no_permissions", wantErr: true},
+		{name: "malformed_permission", permissions: []string{"iam.roles.list", "list"}, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &ProjectIAMCustomRole{RoleID: "myRole", Title: "My Role", Permissions: tc.permissions}
+			err := r.Validate()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("r.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestProjectIAMBinding(t *testing.T) {
+	b := &ProjectIAMBinding{
+		Role:    "roles/owner",
+		Members: []string{"group:owners@my-domain.com", "user:admin@my-domain.com"},
+	}
+	if err := b.Init("my-project"); err != nil {
+		t.Fatalf("b.Init = %v", err)
+	}
+	if err := b.Validate(); err != nil {
+		t.Fatalf("b.Validate = %v", err)
+	}
+
+	want := `{
+  "role": "roles/owner",
+  "members": ["group:owners@my-domain.com", "user:admin@my-domain.com"],
+  "project": "my-project"
+}`
+	checkJSON(t, b, want)
+
+	if got, want := b.ID(), "roles_owner"; got != want {
+		t.Errorf("b.ID() = %v, want %v", got, want)
+	}
+}
+
+func TestCheckIAMBindingConflicts(t *testing.T) {
+	binding := &ProjectIAMBinding{Role: "roles/owner", Members: []string{"group:owners@my-domain.com"}}
+	cases := []struct {
+		name    string
+		members []*ProjectIAMMember
+		wantErr bool
+	}{
+		{
+			name:    "different_roles",
+			members: []*ProjectIAMMember{{Role: "roles/viewer", Member: "group:viewers@my-domain.com"}},
+		},
+		{
+			name: "same_role",
+			members: []*ProjectIAMMember{
+				{Role: "roles/viewer", Member: "group:viewers@my-domain.com"},
+				{Role: "roles/owner", Member: "user:admin@my-domain.com"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rs := []Resource{binding, &ProjectIAMMembers{Members: tc.members}}
+			err := CheckIAMBindingConflicts(rs)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("CheckIAMBindingConflicts = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestServiceAccountIAMBinding(t *testing.T) {
+	b := &ServiceAccountIAMBinding{
+		ServiceAccountID: "${google_service_account.foo-account.name}",
+		Role:             "roles/iam.serviceAccountTokenCreator",
+		Members:          []string{"group:token-creators@my-domain.com", "serviceAccount:etl@my-project.iam.gserviceaccount.com"},
+	}
+	if err := b.Init("my-project"); err != nil {
+		t.Fatalf("b.Init = %v", err)
+	}
+	if err := b.Validate(); err != nil {
+		t.Fatalf("b.Validate = %v", err)
+	}
+
+	want := `{
+  "service_account_id": "${google_service_account.foo-account.name}",
+  "role": "roles/iam.serviceAccountTokenCreator",
+  "members": ["group:token-creators@my-domain.com", "serviceAccount:etl@my-project.iam.gserviceaccount.com"]
+}`
+	checkJSON(t, b, want)
+
+	if got, want := b.ID(), "foo-account_roles_iam_serviceaccounttokencreator"; got != want {
+		t.Errorf("b.ID() = %v, want %v", got, want)
+	}
+}
+
+func TestServiceAccountIAMBindingValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		b       *ServiceAccountIAMBinding
+		wantErr bool
+	}{
+		{
+			name: "full_name",
+			b: &ServiceAccountIAMBinding{
+				ServiceAccountID: "projects/my-project/serviceAccounts/foo@my-project.iam.gserviceaccount.com",
+				Role:             "roles/iam.serviceAccountUser",
+				Members:          []string{"group:users@my-domain.com"},
+			},
+		},
+		{
+			name: "invalid_service_account_id",
+			b: &ServiceAccountIAMBinding{
+				ServiceAccountID: "foo@my-project.iam.gserviceaccount.com",
+				Role:             "roles/iam.serviceAccountUser",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid_member",
+			b: &ServiceAccountIAMBinding{
+				ServiceAccountID: "${google_service_account.foo.name}",
+				Role:             "roles/iam.serviceAccountUser",
+				Members:          []string{"users@my-domain.com"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.b.Init("my-project"); err != nil {
+				t.Fatalf("b.Init = %v", err)
+			}
+			err := tc.b.Validate()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("b.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestServiceAccountIAMBindingInitErrors(t *testing.T) {
+	for _, b := range []*ServiceAccountIAMBinding{
+		{Role: "roles/iam.serviceAccountUser"},
+		{ServiceAccountID: "${google_service_account.foo.name}"},
+	} {
+		if err := b.Init("my-project"); err == nil {
+			t.Errorf("b.Init(%+v) = nil, want error", b)
+		}
+	}
+}
+
+func TestCheckServiceAccountIAMBindingConflicts(t *testing.T) {
+	binding := &ServiceAccountIAMBinding{
+		ServiceAccountID: "${google_service_account.foo.name}",
+		Role:             "roles/iam.serviceAccountTokenCreator",
+		Members:          []string{"group:token-creators@my-domain.com"},
+	}
+	cases := []struct {
+		name    string
+		members []*ServiceAccountIAMMember
+		wantErr bool
+	}{
+		{
+			name: "different_roles",
+			members: []*ServiceAccountIAMMember{
+				{ServiceAccountID: "${google_service_account.foo.name}", Role: "roles/iam.serviceAccountUser", Member: "group:users@my-domain.com"},
+			},
+		},
+		{
+			name: "different_service_accounts",
+			members: []*ServiceAccountIAMMember{
+				{ServiceAccountID: "${google_service_account.bar.name}", Role: "roles/iam.serviceAccountTokenCreator", Member: "group:users@my-domain.com"},
+			},
+		},
+		{
+			name: "same_service_account_and_role",
+			members: []*ServiceAccountIAMMember{
+				{ServiceAccountID: "${google_service_account.foo.name}", Role: "roles/iam.serviceAccountTokenCreator", Member: "user:admin@my-domain.com"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "same_service_account_by_name_and_role",
+			members: []*ServiceAccountIAMMember{
+				{ServiceAccountID: "projects/my-project/serviceAccounts/foo@my-project.iam.gserviceaccount.com", Role: "roles/iam.serviceAccountTokenCreator", Member: "user:admin@my-domain.com"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rs := []Resource{binding, &ServiceAccountIAMMembers{Members: tc.members}}
+			err := CheckIAMBindingConflicts(rs)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("CheckIAMBindingConflicts = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestProjectIAMAuditConfig(t *testing.T) {
+	c := &ProjectIAMAuditConfig{
+		Service: "healthcare.googleapis.com",
+		AuditLogConfigs: []*AuditLogConfig{
+			{LogType: "DATA_READ", ExemptedMembers: []string{"serviceAccount:etl@my-project.iam.gserviceaccount.com"}},
+			{LogType: "DATA_WRITE"},
+		},
+	}
+	if err := c.Init("my-project"); err != nil {
+		t.Fatalf("c.Init = %v", err)
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("c.Validate = %v", err)
+	}
+
+	want := `{
+  "project": "my-project",
+  "service": "healthcare.googleapis.com",
+  "audit_log_config": [
+    {
+      "log_type": "DATA_READ",
+      "exempted_members": ["serviceAccount:etl@my-project.iam.gserviceaccount.com"]
+    },
+    {"log_type": "DATA_WRITE"}
+  ]
+}`
+	checkJSON(t, c, want)
+
+	if got, want := c.ID(), "healthcare_googleapis_com"; got != want {
+		t.Errorf("c.ID() = %v, want %v", got, want)
+	}
+
+	all := &ProjectIAMAuditConfig{Service: "allServices"}
+	if got, want := all.ID(), "project"; got != want {
+		t.Errorf("all.ID() = %v, want %v", got, want)
+	}
+}
+
+func TestProjectIAMAuditConfigValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		configs []*AuditLogConfig
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			configs: []*AuditLogConfig{{LogType: "ADMIN_READ"}, {LogType: "DATA_READ"}, {LogType: "DATA_WRITE"}},
+		},
+		{
+			name:    "invalid_log_type",
+			configs: []*AuditLogConfig{{LogType: "DATA_DELETE"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid_exempted_member",
+			configs: []*AuditLogConfig{{LogType: "DATA_READ", ExemptedMembers: []string{"foo@my-domain.com"}}},
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &ProjectIAMAuditConfig{Service: "allServices", AuditLogConfigs: tc.configs}
+			err := c.Validate()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("c.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestServiceAccountInit(t *testing.T) {
+	cases := []struct {
+		name      string
+		accountID string
+		wantErr   bool
+	}{
+		{name: "valid", accountID: "foo-account"},
+		{name: "min_length", accountID: "abcdef"},
+		{name: "max_length", accountID: "a" + strings.Repeat("b", 29)},
+		{name: "empty", accountID: "", wantErr: true},
+		{name: "too_short", accountID: "abcde", wantErr: true},
+		{name: "too_long", accountID: "a" + strings.Repeat("b", 30), wantErr: true},
+		{name: "leading_digit", accountID: "1foo-account", wantErr: true},
+		{name: "leading_hyphen", accountID: "-foo-account", wantErr: true},
+		{name: "trailing_hyphen", accountID: "foo-account-", wantErr: true},
+		{name: "uppercase", accountID: "Foo-Account", wantErr: true},
+		{name: "underscore", accountID: "foo_account", wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := &ServiceAccount{AccountID: tc.accountID}
+			err := a.Init("my-project")
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("a.Init = %v, want error: %t", err, tc.wantErr)
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), fmt.Sprintf("%q", tc.accountID)) {
+					t.Errorf("a.Init = %v, want error containing %q", err, tc.accountID)
+				}
+				return
+			}
+			if a.Project != "my-project" {
+				t.Errorf("a.Project = %q, want %q", a.Project, "my-project")
+			}
+		})
+	}
+}
+
+func TestServiceAccountInitProject(t *testing.T) {
+	cases := []struct {
+		name     string
+		provider string
+		wantErr  bool
+	}{
+		{name: "provider_alias", provider: "google.project_a"},
+		{name: "default_provider", wantErr: true},
+		{name: "beta_provider", provider: "google-beta", wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := &ServiceAccount{AccountID: "foo-account", Project: "project-a", Provider: tc.provider}
+			if err := a.Init("my-project"); (err != nil) != tc.wantErr {
+				t.Errorf("a.Init = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestServiceAccount(t *testing.T) {
+	cases := []struct {
+		name string
+		a    *ServiceAccount
+		want string
+	}{
+		{
+			name: "zero_values",
+			a:    &ServiceAccount{AccountID: "foo-account", DisplayName: "Foo"},
+			want: `{
+  "account_id": "foo-account",
+  "project": "my-project",
+  "display_name": "Foo"
+}`,
+		},
+		{
+			name: "description_and_disabled",
+			a:    &ServiceAccount{AccountID: "foo-account", DisplayName: "Foo", Description: "Runs the ETL pipeline.", Disabled: true},
+			want: `{
+  "account_id": "foo-account",
+  "project": "my-project",
+  "display_name": "Foo",
+  "description": "Runs the ETL pipeline.",
+  "disabled": true
+}`,
+		},
+		{
+			name: "provider_alias",
+			a:    &ServiceAccount{AccountID: "foo-account", DisplayName: "Foo", Project: "project-a", Provider: "google.project_a"},
+			want: `{
+  "account_id": "foo-account",
+  "project": "project-a",
+  "provider": "google.project_a",
+  "display_name": "Foo"
+}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.a.Init("my-project"); err != nil {
+				t.Fatalf("a.Init = %v", err)
+			}
+			checkJSON(t, tc.a, tc.want)
+			if got, want := tc.a.ID(), "foo-account"; got != want {
+				t.Errorf("a.ID() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestRef(t *testing.T) {
+	cases := []struct {
+		name string
+		r    Resource
+		attr string
+		want string
+	}{
+		{
+			name: "service_account",
+			r:    &ServiceAccount{AccountID: "foo-account"},
+			attr: "email",
+			want: "${google_service_account.foo-account.email}",
+		},
+		{
+			name: "project_iam_binding",
+			r:    &ProjectIAMBinding{Role: "roles/storage.objectViewer"},
+			attr: "etag",
+			want: "${google_project_iam_binding.roles_storage_objectviewer.etag}",
+		},
+		{
+			name: "project_iam_members",
+			r:    &ProjectIAMMembers{Members: []*ProjectIAMMember{{Role: "roles/viewer", Member: "group:foo@my-domain.com"}}},
+			attr: "etag",
+			want: "${google_project_iam_member.project.etag}",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Ref(tc.r, tc.attr); got != tc.want {
+				t.Errorf("Ref(%v, %q) = %q, want %q", tc.r, tc.attr, got, tc.want)
+			}
+		})
+	}
+
+	a := &ServiceAccount{AccountID: "foo-account"}
+	if got, want := a.Ref("email"), "${google_service_account.foo-account.email}"; got != want {
+		t.Errorf("a.Ref(%q) = %q, want %q", "email", got, want)
+	}
+}
+
+func TestServiceAccountImportID(t *testing.T) {
+	a := &ServiceAccount{AccountID: "foo-account"}
+	if err := a.Init("my-project"); err != nil {
+		t.Fatalf("a.Init = %v", err)
+	}
+	var i Importer = a
+	got, err := i.ImportID(nil)
+	if err != nil {
+		t.Fatalf("a.ImportID = %v", err)
+	}
+	if want := "projects/my-project/serviceAccounts/foo-account@my-project.iam.gserviceaccount.com"; got != want {
+		t.Errorf("a.ImportID = %q, want %q", got, want)
+	}
+}
+
+func TestServiceAccountUnmarshalStrict(t *testing.T) {
+	a := new(ServiceAccount)
+	if err := a.UnmarshalStrict([]byte(`{"account_id": "foo-account", "display_name": "Foo"}`)); err != nil {
+		t.Fatalf("a.UnmarshalStrict = %v", err)
+	}
+	if a.AccountID != "foo-account" || a.DisplayName != "Foo" {
+		t.Errorf("a = %+v, want account_id and display_name set", a)
+	}
+
+	err := new(ServiceAccount).UnmarshalStrict([]byte(`{"account_id": "foo-account", "dispaly_name": "Foo"}`))
+	if err == nil || !strings.Contains(err.Error(), `"dispaly_name"`) {
+		t.Errorf("a.UnmarshalStrict = %v, want error naming %q", err, "dispaly_name")
+	}
+}
+
+func TestServiceAccountEmail(t *testing.T) {
+	a := &ServiceAccount{AccountID: "foo-account"}
+	if got := a.Email(); got != "" {
+		t.Errorf("a.Email() before Init = %q, want empty", got)
+	}
+	if err := a.Init("my-project"); err != nil {
+		t.Fatalf("a.Init = %v", err)
+	}
+	if got, want := a.Email(), "foo-account@my-project.iam.gserviceaccount.com"; got != want {
+		t.Errorf("a.Email() = %q, want %q", got, want)
+	}
+	if got, want := a.EmailRef(), "${google_service_account.foo-account.email}"; got != want {
+		t.Errorf("a.EmailRef() = %q, want %q", got, want)
+	}
+}
+
+func TestServiceAccountValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		a       *ServiceAccount
+		wantErr bool
+	}{
+		{name: "valid", a: &ServiceAccount{AccountID: "foo-account", Project: "my-project", DisplayName: "Foo Account"}},
+		{name: "no_project", a: &ServiceAccount{AccountID: "foo-account"}, wantErr: true},
+		{name: "long_display_name", a: &ServiceAccount{AccountID: "foo-account", Project: "my-project", DisplayName: strings.Repeat("a", 101)}, wantErr: true},
+		{name: "long_description", a: &ServiceAccount{AccountID: "foo-account", Project: "my-project", Description: strings.Repeat("a", 257)}, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.a.Validate()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("a.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestServiceAccountKey(t *testing.T) {
+	cases := []struct {
+		name string
+		k    *ServiceAccountKey
+		want string
+	}{
+		{
+			name: "default_algorithm",
+			k:    &ServiceAccountKey{ServiceAccountID: "${google_service_account.foo-account.name}"},
+			want: `{
+  "service_account_id": "${google_service_account.foo-account.name}",
+  "key_algorithm": "KEY_ALG_RSA_2048"
+}`,
+		},
+		{
+			name: "explicit_algorithm",
+			k: &ServiceAccountKey{
+				ServiceAccountID: "${google_service_account.foo-account.name}",
+				KeyAlgorithm:     "KEY_ALG_RSA_1024",
+				PublicKeyType:    "TYPE_X509_PEM_FILE",
+			},
+			want: `{
+  "service_account_id": "${google_service_account.foo-account.name}",
+  "key_algorithm": "KEY_ALG_RSA_1024",
+  "public_key_type": "TYPE_X509_PEM_FILE"
+}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.k.Init("my-project"); err != nil {
+				t.Fatalf("k.Init = %v", err)
+			}
+			checkJSON(t, tc.k, tc.want)
+			if got, want := tc.k.ID(), "google_service_account_foo-account_name"; got != want {
+				t.Errorf("k.ID() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestServiceAccountKeyNames(t *testing.T) {
+	rs := []Resource{
+		&ServiceAccountKey{ServiceAccountID: "${google_service_account.foo-account.name}"},
+		&ServiceAccountKey{ServiceAccountID: "${google_service_account.foo-account.name}", Name: "rotated"},
+	}
+	for _, r := range rs {
+		if err := r.Init("my-project"); err != nil {
+			t.Fatalf("k.Init = %v", err)
+		}
+	}
+	if got, want := rs[1].ID(), "google_service_account_foo-account_name_rotated"; got != want {
+		t.Errorf("k.ID() = %v, want %v", got, want)
+	}
+	if err := CheckDuplicateIDs(rs); err != nil {
+		t.Errorf("CheckDuplicateIDs = %v, want nil for keys with different names", err)
+	}
+	checkJSON(t, rs[1], `{
+  "service_account_id": "${google_service_account.foo-account.name}",
+  "key_algorithm": "KEY_ALG_RSA_2048"
+}`)
+}
+
+func TestServiceAccountKeyString(t *testing.T) {
+	k := &ServiceAccountKey{ServiceAccountID: "${google_service_account.foo-account.name}"}
+	if err := k.Init("my-project"); err != nil {
+		t.Fatalf("k.Init = %v", err)
+	}
+	want := `{"key_algorithm":"KEY_ALG_RSA_2048","service_account_id":"${google_service_account.foo-account.name}"}`
+	if got := fmt.Sprint(k); got != want {
+		t.Errorf("fmt.Sprint(k) = %v, want %v", got, want)
+	}
+}
+
+func TestServiceAccountKeyValidate(t *testing.T) {
+	k := &ServiceAccountKey{ServiceAccountID: "${google_service_account.foo-account.name}"}
+	var err error
+	out := captureLog(t, func() { err = k.Validate() })
+	if err != nil {
+		t.Fatalf("k.Validate = %v", err)
+	}
+	if !strings.Contains(out, "google_service_account.foo-account.name") {
+		t.Errorf("k.Validate logged %q, want warning naming the service account", out)
+	}
+}
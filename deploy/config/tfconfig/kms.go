@@ -0,0 +1,167 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
+)
+
+// KMSKeyRing represents a Terraform KMS key ring.
+type KMSKeyRing struct {
+	Name      string     `json:"name"`
+	Project   string     `json:"project"`
+	Location  string     `json:"location"`
+	Lifecycle *Lifecycle `json:"lifecycle,omitempty"`
+}
+
+// Init initializes the resource.
+func (r *KMSKeyRing) Init(projectID string) error {
+	if r.Name == "" {
+		return errors.New("name must be set")
+	}
+	if r.Location == "" {
+		return errors.New("location must be set")
+	}
+	if r.Project != "" {
+		return fmt.Errorf("project must be unset: %v", r.Project)
+	}
+	r.Project = projectID
+	if r.Lifecycle == nil {
+		r.Lifecycle = &Lifecycle{PreventDestroy: true}
+	}
+	return nil
+}
+
+// Validate checks that the resource is valid.
+// Key rings are always valid but a warning is logged if their destruction is not prevented, as they can never be deleted once created.
+func (r *KMSKeyRing) Validate() error {
+	if r.Lifecycle == nil || !r.Lifecycle.PreventDestroy {
+		log.Printf("Key ring %q can never be deleted: consider preventing its destruction so it is not lost from the terraform state", r.Name)
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (r *KMSKeyRing) ID() string {
+	return r.Name
+}
+
+// ResourceType returns the resource terraform provider type.
+func (r *KMSKeyRing) ResourceType() string {
+	return "google_kms_key_ring"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (r *KMSKeyRing) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("%s/%s/%s", r.Project, r.Location, r.Name), nil
+}
+
+// kmsKeyPurposes are the valid values of a crypto key's purpose.
+var kmsKeyPurposes = map[string]bool{
+	"ENCRYPT_DECRYPT":    true,
+	"ASYMMETRIC_SIGN":    true,
+	"ASYMMETRIC_DECRYPT": true,
+	"MAC":                true,
+}
+
+// kmsCryptoKeyNameRE matches the full resource name of a crypto key.
+var kmsCryptoKeyNameRE = regexp.MustCompile(`^projects/[^/]+/locations/[^/]+/keyRings/[^/]+/cryptoKeys/[^/]+$`)
+
+// validateKMSCryptoKeyName checks that the name is a terraform reference (e.g. Ref(key, "id")) or the full resource name of a crypto key.
+func validateKMSCryptoKeyName(name string) error {
+	if strings.HasPrefix(name, "${") || kmsCryptoKeyNameRE.MatchString(name) {
+		return nil
+	}
+	return fmt.Errorf("invalid crypto key name %q: must be a terraform reference or of the form projects/<project>/locations/<location>/keyRings/<key ring>/cryptoKeys/<key>", name)
+}
+
+// minRotationPeriodSeconds is the minimum rotation period of a crypto key allowed by GCP (24 hours).
+const minRotationPeriodSeconds = 86400
+
+// KMSCryptoKey represents a Terraform KMS crypto key.
+type KMSCryptoKey struct {
+	Name string `json:"name"`
+
+	// KeyRing is the key ring's terraform reference or the name of a key ring in the deployment.
+	KeyRing string `json:"key_ring"`
+
+	Purpose         string              `json:"purpose,omitempty"`
+	RotationPeriod  string              `json:"rotation_period,omitempty"`
+	VersionTemplate *KMSVersionTemplate `json:"version_template,omitempty"`
+	Lifecycle       *Lifecycle          `json:"lifecycle,omitempty"`
+}
+
+// KMSVersionTemplate represents a crypto key version template.
+type KMSVersionTemplate struct {
+	Algorithm       string `json:"algorithm"`
+	ProtectionLevel string `json:"protection_level,omitempty"`
+}
+
+// Init initializes the resource.
+// Crypto keys do not have a project field so the project ID is unused.
+func (k *KMSCryptoKey) Init(string) error {
+	if k.Name == "" {
+		return errors.New("name must be set")
+	}
+	if k.KeyRing == "" {
+		return errors.New("key_ring must be set")
+	}
+	k.KeyRing = nameRef("google_kms_key_ring", k.KeyRing, "id")
+	if k.Lifecycle == nil {
+		k.Lifecycle = &Lifecycle{PreventDestroy: true}
+	}
+	return nil
+}
+
+// Validate checks that the resource is valid.
+// A warning is also logged if the crypto key's destruction is not prevented, as crypto keys can never be deleted once created.
+func (k *KMSCryptoKey) Validate() error {
+	if k.Purpose != "" && !kmsKeyPurposes[k.Purpose] {
+		return fmt.Errorf("invalid purpose %q for crypto key %q: must be one of ENCRYPT_DECRYPT, ASYMMETRIC_SIGN, ASYMMETRIC_DECRYPT or MAC", k.Purpose, k.Name)
+	}
+	if k.RotationPeriod != "" {
+		if !durationRE.MatchString(k.RotationPeriod) {
+			return fmt.Errorf("rotation period %q of crypto key %q must be a duration in seconds, e.g. \"7776000s\"", k.RotationPeriod, k.Name)
+		}
+		secs, err := strconv.ParseFloat(strings.TrimSuffix(k.RotationPeriod, "s"), 64)
+		if err != nil {
+			return fmt.Errorf("failed to parse rotation period %q of crypto key %q: %v", k.RotationPeriod, k.Name, err)
+		}
+		if secs < minRotationPeriodSeconds {
+			return fmt.Errorf("rotation period %q of crypto key %q must be at least %ds", k.RotationPeriod, k.Name, minRotationPeriodSeconds)
+		}
+	}
+	if k.Lifecycle == nil || !k.Lifecycle.PreventDestroy {
+		log.Printf("Crypto key %q can never be deleted: consider preventing its destruction so it is not lost from the terraform state", k.Name)
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (k *KMSCryptoKey) ID() string {
+	return k.Name
+}
+
+// ResourceType returns the resource terraform provider type.
+func (k *KMSCryptoKey) ResourceType() string {
+	return "google_kms_crypto_key"
+}
@@ -0,0 +1,161 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKMSKeyRing(t *testing.T) {
+	r := &KMSKeyRing{Name: "foo-ring", Location: "us-central1"}
+	if err := r.Init("my-project"); err != nil {
+		t.Fatalf("r.Init = %v", err)
+	}
+
+	want := `{
+  "name": "foo-ring",
+  "project": "my-project",
+  "location": "us-central1",
+  "lifecycle": {"prevent_destroy": true}
+}`
+	checkJSON(t, r, want)
+
+	var err error
+	out := captureLog(t, func() { err = r.Validate() })
+	if err != nil {
+		t.Fatalf("r.Validate = %v", err)
+	}
+	if out != "" {
+		t.Errorf("r.Validate logged %q, want no warning when destruction is prevented", out)
+	}
+}
+
+func TestKMSKeyRingDestroyable(t *testing.T) {
+	r := &KMSKeyRing{Name: "foo-ring", Location: "us-central1", Lifecycle: &Lifecycle{}}
+	if err := r.Init("my-project"); err != nil {
+		t.Fatalf("r.Init = %v", err)
+	}
+	checkJSON(t, r, `{
+  "name": "foo-ring",
+  "project": "my-project",
+  "location": "us-central1",
+  "lifecycle": {}
+}`)
+
+	var err error
+	out := captureLog(t, func() { err = r.Validate() })
+	if err != nil {
+		t.Fatalf("r.Validate = %v", err)
+	}
+	if !strings.Contains(out, "foo-ring") {
+		t.Errorf("r.Validate logged %q, want warning naming the key ring", out)
+	}
+}
+
+func TestKMSKeyRingInitErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		r    *KMSKeyRing
+	}{
+		{name: "no_name", r: &KMSKeyRing{Location: "us-central1"}},
+		{name: "no_location", r: &KMSKeyRing{Name: "foo-ring"}},
+		{name: "project_set", r: &KMSKeyRing{Name: "foo-ring", Location: "us-central1", Project: "other-project"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.r.Init("my-project"); err == nil {
+				t.Error("r.Init = nil, want error")
+			}
+		})
+	}
+}
+
+func TestKMSCryptoKey(t *testing.T) {
+	k := &KMSCryptoKey{
+		Name:           "foo-key",
+		KeyRing:        "foo-ring",
+		Purpose:        "ENCRYPT_DECRYPT",
+		RotationPeriod: "7776000s",
+		VersionTemplate: &KMSVersionTemplate{
+			Algorithm:       "GOOGLE_SYMMETRIC_ENCRYPTION",
+			ProtectionLevel: "HSM",
+		},
+	}
+	if err := k.Init("my-project"); err != nil {
+		t.Fatalf("k.Init = %v", err)
+	}
+	var err error
+	out := captureLog(t, func() { err = k.Validate() })
+	if err != nil {
+		t.Fatalf("k.Validate = %v", err)
+	}
+	if out != "" {
+		t.Errorf("k.Validate logged %q, want no warning when destruction is prevented", out)
+	}
+
+	want := `{
+  "name": "foo-key",
+  "key_ring": "${google_kms_key_ring.foo-ring.id}",
+  "purpose": "ENCRYPT_DECRYPT",
+  "rotation_period": "7776000s",
+  "version_template": {
+    "algorithm": "GOOGLE_SYMMETRIC_ENCRYPTION",
+    "protection_level": "HSM"
+  },
+  "lifecycle": {"prevent_destroy": true}
+}`
+	checkJSON(t, k, want)
+}
+
+func TestKMSCryptoKeyDestroyable(t *testing.T) {
+	k := &KMSCryptoKey{Name: "foo-key", KeyRing: "foo-ring", Lifecycle: &Lifecycle{IgnoreChanges: []string{"labels"}}}
+	if err := k.Init("my-project"); err != nil {
+		t.Fatalf("k.Init = %v", err)
+	}
+	var err error
+	out := captureLog(t, func() { err = k.Validate() })
+	if err != nil {
+		t.Fatalf("k.Validate = %v", err)
+	}
+	if !strings.Contains(out, "foo-key") {
+		t.Errorf("k.Validate logged %q, want warning naming the crypto key", out)
+	}
+}
+
+func TestKMSCryptoKeyValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		k       *KMSCryptoKey
+		wantErr bool
+	}{
+		{name: "defaults", k: &KMSCryptoKey{}},
+		{name: "min_rotation_period", k: &KMSCryptoKey{RotationPeriod: "86400s"}},
+		{name: "short_rotation_period", k: &KMSCryptoKey{RotationPeriod: "86399.5s"}, wantErr: true},
+		{name: "malformed_rotation_period", k: &KMSCryptoKey{RotationPeriod: "90d"}, wantErr: true},
+		{name: "asymmetric_sign", k: &KMSCryptoKey{Purpose: "ASYMMETRIC_SIGN"}},
+		{name: "invalid_purpose", k: &KMSCryptoKey{Purpose: "SIGN"}, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.k.Name = "foo-key"
+			var err error
+			captureLog(t, func() { err = tc.k.Validate() })
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("k.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
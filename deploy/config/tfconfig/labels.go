@@ -0,0 +1,44 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+// Labeled is implemented by resources that support labels.
+type Labeled interface {
+	GetLabels() map[string]string
+	SetLabels(map[string]string)
+}
+
+// MergeCommonLabels merges the common labels into the labels of each of the given resources that implement Labeled.
+// Labels set on a resource take precedence over common labels with the same key.
+// Each resource is given its own copy of the labels, and labels are marshalled with sorted keys, so the output is deterministic.
+func MergeCommonLabels(rs []Resource, common map[string]string) {
+	if len(common) == 0 {
+		return
+	}
+	for _, r := range rs {
+		l, ok := r.(Labeled)
+		if !ok {
+			continue
+		}
+		merged := make(map[string]string, len(common))
+		for k, v := range common {
+			merged[k] = v
+		}
+		for k, v := range l.GetLabels() {
+			merged[k] = v
+		}
+		l.SetLabels(merged)
+	}
+}
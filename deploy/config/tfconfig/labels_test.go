@@ -0,0 +1,70 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMergeCommonLabels(t *testing.T) {
+	common := map[string]string{
+		"data-classification": "phi",
+		"environment":         "prod",
+	}
+	b := &StorageBucket{Name: "foo-bucket", Labels: map[string]string{"environment": "dev", "team": "foo"}}
+	d := &BigqueryDataset{DatasetID: "foo_dataset"}
+	sa := &ServiceAccount{AccountID: "foo-account"}
+	MergeCommonLabels([]Resource{b, d, sa}, common)
+
+	wantBucket := map[string]string{"data-classification": "phi", "environment": "dev", "team": "foo"}
+	if diff := cmp.Diff(b.Labels, wantBucket); diff != "" {
+		t.Errorf("bucket labels differ (-got +want):\n%v", diff)
+	}
+	if diff := cmp.Diff(d.Labels, common); diff != "" {
+		t.Errorf("dataset labels differ (-got +want):\n%v", diff)
+	}
+
+	// Resources must not share the common labels map.
+	d.Labels["owner"] = "bar"
+	if _, ok := common["owner"]; ok {
+		t.Error("MergeCommonLabels shared the common labels map with a resource")
+	}
+}
+
+func TestMergeCommonLabelsDeterministic(t *testing.T) {
+	common := map[string]string{"environment": "prod", "data-classification": "phi", "cost-center": "123"}
+	var want string
+	for i := 0; i < 10; i++ {
+		b := &StorageBucket{Name: "foo-bucket", Labels: map[string]string{"zone": "a", "app": "foo"}}
+		MergeCommonLabels([]Resource{b}, common)
+		got, err := json.Marshal(b.Labels)
+		if err != nil {
+			t.Fatalf("json.Marshal = %v", err)
+		}
+		if i == 0 {
+			want = string(got)
+			continue
+		}
+		if string(got) != want {
+			t.Fatalf("json.Marshal = %s, want %s", got, want)
+		}
+	}
+	if wantSorted := `{"app":"foo","cost-center":"123","data-classification":"phi","environment":"prod","zone":"a"}`; want != wantSorted {
+		t.Errorf("json.Marshal = %s, want %s", want, wantSorted)
+	}
+}
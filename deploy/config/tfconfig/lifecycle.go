@@ -0,0 +1,72 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Lifecycle represents a Terraform lifecycle meta-argument block.
+// Resources that need a default carry one through a "lifecycle" field, which is marshalled alongside the resource's own fields.
+// Any other resource that keeps its raw definition passes a user set "lifecycle" block through the merge in MergedMap.
+// See https://www.terraform.io/docs/configuration/resources.html#lifecycle-lifecycle-customizations.
+type Lifecycle struct {
+	PreventDestroy      bool `json:"prevent_destroy,omitempty"`
+	CreateBeforeDestroy bool `json:"create_before_destroy,omitempty"`
+
+	// IgnoreChanges holds the bare names of the attributes to ignore, e.g. "labels" or "all".
+	IgnoreChanges []string `json:"ignore_changes,omitempty"`
+}
+
+// MarshalJSON marshals the lifecycle block.
+// Terraform expects ignore_changes to hold attribute names rather than strings or interpolations,
+// so any surrounding quotes or "${...}" wrappers are stripped.
+func (l *Lifecycle) MarshalJSON() ([]byte, error) {
+	type alias Lifecycle // use type alias to avoid infinite recursion
+	a := alias(*l)
+	a.IgnoreChanges = nil
+	for _, c := range l.IgnoreChanges {
+		a.IgnoreChanges = append(a.IgnoreChanges, bareAttribute(c))
+	}
+	return json.Marshal(a)
+}
+
+// bareAttribute returns the attribute name without surrounding whitespace, quotes or interpolation.
+func bareAttribute(attr string) string {
+	attr = strings.TrimSpace(attr)
+	if strings.HasPrefix(attr, "${") && strings.HasSuffix(attr, "}") {
+		attr = strings.TrimSpace(attr[2 : len(attr)-1])
+	}
+	return strings.Trim(attr, `"`)
+}
+
+// normalizeLifecycle strips any quotes or interpolation from the ignore_changes of a merged resource's lifecycle block.
+func normalizeLifecycle(merged map[string]interface{}) {
+	l, ok := merged["lifecycle"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	switch ics := l["ignore_changes"].(type) {
+	case string:
+		l["ignore_changes"] = bareAttribute(ics)
+	case []interface{}:
+		for i, c := range ics {
+			if s, ok := c.(string); ok {
+				ics[i] = bareAttribute(s)
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLifecycle(t *testing.T) {
+	cases := []struct {
+		name string
+		l    *Lifecycle
+		want string
+	}{
+		{
+			name: "empty",
+			l:    &Lifecycle{},
+			want: `{}`,
+		},
+		{
+			name: "all_fields",
+			l: &Lifecycle{
+				PreventDestroy:      true,
+				CreateBeforeDestroy: true,
+				IgnoreChanges:       []string{"labels", "rotation_period"},
+			},
+			want: `{
+  "prevent_destroy": true,
+  "create_before_destroy": true,
+  "ignore_changes": ["labels", "rotation_period"]
+}`,
+		},
+		{
+			name: "ignore_changes_formatting",
+			l:    &Lifecycle{IgnoreChanges: []string{`"labels"`, "${rotation_period}", " all "}},
+			want: `{"ignore_changes": ["labels", "rotation_period", "all"]}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			checkJSON(t, tc.l, tc.want)
+		})
+	}
+}
+
+func TestLifecycleRaw(t *testing.T) {
+	data := `{
+  "dataset_id": "foo_dataset",
+  "location": "US",
+  "lifecycle": {
+    "prevent_destroy": true,
+    "ignore_changes": ["\"labels\"", "${access}"]
+  }
+}`
+	d := new(BigqueryDataset)
+	if err := json.Unmarshal([]byte(data), d); err != nil {
+		t.Fatalf("json.Unmarshal = %v", err)
+	}
+	if err := d.Init("my-project"); err != nil {
+		t.Fatalf("d.Init = %v", err)
+	}
+	m, err := interfacePair{d.raw, aliasBigqueryDataset(*d)}.MergedMap()
+	if err != nil {
+		t.Fatalf("MergedMap = %v", err)
+	}
+	checkJSON(t, m["lifecycle"], `{
+  "prevent_destroy": true,
+  "ignore_changes": ["labels", "access"]
+}`)
+}
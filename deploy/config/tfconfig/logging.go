@@ -23,19 +23,38 @@ import (
 
 // LoggingSink represents a logging sink.
 type LoggingSink struct {
-	Name                 string `json:"name"`
-	Project              string `json:"project"`
-	Destination          string `json:"destination"`
-	Filter               string `json:"filter"`
-	UniqueWriterIdentity bool   `json:"unique_writer_identity"`
+	Name        string `json:"name"`
+	Project     string `json:"project"`
+	Destination string `json:"destination"`
+	Filter      string `json:"filter"`
+
+	// UniqueWriterIdentity defaults to true so the sink's writer can be granted access to the destination alone.
+	UniqueWriterIdentity *bool `json:"unique_writer_identity"`
+
+	BigqueryOptions *LoggingSinkBigqueryOptions `json:"bigquery_options,omitempty"`
+}
+
+// LoggingSinkBigqueryOptions are the options for sinks with a bigquery dataset destination.
+type LoggingSinkBigqueryOptions struct {
+	UsePartitionedTables bool `json:"use_partitioned_tables"`
 }
 
 // Init initializes the resource.
 func (s *LoggingSink) Init(projectID string) error {
 	s.Project = projectID
+	if s.UniqueWriterIdentity == nil {
+		t := true
+		s.UniqueWriterIdentity = &t
+	}
 	return nil
 }
 
+// WriterIdentityRef returns the terraform reference to the sink's writer identity.
+// It should be granted write access on the destination.
+func (s *LoggingSink) WriterIdentityRef() string {
+	return Ref(s, "writer_identity")
+}
+
 // ID returns the resource unique identifier.
 func (s *LoggingSink) ID() string {
 	return s.Name
@@ -60,6 +79,34 @@ type LoggingMetric struct {
 	MetricDescriptor *MetricDescriptor `json:"metric_descriptor,omitempty"`
 	ValueExtractor   string            `json:"value_extractor,omitempty"`
 	LabelExtractors  map[string]string `json:"label_extractors,omitempty"`
+	BucketOptions    *BucketOptions    `json:"bucket_options,omitempty"`
+}
+
+// BucketOptions defines the histogram buckets of a distribution metric.
+// Only one of the bucket types should be set.
+type BucketOptions struct {
+	LinearBuckets      *LinearBuckets      `json:"linear_buckets,omitempty"`
+	ExponentialBuckets *ExponentialBuckets `json:"exponential_buckets,omitempty"`
+	ExplicitBuckets    *ExplicitBuckets    `json:"explicit_buckets,omitempty"`
+}
+
+// LinearBuckets defines buckets of equal width.
+type LinearBuckets struct {
+	NumFiniteBuckets int     `json:"num_finite_buckets"`
+	Width            float64 `json:"width"`
+	Offset           float64 `json:"offset"`
+}
+
+// ExponentialBuckets defines buckets whose width grows exponentially.
+type ExponentialBuckets struct {
+	NumFiniteBuckets int     `json:"num_finite_buckets"`
+	GrowthFactor     float64 `json:"growth_factor"`
+	Scale            float64 `json:"scale"`
+}
+
+// ExplicitBuckets defines buckets from an explicit list of bounds.
+type ExplicitBuckets struct {
+	Bounds []float64 `json:"bounds"`
 }
 
 // MetricDescriptor is the metric descriptor associated with the logs-based metric.
@@ -88,6 +135,14 @@ func (m *LoggingMetric) Init(projectID string) error {
 	return nil
 }
 
+// Validate checks that the resource is valid.
+func (m *LoggingMetric) Validate() error {
+	if m.Filter == "" {
+		return fmt.Errorf("filter of logging metric %q must be set", m.Name)
+	}
+	return nil
+}
+
 // ID returns the resource unique identifier.
 func (m *LoggingMetric) ID() string {
 	return m.Name
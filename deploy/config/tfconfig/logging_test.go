@@ -0,0 +1,150 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLoggingMetric(t *testing.T) {
+	cases := []struct {
+		name string
+		m    *LoggingMetric
+		want string
+	}{
+		{
+			name: "counter",
+			m: &LoggingMetric{
+				Name:        "fhir-reads",
+				Description: "Count of FHIR read requests.",
+				Filter:      `protoPayload.methodName="google.cloud.healthcare.v1.fhir.FhirService.ReadResource"`,
+				MetricDescriptor: &MetricDescriptor{
+					MetricKind: "DELTA",
+					ValueType:  "INT64",
+					Labels:     []*Label{{Key: "user", ValueType: "STRING"}, {Key: "store", ValueType: "STRING"}},
+				},
+				LabelExtractors: map[string]string{
+					"user":  "EXTRACT(protoPayload.authenticationInfo.principalEmail)",
+					"store": "EXTRACT(protoPayload.resourceName)",
+				},
+			},
+			want: `{"name":"fhir-reads","project":"my-project","description":"Count of FHIR read requests.",` +
+				`"filter":"protoPayload.methodName=\"google.cloud.healthcare.v1.fhir.FhirService.ReadResource\"",` +
+				`"metric_descriptor":{"metric_kind":"DELTA","value_type":"INT64","labels":[{"key":"user","value_type":"STRING"},{"key":"store","value_type":"STRING"}]},` +
+				`"label_extractors":{"store":"EXTRACT(protoPayload.resourceName)","user":"EXTRACT(protoPayload.authenticationInfo.principalEmail)"}}`,
+		},
+		{
+			name: "distribution",
+			m: &LoggingMetric{
+				Name:             "fhir-latency",
+				Description:      "Latency of FHIR requests.",
+				Filter:           `resource.type="healthcare_fhir_store"`,
+				MetricDescriptor: &MetricDescriptor{MetricKind: "DELTA", ValueType: "DISTRIBUTION"},
+				ValueExtractor:   "EXTRACT(httpRequest.latency)",
+				BucketOptions: &BucketOptions{
+					ExponentialBuckets: &ExponentialBuckets{NumFiniteBuckets: 64, GrowthFactor: 2, Scale: 0.01},
+				},
+			},
+			want: `{"name":"fhir-latency","project":"my-project","description":"Latency of FHIR requests.",` +
+				`"filter":"resource.type=\"healthcare_fhir_store\"",` +
+				`"metric_descriptor":{"metric_kind":"DELTA","value_type":"DISTRIBUTION"},` +
+				`"value_extractor":"EXTRACT(httpRequest.latency)",` +
+				`"bucket_options":{"exponential_buckets":{"num_finite_buckets":64,"growth_factor":2,"scale":0.01}}}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.m.Init("my-project"); err != nil {
+				t.Fatalf("m.Init = %v", err)
+			}
+			if err := tc.m.Validate(); err != nil {
+				t.Fatalf("m.Validate = %v", err)
+			}
+			b, err := json.Marshal(tc.m)
+			if err != nil {
+				t.Fatalf("json.Marshal = %v", err)
+			}
+			if string(b) != tc.want {
+				t.Errorf("json.Marshal = %v, want %v", string(b), tc.want)
+			}
+		})
+	}
+}
+
+func TestLoggingMetricValidateEmptyFilter(t *testing.T) {
+	m := &LoggingMetric{Name: "foo-metric"}
+	if err := m.Validate(); err == nil {
+		t.Error("m.Validate = nil, want error")
+	}
+}
+
+func TestLoggingSink(t *testing.T) {
+	f := false
+	cases := []struct {
+		name string
+		s    *LoggingSink
+		want string
+	}{
+		{
+			name: "default_unique_writer_identity",
+			s: &LoggingSink{
+				Name:            "audit-logs-to-bigquery",
+				Destination:     "bigquery.googleapis.com/projects/my-audit-project/datasets/audit_logs",
+				Filter:          `logName:"logs/cloudaudit.googleapis.com"`,
+				BigqueryOptions: &LoggingSinkBigqueryOptions{UsePartitionedTables: true},
+			},
+			want: `{
+  "name": "audit-logs-to-bigquery",
+  "project": "my-project",
+  "destination": "bigquery.googleapis.com/projects/my-audit-project/datasets/audit_logs",
+  "filter": "logName:\"logs/cloudaudit.googleapis.com\"",
+  "unique_writer_identity": true,
+  "bigquery_options": {"use_partitioned_tables": true}
+}`,
+		},
+		{
+			name: "shared_writer_identity",
+			s: &LoggingSink{
+				Name:                 "audit-logs-to-gcs",
+				Destination:          "storage.googleapis.com/my-audit-bucket",
+				UniqueWriterIdentity: &f,
+			},
+			want: `{
+  "name": "audit-logs-to-gcs",
+  "project": "my-project",
+  "destination": "storage.googleapis.com/my-audit-bucket",
+  "filter": "",
+  "unique_writer_identity": false
+}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.s.Init("my-project"); err != nil {
+				t.Fatalf("s.Init = %v", err)
+			}
+			checkJSON(t, tc.s, tc.want)
+		})
+	}
+}
+
+func TestLoggingSinkWriterIdentityRef(t *testing.T) {
+	s := &LoggingSink{Name: "audit-logs-to-bigquery"}
+	want := "${google_logging_project_sink.audit-logs-to-bigquery.writer_identity}"
+	if got := s.WriterIdentityRef(); got != want {
+		t.Errorf("s.WriterIdentityRef() = %v, want %v", got, want)
+	}
+}
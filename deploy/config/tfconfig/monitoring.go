@@ -30,11 +30,31 @@ type MonitoringNotificationChannel struct {
 	Type        string                 `json:"type,omitempty"`
 	Labels      map[string]interface{} `json:"labels,omitempty"`
 
+	// SensitiveLabels hold the channel's credentials and are kept out of labels so they are not stored in plain text.
+	SensitiveLabels *SensitiveLabels `json:"sensitive_labels,omitempty"`
+
 	Email string `json:"_email"`
 
 	raw json.RawMessage
 }
 
+// SensitiveLabels are the credentials of a notification channel. At most one field should be set.
+type SensitiveLabels struct {
+	AuthToken  string `json:"auth_token,omitempty"`
+	Password   string `json:"password,omitempty"`
+	ServiceKey string `json:"service_key,omitempty"`
+}
+
+// requiredChannelLabels are the labels that must be set for each notification channel type.
+var requiredChannelLabels = map[string][]string{
+	"email":             {"email_address"},
+	"pubsub":            {"topic"},
+	"slack":             {"channel_name"},
+	"sms":               {"number"},
+	"webhook_basicauth": {"url", "username"},
+	"webhook_tokenauth": {"url"},
+}
+
 // Init initializes the resource.
 func (c *MonitoringNotificationChannel) Init(projectID string) error {
 	if c.DisplayName == "" {
@@ -59,6 +79,22 @@ func (c *MonitoringNotificationChannel) Init(projectID string) error {
 	return nil
 }
 
+// Validate checks that the resource is valid.
+func (c *MonitoringNotificationChannel) Validate() error {
+	for _, l := range requiredChannelLabels[c.Type] {
+		if _, ok := c.Labels[l]; !ok {
+			return fmt.Errorf("label %q must be set for notification channel %q of type %q", l, c.DisplayName, c.Type)
+		}
+	}
+	if c.Type == "pagerduty" {
+		_, ok := c.Labels["service_key"]
+		if !ok && (c.SensitiveLabels == nil || c.SensitiveLabels.ServiceKey == "") {
+			return fmt.Errorf("service_key must be set in labels or sensitive labels for notification channel %q of type %q", c.DisplayName, c.Type)
+		}
+	}
+	return nil
+}
+
 // ID returns the resource unique identifier.
 func (c *MonitoringNotificationChannel) ID() string {
 	return standardizeID(c.DisplayName)
@@ -69,6 +105,11 @@ func (c *MonitoringNotificationChannel) ResourceType() string {
 	return "google_monitoring_notification_channel"
 }
 
+// String returns the channel config with its credentials redacted for logging.
+func (c *MonitoringNotificationChannel) String() string {
+	return redactedString(c, sensitivePaths[c.ResourceType()]...)
+}
+
 // ImportID returns the ID to use for terraform imports.
 func (c *MonitoringNotificationChannel) ImportID(rn runner.Runner) (string, error) {
 	// Check channel existence and create if not.
@@ -144,9 +185,17 @@ type Condition struct {
 
 // ConditionThreshold is a condition that compares a time series against a threshold.
 type ConditionThreshold struct {
-	Filter     string `json:"filter,omitempty"`
-	Comparison string `json:"comparison"`
-	Duration   string `json:"duration"`
+	Filter         string  `json:"filter,omitempty"`
+	Comparison     string  `json:"comparison"`
+	ThresholdValue float64 `json:"threshold_value,omitempty"`
+	Duration       string  `json:"duration"`
+}
+
+// alertPolicyCombiners are the valid ways to combine the conditions of an alert policy.
+var alertPolicyCombiners = map[string]bool{
+	"AND":                        true,
+	"OR":                         true,
+	"AND_WITH_MATCHING_RESOURCE": true,
 }
 
 // Documentation is a short name or phrase used to identify the policy in dashboards, notifications, and incidents.
@@ -167,6 +216,14 @@ func (p *MonitoringAlertPolicy) Init(projectID string) error {
 	return nil
 }
 
+// Validate checks that the resource is valid.
+func (p *MonitoringAlertPolicy) Validate() error {
+	if !alertPolicyCombiners[p.Combiner] {
+		return fmt.Errorf("invalid combiner %q for alert policy %q: must be one of AND, OR or AND_WITH_MATCHING_RESOURCE", p.Combiner, p.DisplayName)
+	}
+	return nil
+}
+
 // ID returns the resource unique identifier.
 func (p *MonitoringAlertPolicy) ID() string {
 	return standardizeID(p.DisplayName)
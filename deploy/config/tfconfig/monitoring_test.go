@@ -0,0 +1,224 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMonitoringAlertPolicy(t *testing.T) {
+	p := &MonitoringAlertPolicy{
+		DisplayName: "FHIR Exfiltration Alert",
+		Combiner:    "OR",
+		Conditions: []*Condition{
+			{
+				DisplayName: "High FHIR read rate",
+				ConditionThreshold: &ConditionThreshold{
+					Filter:         `metric.type="logging.googleapis.com/user/fhir-reads"`,
+					Comparison:     "COMPARISON_GT",
+					ThresholdValue: 1000,
+					Duration:       "300s",
+				},
+			},
+			{
+				DisplayName: "Any FHIR export",
+				ConditionThreshold: &ConditionThreshold{
+					Filter:     `metric.type="logging.googleapis.com/user/fhir-exports"`,
+					Comparison: "COMPARISON_GT",
+					Duration:   "0s",
+				},
+			},
+		},
+		NotificationChannels: []string{"${google_monitoring_notification_channel.security.name}"},
+	}
+	if err := p.Init("my-project"); err != nil {
+		t.Fatalf("p.Init = %v", err)
+	}
+	if err := p.Validate(); err != nil {
+		t.Fatalf("p.Validate = %v", err)
+	}
+
+	want := `{
+  "display_name": "FHIR Exfiltration Alert",
+  "project": "my-project",
+  "combiner": "OR",
+  "conditions": [
+    {
+      "display_name": "High FHIR read rate",
+      "condition_threshold": {
+        "filter": "metric.type=\"logging.googleapis.com/user/fhir-reads\"",
+        "comparison": "COMPARISON_GT",
+        "threshold_value": 1000,
+        "duration": "300s"
+      }
+    },
+    {
+      "display_name": "Any FHIR export",
+      "condition_threshold": {
+        "filter": "metric.type=\"logging.googleapis.com/user/fhir-exports\"",
+        "comparison": "COMPARISON_GT",
+        "duration": "0s"
+      }
+    }
+  ],
+  "notification_channels": ["${google_monitoring_notification_channel.security.name}"]
+}`
+	checkJSON(t, p, want)
+
+	if got, want := p.ID(), "fhir_exfiltration_alert"; got != want {
+		t.Errorf("p.ID() = %v, want %v", got, want)
+	}
+}
+
+func TestMonitoringAlertPolicyValidate(t *testing.T) {
+	cases := []struct {
+		combiner string
+		wantErr  bool
+	}{
+		{combiner: "AND"},
+		{combiner: "OR"},
+		{combiner: "AND_WITH_MATCHING_RESOURCE"},
+		{combiner: "", wantErr: true},
+		{combiner: "XOR", wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.combiner, func(t *testing.T) {
+			p := &MonitoringAlertPolicy{DisplayName: "Foo Alert", Combiner: tc.combiner}
+			err := p.Validate()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("p.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestMonitoringNotificationChannel(t *testing.T) {
+	cases := []struct {
+		name string
+		c    *MonitoringNotificationChannel
+		want string
+	}{
+		{
+			name: "email",
+			c:    &MonitoringNotificationChannel{DisplayName: "Security Team", Email: "security@my-domain.com"},
+			want: `{
+  "display_name": "Security Team",
+  "project": "my-project",
+  "type": "email",
+  "labels": {"email_address": "security@my-domain.com"}
+}`,
+		},
+		{
+			name: "pubsub",
+			c: &MonitoringNotificationChannel{
+				DisplayName: "Alerts Topic",
+				Type:        "pubsub",
+				Labels:      map[string]interface{}{"topic": "projects/my-project/topics/alerts"},
+			},
+			want: `{
+  "display_name": "Alerts Topic",
+  "project": "my-project",
+  "type": "pubsub",
+  "labels": {"topic": "projects/my-project/topics/alerts"}
+}`,
+		},
+		{
+			name: "pagerduty_sensitive",
+			c: &MonitoringNotificationChannel{
+				DisplayName:     "On Call",
+				Type:            "pagerduty",
+				SensitiveLabels: &SensitiveLabels{ServiceKey: "abc123"},
+			},
+			want: `{
+  "display_name": "On Call",
+  "project": "my-project",
+  "type": "pagerduty",
+  "sensitive_labels": {"service_key": "abc123"}
+}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.c.Init("my-project"); err != nil {
+				t.Fatalf("c.Init = %v", err)
+			}
+			if err := tc.c.Validate(); err != nil {
+				t.Fatalf("c.Validate = %v", err)
+			}
+			checkJSON(t, tc.c, tc.want)
+		})
+	}
+}
+
+func TestMonitoringNotificationChannelString(t *testing.T) {
+	c := &MonitoringNotificationChannel{
+		DisplayName:     "On Call",
+		Type:            "webhook_basicauth",
+		Labels:          map[string]interface{}{"url": "https://hooks.my-domain.com", "username": "alerts", "password": "in-labels"},
+		SensitiveLabels: &SensitiveLabels{Password: "hunter2"},
+	}
+	if err := c.Init("my-project"); err != nil {
+		t.Fatalf("c.Init = %v", err)
+	}
+
+	got := c.String()
+	for _, secret := range []string{"hunter2", "in-labels"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("c.String() = %v, want %q redacted", got, secret)
+		}
+	}
+	if want := `"sensitive_labels":{"password":"***"}`; !strings.Contains(got, want) {
+		t.Errorf("c.String() = %v, want it to contain %v", got, want)
+	}
+	if want := `"username":"alerts"`; !strings.Contains(got, want) {
+		t.Errorf("c.String() = %v, want it to contain %v", got, want)
+	}
+
+	checkJSON(t, c, `{
+  "display_name": "On Call",
+  "project": "my-project",
+  "type": "webhook_basicauth",
+  "labels": {"url": "https://hooks.my-domain.com", "username": "alerts", "password": "in-labels"},
+  "sensitive_labels": {"password": "hunter2"}
+}`)
+}
+
+func TestMonitoringNotificationChannelValidate(t *testing.T) {
+	cases := []struct {
+		name string
+		c    *MonitoringNotificationChannel
+	}{
+		{
+			name: "email_without_address",
+			c:    &MonitoringNotificationChannel{DisplayName: "Security Team", Type: "email"},
+		},
+		{
+			name: "pubsub_without_topic",
+			c:    &MonitoringNotificationChannel{DisplayName: "Alerts Topic", Type: "pubsub", Labels: map[string]interface{}{"email_address": "foo@my-domain.com"}},
+		},
+		{
+			name: "pagerduty_without_service_key",
+			c:    &MonitoringNotificationChannel{DisplayName: "On Call", Type: "pagerduty"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.c.Validate(); err == nil {
+				t.Error("c.Validate = nil, want error")
+			}
+		})
+	}
+}
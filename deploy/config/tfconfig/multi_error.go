@@ -0,0 +1,104 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ResourceError is an error of a single resource.
+type ResourceError struct {
+	// ResourceType and ID identify the resource. Both are empty for errors that are not specific to a resource,
+	// e.g. duplicate IDs across resources.
+	ResourceType string
+	ID           string
+	Err          error
+}
+
+func (e *ResourceError) Error() string {
+	if e.ResourceType == "" && e.ID == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s.%s: %v", e.ResourceType, e.ID, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ResourceError) Unwrap() error {
+	return e.Err
+}
+
+// MultiError aggregates the errors of multiple resources so they can all be reported at once.
+// It formats as one "- <type>.<id>: <error>" line per error.
+// errors.Is and errors.As match each of the aggregated errors.
+type MultiError struct {
+	Errors []*ResourceError
+}
+
+// Add adds the error of the resource with the given type and ID. Nil errors are ignored.
+func (m *MultiError) Add(resourceType, id string, err error) {
+	if err == nil {
+		return
+	}
+	m.Errors = append(m.Errors, &ResourceError{ResourceType: resourceType, ID: id, Err: err})
+}
+
+// ErrOrNil returns the aggregated error, or nil if no error was added.
+func (m *MultiError) ErrOrNil() error {
+	if len(m.Errors) == 0 {
+		return nil
+	}
+	return m
+}
+
+func (m *MultiError) Error() string {
+	lines := make([]string, 0, len(m.Errors))
+	for _, e := range m.Errors {
+		lines = append(lines, "- "+e.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Unwrap returns the aggregated errors.
+func (m *MultiError) Unwrap() []error {
+	errs := make([]error, 0, len(m.Errors))
+	for _, e := range m.Errors {
+		errs = append(errs, e)
+	}
+	return errs
+}
+
+// Is reports whether any of the aggregated errors matches target.
+// It lets errors.Is match the aggregated errors on Go versions that do not support unwrapping to multiple errors.
+func (m *MultiError) Is(target error) bool {
+	for _, e := range m.Errors {
+		if errors.Is(e, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first aggregated error that matches target, and if so, sets target to that error value and returns true.
+// It lets errors.As match the aggregated errors on Go versions that do not support unwrapping to multiple errors.
+func (m *MultiError) As(target interface{}) bool {
+	for _, e := range m.Errors {
+		if errors.As(e, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestMultiError(t *testing.T) {
+	errNotFound := errors.New("not found")
+	sa := &ServiceAccount{AccountID: "foo-account", DisplayName: strings.Repeat("a", 101)}
+	rs := []Resource{
+		sa,
+		&StorageBucket{Name: "foo-bucket"},
+		&PubsubTopic{Name: "foo-topic"},
+	}
+
+	if err := sa.Init("my-project"); err != nil {
+		t.Fatalf("sa.Init = %v", err)
+	}
+
+	m := new(MultiError)
+	if err := m.ErrOrNil(); err != nil {
+		t.Fatalf("m.ErrOrNil = %v, want nil", err)
+	}
+	m.Add(sa.ResourceType(), sa.ID(), sa.Validate())
+	m.Add(rs[1].ResourceType(), rs[1].ID(), fmt.Errorf("failed to get bucket: %w", errNotFound))
+	m.Add(rs[2].ResourceType(), rs[2].ID(), errors.New("kms_key_name must be set"))
+	m.Add(rs[2].ResourceType(), rs[2].ID(), nil)
+
+	err := m.ErrOrNil()
+	if err == nil {
+		t.Fatal("m.ErrOrNil = nil, want error")
+	}
+	lines := strings.Split(err.Error(), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("m.Error() = %q, want 3 lines", err.Error())
+	}
+	for i, prefix := range []string{
+		"- google_service_account.foo-account: display_name",
+		"- google_storage_bucket.foo-bucket: failed to get bucket: not found",
+		"- google_pubsub_topic.foo-topic: kms_key_name must be set",
+	} {
+		if !strings.HasPrefix(lines[i], prefix) {
+			t.Errorf("line %d = %q, want prefix %q", i, lines[i], prefix)
+		}
+	}
+
+	if !errors.Is(err, errNotFound) {
+		t.Errorf("errors.Is(%v, %v) = false, want true", err, errNotFound)
+	}
+	var re *ResourceError
+	if !errors.As(err, &re) {
+		t.Fatalf("errors.As(%v, %T) = false, want true", err, re)
+	}
+	if re.ResourceType != "google_service_account" || re.ID != "foo-account" {
+		t.Errorf("errors.As matched %v, want the service account error", re)
+	}
+	if wrapped := fmt.Errorf("failed to validate resources:\n%w", err); !errors.Is(wrapped, errNotFound) {
+		t.Errorf("errors.Is(%v, %v) = false, want true through wrapping", wrapped, errNotFound)
+	}
+
+	// Is and As are called directly as errors.Is and errors.As only unwrap multiple errors from Go 1.20.
+	if !m.Is(errNotFound) {
+		t.Errorf("m.Is(%v) = false, want true", errNotFound)
+	}
+	if m.Is(errors.New("other")) {
+		t.Error("m.Is(other) = true, want false")
+	}
+	re = nil
+	if !m.As(&re) || re.ID != "foo-account" {
+		t.Errorf("m.As(%T) = false or matched %v, want the service account error", re, re)
+	}
+}
+
+func TestResourceErrorWithoutResource(t *testing.T) {
+	e := &ResourceError{Err: errors.New("found resources with duplicate IDs: google_storage_bucket.foo")}
+	if got, want := e.Error(), "found resources with duplicate IDs: google_storage_bucket.foo"; got != want {
+		t.Errorf("e.Error() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
+)
+
+// BooleanPolicy enforces or disables a boolean constraint.
+type BooleanPolicy struct {
+	Enforced bool `json:"enforced"`
+}
+
+// ListPolicy allows or denies values of a list constraint. Only one of Allow and Deny should be set.
+type ListPolicy struct {
+	Allow             *ListPolicyValues `json:"allow,omitempty"`
+	Deny              *ListPolicyValues `json:"deny,omitempty"`
+	SuggestedValue    string            `json:"suggested_value,omitempty"`
+	InheritFromParent bool              `json:"inherit_from_parent,omitempty"`
+}
+
+// ListPolicyValues are the values allowed or denied by a list policy. Only one of All and Values should be set.
+type ListPolicyValues struct {
+	All    bool     `json:"all,omitempty"`
+	Values []string `json:"values,omitempty"`
+}
+
+// RestorePolicy restores the default behavior of the constraint.
+type RestorePolicy struct {
+	Default bool `json:"default"`
+}
+
+// validateOrgPolicy checks that exactly one kind of policy is set for the constraint.
+func validateOrgPolicy(constraint string, b *BooleanPolicy, l *ListPolicy, r *RestorePolicy) error {
+	n := 0
+	for _, set := range []bool{b != nil, l != nil, r != nil} {
+		if set {
+			n++
+		}
+	}
+	if n != 1 {
+		return fmt.Errorf("exactly one of boolean_policy, list_policy and restore_policy must be set for constraint %q, got %d", constraint, n)
+	}
+	if l != nil && (l.Allow == nil) == (l.Deny == nil) {
+		return fmt.Errorf("exactly one of allow and deny must be set in the list policy for constraint %q", constraint)
+	}
+	return nil
+}
+
+// orgPolicyID returns the resource ID for the constraint, e.g. "gcp_resourcelocations" for "constraints/gcp.resourceLocations".
+func orgPolicyID(constraint string) string {
+	return standardizeID(strings.TrimPrefix(constraint, "constraints/"))
+}
+
+// ProjectOrganizationPolicy represents a Terraform project organization policy.
+type ProjectOrganizationPolicy struct {
+	Project       string         `json:"project"`
+	Provider      string         `json:"provider,omitempty"`
+	Constraint    string         `json:"constraint"`
+	BooleanPolicy *BooleanPolicy `json:"boolean_policy,omitempty"`
+	ListPolicy    *ListPolicy    `json:"list_policy,omitempty"`
+	RestorePolicy *RestorePolicy `json:"restore_policy,omitempty"`
+}
+
+// Init initializes the resource.
+func (p *ProjectOrganizationPolicy) Init(projectID string) error {
+	if p.Constraint == "" {
+		return errors.New("constraint must be set")
+	}
+	if p.Project != "" {
+		return fmt.Errorf("project must be unset: %v", p.Project)
+	}
+	p.Project = projectID
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (p *ProjectOrganizationPolicy) Validate() error {
+	return validateOrgPolicy(p.Constraint, p.BooleanPolicy, p.ListPolicy, p.RestorePolicy)
+}
+
+// ID returns the resource unique identifier.
+func (p *ProjectOrganizationPolicy) ID() string {
+	return orgPolicyID(p.Constraint)
+}
+
+// ResourceType returns the resource terraform provider type.
+func (p *ProjectOrganizationPolicy) ResourceType() string {
+	return "google_project_organization_policy"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (p *ProjectOrganizationPolicy) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("%s:%s", p.Project, p.Constraint), nil
+}
+
+// FolderOrganizationPolicy represents a Terraform folder organization policy.
+type FolderOrganizationPolicy struct {
+	// Folder is of the form folders/{folder_id}.
+	Folder        string         `json:"folder"`
+	Provider      string         `json:"provider,omitempty"`
+	Constraint    string         `json:"constraint"`
+	BooleanPolicy *BooleanPolicy `json:"boolean_policy,omitempty"`
+	ListPolicy    *ListPolicy    `json:"list_policy,omitempty"`
+	RestorePolicy *RestorePolicy `json:"restore_policy,omitempty"`
+}
+
+// Init initializes the resource.
+// Folder policies are not set on a project so the project ID is unused.
+func (p *FolderOrganizationPolicy) Init(string) error {
+	if p.Folder == "" {
+		return errors.New("folder must be set")
+	}
+	if p.Constraint == "" {
+		return errors.New("constraint must be set")
+	}
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (p *FolderOrganizationPolicy) Validate() error {
+	return validateOrgPolicy(p.Constraint, p.BooleanPolicy, p.ListPolicy, p.RestorePolicy)
+}
+
+// ID returns the resource unique identifier.
+func (p *FolderOrganizationPolicy) ID() string {
+	return orgPolicyID(p.Constraint)
+}
+
+// ResourceType returns the resource terraform provider type.
+func (p *FolderOrganizationPolicy) ResourceType() string {
+	return "google_folder_organization_policy"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (p *FolderOrganizationPolicy) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("%s/%s", p.Folder, p.Constraint), nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"testing"
+)
+
+func TestProjectOrganizationPolicy(t *testing.T) {
+	cases := []struct {
+		name   string
+		p      *ProjectOrganizationPolicy
+		wantID string
+		want   string
+	}{
+		{
+			name: "boolean_enforce",
+			p: &ProjectOrganizationPolicy{
+				Constraint:    "constraints/iam.disableServiceAccountKeyCreation",
+				BooleanPolicy: &BooleanPolicy{Enforced: true},
+			},
+			wantID: "iam_disableserviceaccountkeycreation",
+			want: `{
+  "project": "my-project",
+  "constraint": "constraints/iam.disableServiceAccountKeyCreation",
+  "boolean_policy": {"enforced": true}
+}`,
+		},
+		{
+			name: "list_allow",
+			p: &ProjectOrganizationPolicy{
+				Constraint: "constraints/gcp.resourceLocations",
+				ListPolicy: &ListPolicy{Allow: &ListPolicyValues{Values: []string{"in:us-locations"}}},
+			},
+			wantID: "gcp_resourcelocations",
+			want: `{
+  "project": "my-project",
+  "constraint": "constraints/gcp.resourceLocations",
+  "list_policy": {
+    "allow": {"values": ["in:us-locations"]}
+  }
+}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.p.Init("my-project"); err != nil {
+				t.Fatalf("p.Init = %v", err)
+			}
+			if err := tc.p.Validate(); err != nil {
+				t.Fatalf("p.Validate = %v", err)
+			}
+			if got := tc.p.ID(); got != tc.wantID {
+				t.Errorf("p.ID = %q, want %q", got, tc.wantID)
+			}
+			checkJSON(t, tc.p, tc.want)
+		})
+	}
+}
+
+func TestOrganizationPolicyValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		b       *BooleanPolicy
+		l       *ListPolicy
+		r       *RestorePolicy
+		wantErr bool
+	}{
+		{name: "boolean", b: &BooleanPolicy{Enforced: true}},
+		{name: "restore", r: &RestorePolicy{Default: true}},
+		{name: "list_deny_all", l: &ListPolicy{Deny: &ListPolicyValues{All: true}}},
+		{name: "none", wantErr: true},
+		{name: "boolean_and_list", b: &BooleanPolicy{}, l: &ListPolicy{Allow: &ListPolicyValues{All: true}}, wantErr: true},
+		{name: "all_kinds", b: &BooleanPolicy{}, l: &ListPolicy{Allow: &ListPolicyValues{All: true}}, r: &RestorePolicy{}, wantErr: true},
+		{name: "list_allow_and_deny", l: &ListPolicy{Allow: &ListPolicyValues{All: true}, Deny: &ListPolicyValues{All: true}}, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &ProjectOrganizationPolicy{Constraint: "constraints/foo", BooleanPolicy: tc.b, ListPolicy: tc.l, RestorePolicy: tc.r}
+			err := p.Validate()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("p.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+
+			f := &FolderOrganizationPolicy{Folder: "folders/123", Constraint: "constraints/foo", BooleanPolicy: tc.b, ListPolicy: tc.l, RestorePolicy: tc.r}
+			err = f.Validate()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("f.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -15,6 +15,7 @@
 package tfconfig
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -75,6 +76,7 @@ func (p interfacePair) MergedMap() (map[string]interface{}, error) {
 			delete(merged, k)
 		}
 	}
+	normalizeLifecycle(merged)
 	return merged, nil
 }
 
@@ -101,3 +103,17 @@ func unmarshalJSONMany(data []byte, vs ...interface{}) error {
 	}
 	return nil
 }
+
+// unmarshalStrict unmarshals the data to v, returning an error naming the first field of the data that v does not define.
+// Unlike json.Unmarshal, trailing data after the JSON value is also an error.
+func unmarshalStrict(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("unexpected data after JSON value")
+	}
+	return nil
+}
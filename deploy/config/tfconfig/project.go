@@ -16,6 +16,8 @@ package tfconfig
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 
 	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
 )
@@ -67,12 +69,79 @@ type ProjectServices struct {
 type ProjectService struct {
 	Service string `json:"service"`
 
-	// The following fields should not be set by users.
+	// DisableOnDestroy defaults to false for standalone services as the provider defaults it to true,
+	// which would disable APIs still in use by resources outside the deployment.
+	// Services of ProjectServices keep the provider default unless it is set.
+	DisableOnDestroy         *bool `json:"disable_on_destroy,omitempty"`
+	DisableDependentServices bool  `json:"disable_dependent_services,omitempty"`
 
-	// ForEach is used to let a single iam member expand to reference multiple iam members
-	// through the use of terraform's for_each iterator.
-	ForEach map[string]bool `json:"for_each,omitempty"`
-	Project string          `json:"project,omitempty"`
+	Project string `json:"project,omitempty"`
+}
+
+// mergedProjectService is a project service expanded to multiple services through terraform's for_each iterator.
+// Services are mapped to true, or to their options if any service sets options.
+type mergedProjectService struct {
+	ForEach                  interface{} `json:"for_each"`
+	Project                  string      `json:"project"`
+	Service                  string      `json:"service"`
+	DisableOnDestroy         string      `json:"disable_on_destroy,omitempty"`
+	DisableDependentServices string      `json:"disable_dependent_services,omitempty"`
+}
+
+// Init initializes the resource.
+func (s *ProjectService) Init(projectID string) error {
+	if s.Service == "" {
+		return errors.New("service must be set")
+	}
+	if s.Project != "" {
+		return fmt.Errorf("project must be unset: %v", s.Project)
+	}
+	s.Project = projectID
+	if s.DisableOnDestroy == nil {
+		f := false
+		s.DisableOnDestroy = &f
+	}
+	return nil
+}
+
+// hasOptions returns whether the service sets any option.
+func (s *ProjectService) hasOptions() bool {
+	return s.DisableOnDestroy != nil || s.DisableDependentServices
+}
+
+// sameOptions returns whether both services set the same options.
+func (s *ProjectService) sameOptions(o *ProjectService) bool {
+	if (s.DisableOnDestroy == nil) != (o.DisableOnDestroy == nil) {
+		return false
+	}
+	if s.DisableOnDestroy != nil && *s.DisableOnDestroy != *o.DisableOnDestroy {
+		return false
+	}
+	return s.DisableDependentServices == o.DisableDependentServices
+}
+
+// Validate checks that the resource is valid.
+func (s *ProjectService) Validate() error {
+	if !serviceRE.MatchString(s.Service) {
+		return fmt.Errorf("invalid service %q: must be of the form <name>.googleapis.com", s.Service)
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+// It is the standardized service name, e.g. "healthcare_googleapis_com" for healthcare.googleapis.com.
+func (s *ProjectService) ID() string {
+	return standardizeID(s.Service)
+}
+
+// ResourceType returns the resource terraform provider type.
+func (*ProjectService) ResourceType() string {
+	return "google_project_service"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (s *ProjectService) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("%s/%s", s.Project, s.Service), nil
 }
 
 // Init initializes the resource.
@@ -81,6 +150,21 @@ func (s *ProjectServices) Init(projectID string) error {
 	return nil
 }
 
+// Validate checks that the resource is valid.
+func (s *ProjectServices) Validate() error {
+	seen := make(map[string]*ProjectService)
+	for _, svc := range s.Services {
+		if err := svc.Validate(); err != nil {
+			return err
+		}
+		if prev, ok := seen[svc.Service]; ok && !prev.sameOptions(svc) {
+			return fmt.Errorf("service %q is set multiple times with different options", svc.Service)
+		}
+		seen[svc.Service] = svc
+	}
+	return nil
+}
+
 // ID returns the resource unique identifier.
 // It is hardcoded to return "project" as there is at most one of this resource in a deployment.
 func (*ProjectServices) ID() string {
@@ -92,23 +176,52 @@ func (*ProjectServices) ResourceType() string {
 	return "google_project_service"
 }
 
-// MarshalJSON marshals the list of members into a single member.
-// The single member will set a for_each block to expand to multiple iam members in the terraform call.
+// MarshalJSON marshals the list of services into a single service.
+// The single service will set a for_each block keyed by service name to expand to multiple services in the terraform call.
+// Services set multiple times are only kept once.
+// If no service sets options, each service is mapped to true. Otherwise services are mapped to their options,
+// and services that do not set disable_on_destroy keep the provider default.
 func (s *ProjectServices) MarshalJSON() ([]byte, error) {
-	forEach := make(map[string]bool)
-
+	hasOptions := false
 	for _, svc := range s.Services {
-		forEach[svc.Service] = true
+		if svc.hasOptions() {
+			hasOptions = true
+			break
+		}
+	}
+	if !hasOptions {
+		forEach := make(map[string]bool)
+		for _, svc := range s.Services {
+			forEach[svc.Service] = true
+		}
+		return json.Marshal(&mergedProjectService{
+			ForEach: forEach,
+			Project: s.project,
+			Service: "${each.key}",
+		})
 	}
 
-	return json.Marshal(&ProjectService{
-		ForEach: forEach,
-		Project: s.project,
-		Service: "${each.key}",
+	forEach := make(map[string]*ProjectService)
+	for _, svc := range s.Services {
+		if _, ok := forEach[svc.Service]; ok {
+			continue
+		}
+		forEach[svc.Service] = &ProjectService{
+			Service:                  svc.Service,
+			DisableOnDestroy:         svc.DisableOnDestroy,
+			DisableDependentServices: svc.DisableDependentServices,
+		}
+	}
+	return json.Marshal(&mergedProjectService{
+		ForEach:                  forEach,
+		Project:                  s.project,
+		Service:                  "${each.key}",
+		DisableOnDestroy:         `${lookup(each.value, "disable_on_destroy", true)}`,
+		DisableDependentServices: `${lookup(each.value, "disable_dependent_services", false)}`,
 	})
 }
 
-// UnmarshalJSON unmarshals the bytes to a list of members.
+// UnmarshalJSON unmarshals the bytes to a list of services.
 func (s *ProjectServices) UnmarshalJSON(b []byte) error {
 	return json.Unmarshal(b, &s.Services)
 }
@@ -0,0 +1,140 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"testing"
+)
+
+func TestProjectService(t *testing.T) {
+	s := &ProjectService{Service: "healthcare.googleapis.com"}
+	if err := s.Init("my-project"); err != nil {
+		t.Fatalf("s.Init = %v", err)
+	}
+	if err := s.Validate(); err != nil {
+		t.Fatalf("s.Validate = %v", err)
+	}
+	if got, want := s.ID(), "healthcare_googleapis_com"; got != want {
+		t.Errorf("s.ID() = %q, want %q", got, want)
+	}
+
+	want := `{
+  "service": "healthcare.googleapis.com",
+  "disable_on_destroy": false,
+  "project": "my-project"
+}`
+	checkJSON(t, s, want)
+}
+
+func TestProjectServiceValidate(t *testing.T) {
+	for _, svc := range []string{"healthcare", "healthcare.example.com", "Healthcare.googleapis.com", ".googleapis.com"} {
+		t.Run(svc, func(t *testing.T) {
+			s := &ProjectService{Service: svc}
+			if err := s.Validate(); err == nil {
+				t.Error("s.Validate = nil, want error")
+			}
+		})
+	}
+}
+
+func TestProjectServices(t *testing.T) {
+	tr, f := true, false
+	s := &ProjectServices{
+		Services: []*ProjectService{
+			{Service: "healthcare.googleapis.com"},
+			{Service: "compute.googleapis.com", DisableOnDestroy: &tr, DisableDependentServices: true},
+			{Service: "bigquery.googleapis.com", DisableOnDestroy: &f},
+			{Service: "healthcare.googleapis.com"},
+		},
+	}
+	if err := s.Init("my-project"); err != nil {
+		t.Fatalf("s.Init = %v", err)
+	}
+	if err := s.Validate(); err != nil {
+		t.Fatalf("s.Validate = %v", err)
+	}
+
+	want := `{
+  "for_each": {
+    "compute.googleapis.com": {
+      "service": "compute.googleapis.com",
+      "disable_on_destroy": true,
+      "disable_dependent_services": true
+    },
+    "bigquery.googleapis.com": {
+      "service": "bigquery.googleapis.com",
+      "disable_on_destroy": false
+    },
+    "healthcare.googleapis.com": {
+      "service": "healthcare.googleapis.com"
+    }
+  },
+  "project": "my-project",
+  "service": "${each.key}",
+  "disable_on_destroy": "${lookup(each.value, \"disable_on_destroy\", true)}",
+  "disable_dependent_services": "${lookup(each.value, \"disable_dependent_services\", false)}"
+}`
+	checkJSON(t, s, want)
+}
+
+func TestProjectServicesWithoutOptions(t *testing.T) {
+	s := &ProjectServices{
+		Services: []*ProjectService{
+			{Service: "healthcare.googleapis.com"},
+			{Service: "compute.googleapis.com"},
+			{Service: "healthcare.googleapis.com"},
+		},
+	}
+	if err := s.Init("my-project"); err != nil {
+		t.Fatalf("s.Init = %v", err)
+	}
+
+	want := `{
+  "for_each": {
+    "compute.googleapis.com": true,
+    "healthcare.googleapis.com": true
+  },
+  "project": "my-project",
+  "service": "${each.key}"
+}`
+	checkJSON(t, s, want)
+}
+
+func TestProjectServicesValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		services []*ProjectService
+	}{
+		{
+			name:     "invalid_service",
+			services: []*ProjectService{{Service: "healthcare"}},
+		},
+		{
+			name: "conflicting_duplicate",
+			services: []*ProjectService{
+				{Service: "healthcare.googleapis.com"},
+				{Service: "healthcare.googleapis.com", DisableDependentServices: true},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &ProjectServices{Services: tc.services}
+			if err := s.Validate(); err == nil {
+				t.Error("s.Validate = nil, want error")
+			}
+		})
+	}
+}
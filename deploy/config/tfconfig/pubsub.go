@@ -27,6 +27,10 @@ type PubsubTopic struct {
 	Name    string `json:"name"`
 	Project string `json:"project"`
 
+	Labels                   map[string]string `json:"labels,omitempty"`
+	KMSKeyName               string            `json:"kms_key_name,omitempty"`
+	MessageRetentionDuration string            `json:"message_retention_duration,omitempty"`
+
 	IAMMembers    []*TopicIAMMember     `json:"_iam_members"`
 	Subscriptions []*PubsubSubscription `json:"_subscriptions"`
 
@@ -53,6 +57,14 @@ func (t *PubsubTopic) Init(projectID string) error {
 	return nil
 }
 
+// Validate checks that the resource is valid.
+func (t *PubsubTopic) Validate() error {
+	if t.MessageRetentionDuration != "" && !durationRE.MatchString(t.MessageRetentionDuration) {
+		return fmt.Errorf("message retention duration %q of topic %q must be a duration in seconds, e.g. \"86400s\"", t.MessageRetentionDuration, t.Name)
+	}
+	return nil
+}
+
 // ID returns the resource unique identifier.
 func (t *PubsubTopic) ID() string {
 	return t.Name
@@ -63,6 +75,16 @@ func (t *PubsubTopic) ResourceType() string {
 	return "google_pubsub_topic"
 }
 
+// GetLabels returns the labels of the resource.
+func (t *PubsubTopic) GetLabels() map[string]string {
+	return t.Labels
+}
+
+// SetLabels sets the labels of the resource.
+func (t *PubsubTopic) SetLabels(labels map[string]string) {
+	t.Labels = labels
+}
+
 // ImportID returns the ID to use for terraform imports.
 func (t *PubsubTopic) ImportID(runner.Runner) (string, error) {
 	return fmt.Sprintf("%s/%s", t.Project, t.Name), nil
@@ -156,11 +178,35 @@ type PubsubSubscription struct {
 	Project string `json:"project"`
 	Topic   string `json:"topic"`
 
+	AckDeadlineSeconds       int               `json:"ack_deadline_seconds,omitempty"`
+	MessageRetentionDuration string            `json:"message_retention_duration,omitempty"`
+	PushConfig               *PushConfig       `json:"push_config,omitempty"`
+	DeadLetterPolicy         *DeadLetterPolicy `json:"dead_letter_policy,omitempty"`
+
 	IAMMembers []*SubscriptionIAMMember `json:"_iam_members"`
 
 	raw json.RawMessage
 }
 
+// PushConfig represents a subscription push config.
+// Subscriptions without a push config are pull subscriptions.
+type PushConfig struct {
+	PushEndpoint string     `json:"push_endpoint"`
+	OIDCToken    *OIDCToken `json:"oidc_token,omitempty"`
+}
+
+// OIDCToken represents the OIDC token to set on push requests.
+type OIDCToken struct {
+	ServiceAccountEmail string `json:"service_account_email"`
+	Audience            string `json:"audience,omitempty"`
+}
+
+// DeadLetterPolicy represents a subscription dead letter policy.
+type DeadLetterPolicy struct {
+	DeadLetterTopic     string `json:"dead_letter_topic"`
+	MaxDeliveryAttempts int    `json:"max_delivery_attempts,omitempty"`
+}
+
 // Init initializes the resource.
 func (s *PubsubSubscription) Init(projectID string) error {
 	if s.Name == "" {
@@ -173,6 +219,20 @@ func (s *PubsubSubscription) Init(projectID string) error {
 	return nil
 }
 
+// Validate checks that the resource is valid.
+func (s *PubsubSubscription) Validate() error {
+	if s.AckDeadlineSeconds != 0 && (s.AckDeadlineSeconds < 10 || s.AckDeadlineSeconds > 600) {
+		return fmt.Errorf("ack deadline of subscription %q must be between 10 and 600 seconds, got %d", s.Name, s.AckDeadlineSeconds)
+	}
+	if s.MessageRetentionDuration != "" && !durationRE.MatchString(s.MessageRetentionDuration) {
+		return fmt.Errorf("message retention duration %q of subscription %q must be a duration in seconds, e.g. \"86400s\"", s.MessageRetentionDuration, s.Name)
+	}
+	if p := s.DeadLetterPolicy; p != nil && p.MaxDeliveryAttempts != 0 && (p.MaxDeliveryAttempts < 5 || p.MaxDeliveryAttempts > 100) {
+		return fmt.Errorf("max delivery attempts of subscription %q must be between 5 and 100, got %d", s.Name, p.MaxDeliveryAttempts)
+	}
+	return nil
+}
+
 // ID returns the resource unique identifier.
 func (s *PubsubSubscription) ID() string {
 	return s.Name
@@ -227,7 +287,15 @@ func (s *PubsubSubscription) UnmarshalJSON(data []byte) error {
 // MarshalJSON provides a custom JSON marshaller.
 // It is used to merge the original (raw) user JSON definition with the struct.
 func (s *PubsubSubscription) MarshalJSON() ([]byte, error) {
-	return interfacePair{s.raw, aliasPubsubSubscription(*s)}.MarshalJSON()
+	merged, err := interfacePair{s.raw, aliasPubsubSubscription(*s)}.MergedMap()
+	if err != nil {
+		return nil, err
+	}
+	// Pull subscriptions must not set a push config at all.
+	if s.PushConfig == nil || s.PushConfig.PushEndpoint == "" {
+		delete(merged, "push_config")
+	}
+	return json.Marshal(merged)
 }
 
 // SubscriptionIAMMember represents a Terraform subscription IAM member.
@@ -263,3 +331,90 @@ func (m *SubscriptionIAMMember) ID() string {
 func (m *SubscriptionIAMMember) ResourceType() string {
 	return "google_pubsub_subscription_iam_member"
 }
+
+// PubsubTopicIAMMembers represents multiple Terraform pubsub topic IAM members across one or more topics.
+// It is used to wrap and merge multiple IAM members into a single IAM member when being marshalled to JSON.
+// Unlike the IAM members set on a PubsubTopic, each member sets the topic it should be granted on,
+// which allows granting access on topics not defined in the deployment.
+type PubsubTopicIAMMembers struct {
+	Members   []*PubsubTopicIAMMember
+	DependsOn []string
+	project   string
+}
+
+// PubsubTopicIAMMember represents a Terraform pubsub topic IAM member.
+type PubsubTopicIAMMember struct {
+	Topic     string        `json:"topic"`
+	Role      string        `json:"role"`
+	Member    string        `json:"member"`
+	Condition *IAMCondition `json:"condition,omitempty"`
+
+	// The following fields should not be set by users.
+
+	// ForEach is used to let a single iam member expand to reference multiple iam members
+	// through the use of terraform's for_each iterator.
+	ForEach   map[string]*PubsubTopicIAMMember `json:"for_each,omitempty"`
+	Project   string                           `json:"project,omitempty"`
+	DependsOn []string                         `json:"depends_on,omitempty"`
+
+	// Dynamic holds the condition dynamic block of the member, see memberConditionDynamicBlock.
+	Dynamic map[string]interface{} `json:"dynamic,omitempty"`
+}
+
+// Init initializes the resource.
+func (ms *PubsubTopicIAMMembers) Init(projectID string) error {
+	for _, m := range ms.Members {
+		if m.Project != "" {
+			return fmt.Errorf("project must be unset: %v", m.Project)
+		}
+		if m.Topic == "" {
+			return fmt.Errorf("topic must be set for role %q and member %q", m.Role, m.Member)
+		}
+		if err := validateIAMMember(m.Member); err != nil {
+			return fmt.Errorf("invalid member for role %q on topic %q: %v", m.Role, m.Topic, err)
+		}
+	}
+	ms.project = projectID
+	return nil
+}
+
+func (m *PubsubTopicIAMMember) forEachKey() string {
+	return resourceMemberKey(m.Topic, m.Role, m.Member, m.Condition)
+}
+
+func (m *PubsubTopicIAMMember) iamCondition() *IAMCondition {
+	return m.Condition
+}
+
+// ID returns the resource unique identifier.
+// It is hardcoded to return "topics" as there is at most one of this resource in a deployment.
+func (ms *PubsubTopicIAMMembers) ID() string {
+	return "topics"
+}
+
+// ResourceType returns the resource terraform provider type.
+func (ms *PubsubTopicIAMMembers) ResourceType() string {
+	return "google_pubsub_topic_iam_member"
+}
+
+func (ms *PubsubTopicIAMMembers) dependsOn() []string {
+	return ms.DependsOn
+}
+
+// MarshalJSON marshals the list of members into a single member.
+// The single member will set a for_each block to expand to multiple iam members in the terraform call.
+// See forEachKeys for details on how the for_each keys are built.
+func (ms *PubsubTopicIAMMembers) MarshalJSON() ([]byte, error) {
+	return marshalForEachMembers(&PubsubTopicIAMMember{
+		Project:   ms.project,
+		Topic:     "${each.value.topic}",
+		Role:      "${each.value.role}",
+		Member:    "${each.value.member}",
+		DependsOn: normalizeDependsOn(ms.DependsOn),
+	}, ms.Members)
+}
+
+// UnmarshalJSON unmarshals the bytes to a list of members.
+func (ms *PubsubTopicIAMMembers) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &ms.Members)
+}
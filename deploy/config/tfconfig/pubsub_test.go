@@ -0,0 +1,232 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPubsubTopicIAMMembers(t *testing.T) {
+	ms := new(PubsubTopicIAMMembers)
+	input := `[
+  {"topic": "foo-topic", "role": "roles/pubsub.publisher", "member": "serviceAccount:service-123@gcp-sa-healthcare.iam.gserviceaccount.com"},
+  {"topic": "bar-topic", "role": "roles/pubsub.publisher", "member": "serviceAccount:service-123@gcp-sa-healthcare.iam.gserviceaccount.com"},
+  {"topic": "bar-topic", "role": "roles/pubsub.subscriber", "member": "group:foo@my-domain.com"}
+]`
+	if err := json.Unmarshal([]byte(input), ms); err != nil {
+		t.Fatalf("json.Unmarshal = %v", err)
+	}
+	if err := ms.Init("my-project"); err != nil {
+		t.Fatalf("ms.Init = %v", err)
+	}
+
+	want := `{
+  "for_each": {
+    "foo-topic roles/pubsub.publisher serviceAccount:service-123@gcp-sa-healthcare.iam.gserviceaccount.com": {
+      "topic": "foo-topic",
+      "role": "roles/pubsub.publisher",
+      "member": "serviceAccount:service-123@gcp-sa-healthcare.iam.gserviceaccount.com"
+    },
+    "bar-topic roles/pubsub.publisher serviceAccount:service-123@gcp-sa-healthcare.iam.gserviceaccount.com": {
+      "topic": "bar-topic",
+      "role": "roles/pubsub.publisher",
+      "member": "serviceAccount:service-123@gcp-sa-healthcare.iam.gserviceaccount.com"
+    },
+    "bar-topic roles/pubsub.subscriber group:foo@my-domain.com": {
+      "topic": "bar-topic",
+      "role": "roles/pubsub.subscriber",
+      "member": "group:foo@my-domain.com"
+    }
+  },
+  "project": "my-project",
+  "topic": "${each.value.topic}",
+  "role": "${each.value.role}",
+  "member": "${each.value.member}"
+}`
+	checkJSON(t, ms, want)
+}
+
+func TestPubsubTopicIAMMembersReferenceKey(t *testing.T) {
+	m := &PubsubTopicIAMMember{Topic: "${google_pubsub_topic.foo-topic.id}", Role: "roles/pubsub.publisher", Member: "group:foo@my-domain.com"}
+	if got, want := m.forEachKey(), "foo-topic roles/pubsub.publisher group:foo@my-domain.com"; got != want {
+		t.Errorf("m.forEachKey() = %q, want %q", got, want)
+	}
+}
+
+func TestPubsubTopicIAMMembersInitProjectSet(t *testing.T) {
+	ms := &PubsubTopicIAMMembers{Members: []*PubsubTopicIAMMember{
+		{Topic: "foo-topic", Role: "roles/pubsub.publisher", Member: "group:foo@my-domain.com", Project: "other-project"},
+	}}
+	if err := ms.Init("my-project"); err == nil {
+		t.Error("ms.Init = nil, want error")
+	}
+}
+
+func TestPubsubTopic(t *testing.T) {
+	cases := []struct {
+		name string
+		t    *PubsubTopic
+		want string
+	}{
+		{
+			name: "minimal",
+			t:    &PubsubTopic{Name: "foo-topic"},
+			want: `{"name":"foo-topic","project":"my-project"}`,
+		},
+		{
+			name: "cmek_and_labels",
+			t: &PubsubTopic{
+				Name:                     "foo-topic",
+				Labels:                   map[string]string{"pipeline": "fhir", "env": "prod"},
+				KMSKeyName:               "${google_kms_crypto_key.foo-key.id}",
+				MessageRetentionDuration: "86400s",
+			},
+			want: `{"kms_key_name":"${google_kms_crypto_key.foo-key.id}","labels":{"env":"prod","pipeline":"fhir"},` +
+				`"message_retention_duration":"86400s","name":"foo-topic","project":"my-project"}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.t.Init("my-project"); err != nil {
+				t.Fatalf("t.Init = %v", err)
+			}
+			if err := tc.t.Validate(); err != nil {
+				t.Fatalf("t.Validate = %v", err)
+			}
+			b, err := json.Marshal(tc.t)
+			if err != nil {
+				t.Fatalf("json.Marshal = %v", err)
+			}
+			if got := string(b); got != tc.want {
+				t.Errorf("json.Marshal = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPubsubTopicValidate(t *testing.T) {
+	cases := []struct {
+		duration string
+		wantErr  bool
+	}{
+		{duration: ""},
+		{duration: "600s"},
+		{duration: "600.5s"},
+		{duration: "10m", wantErr: true},
+		{duration: "600", wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.duration, func(t *testing.T) {
+			topic := &PubsubTopic{Name: "foo-topic", MessageRetentionDuration: tc.duration}
+			err := topic.Validate()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("topic.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestPubsubSubscription(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "pull",
+			data: `{
+  "name": "foo-subscription",
+  "topic": "${google_pubsub_topic.foo-topic.name}",
+  "ack_deadline_seconds": 60,
+  "push_config": {"push_endpoint": ""}
+}`,
+			want: `{
+  "name": "foo-subscription",
+  "project": "my-project",
+  "topic": "${google_pubsub_topic.foo-topic.name}",
+  "ack_deadline_seconds": 60
+}`,
+		},
+		{
+			name: "push",
+			data: `{
+  "name": "foo-subscription",
+  "topic": "${google_pubsub_topic.foo-topic.name}",
+  "message_retention_duration": "86400s",
+  "push_config": {
+    "push_endpoint": "https://example.com/push",
+    "oidc_token": {"service_account_email": "pusher@my-project.iam.gserviceaccount.com"}
+  },
+  "dead_letter_policy": {"dead_letter_topic": "${google_pubsub_topic.dead-letter.id}", "max_delivery_attempts": 10}
+}`,
+			want: `{
+  "name": "foo-subscription",
+  "project": "my-project",
+  "topic": "${google_pubsub_topic.foo-topic.name}",
+  "message_retention_duration": "86400s",
+  "push_config": {
+    "push_endpoint": "https://example.com/push",
+    "oidc_token": {"service_account_email": "pusher@my-project.iam.gserviceaccount.com"}
+  },
+  "dead_letter_policy": {"dead_letter_topic": "${google_pubsub_topic.dead-letter.id}", "max_delivery_attempts": 10}
+}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := new(PubsubSubscription)
+			if err := json.Unmarshal([]byte(tc.data), s); err != nil {
+				t.Fatalf("json.Unmarshal = %v", err)
+			}
+			if err := s.Init("my-project"); err != nil {
+				t.Fatalf("s.Init = %v", err)
+			}
+			if err := s.Validate(); err != nil {
+				t.Fatalf("s.Validate = %v", err)
+			}
+			checkJSON(t, s, tc.want)
+		})
+	}
+}
+
+func TestPubsubSubscriptionValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		s       *PubsubSubscription
+		wantErr bool
+	}{
+		{name: "default_ack_deadline", s: &PubsubSubscription{}},
+		{name: "min_ack_deadline", s: &PubsubSubscription{AckDeadlineSeconds: 10}},
+		{name: "max_ack_deadline", s: &PubsubSubscription{AckDeadlineSeconds: 600}},
+		{name: "ack_deadline_too_low", s: &PubsubSubscription{AckDeadlineSeconds: 9}, wantErr: true},
+		{name: "ack_deadline_too_high", s: &PubsubSubscription{AckDeadlineSeconds: 601}, wantErr: true},
+		{name: "invalid_retention", s: &PubsubSubscription{MessageRetentionDuration: "1d"}, wantErr: true},
+		{
+			name:    "max_delivery_attempts_too_low",
+			s:       &PubsubSubscription{DeadLetterPolicy: &DeadLetterPolicy{DeadLetterTopic: "foo", MaxDeliveryAttempts: 4}},
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.s.Name = "foo-subscription"
+			err := tc.s.Validate()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("s.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
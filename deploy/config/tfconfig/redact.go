@@ -0,0 +1,139 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// redacted replaces the values of sensitive fields in the string form of resources.
+const redacted = "***"
+
+// sensitivePaths are the paths of the sensitive fields of each resource type, see redactedString.
+// Notification channel credentials are redacted both in sensitive labels and in labels for channels that set them there.
+var sensitivePaths = map[string][]string{
+	"google_sql_user": {"password"},
+	"google_monitoring_notification_channel": {
+		"sensitive_labels.auth_token", "labels.auth_token",
+		"sensitive_labels.password", "labels.password",
+		"sensitive_labels.service_key", "labels.service_key",
+	},
+}
+
+// RedactConfig returns the given terraform JSON config document indented and with its sensitive values redacted,
+// so it can be logged safely. These are the sensitive fields of resources and the default values and values of
+// variables and outputs marked sensitive. Blocks can be grouped either in a single object or in a list of objects.
+// The config itself is unaffected and still holds the real values.
+func RedactConfig(b []byte) string {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return fmt.Sprintf("(failed to unmarshal config: %v)", err)
+	}
+	redactResources(doc["resource"])
+	redactSensitiveBlocks(doc["variable"], "default")
+	redactSensitiveBlocks(doc["output"], "value")
+	b, err := json.MarshalIndent(doc, "", " ")
+	if err != nil {
+		return fmt.Sprintf("(failed to marshal config: %v)", err)
+	}
+	return string(b)
+}
+
+// redactResources redacts the sensitive fields of the given resources grouped by type then ID.
+func redactResources(v interface{}) {
+	switch v := v.(type) {
+	case []interface{}:
+		for _, e := range v {
+			redactResources(e)
+		}
+	case map[string]interface{}:
+		for typ, byID := range v {
+			paths := sensitivePaths[typ]
+			rs, ok := byID.(map[string]interface{})
+			if len(paths) == 0 || !ok {
+				continue
+			}
+			for _, r := range rs {
+				m, ok := r.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				for _, p := range paths {
+					redactPath(m, strings.Split(p, "."))
+				}
+			}
+		}
+	}
+}
+
+// redactSensitiveBlocks redacts the given field of the blocks grouped by name that are marked sensitive.
+func redactSensitiveBlocks(v interface{}, field string) {
+	switch v := v.(type) {
+	case []interface{}:
+		for _, e := range v {
+			redactSensitiveBlocks(e, field)
+		}
+	case map[string]interface{}:
+		for _, b := range v {
+			m, ok := b.(map[string]interface{})
+			if !ok || m["sensitive"] != true {
+				continue
+			}
+			if _, ok := m[field]; ok {
+				m[field] = redacted
+			}
+		}
+	}
+}
+
+// redactedString returns the JSON form of v with the values at the given paths replaced by redacted.
+// Paths are dot separated keys of nested objects, e.g. "sensitive_labels.password". Fields that are not set are left unset.
+// Resources carrying sensitive fields use it to implement String so they can be logged safely.
+// Their JSON form is unaffected and still holds the real values.
+func redactedString(v interface{}, paths ...string) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%T(failed to marshal: %v)", v, err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return fmt.Sprintf("%T(failed to unmarshal: %v)", v, err)
+	}
+	for _, p := range paths {
+		redactPath(m, strings.Split(p, "."))
+	}
+	b, err = json.Marshal(m)
+	if err != nil {
+		return fmt.Sprintf("%T(failed to marshal: %v)", v, err)
+	}
+	return string(b)
+}
+
+// redactPath replaces the value at the given keys of nested objects of m.
+func redactPath(m map[string]interface{}, keys []string) {
+	v, ok := m[keys[0]]
+	if !ok {
+		return
+	}
+	if len(keys) == 1 {
+		m[keys[0]] = redacted
+		return
+	}
+	if child, ok := v.(map[string]interface{}); ok {
+		redactPath(child, keys[1:])
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactedString(t *testing.T) {
+	v := map[string]interface{}{
+		"name":   "foo",
+		"secret": "hunter2",
+		"nested": map[string]interface{}{"token": "abc123", "keep": "bar"},
+		"list":   []string{"a"},
+	}
+	got := redactedString(v, "secret", "nested.token", "missing", "nested.missing.token", "list.token")
+	want := `{"list":["a"],"name":"foo","nested":{"keep":"bar","token":"***"},"secret":"***"}`
+	if got != want {
+		t.Errorf("redactedString = %v, want %v", got, want)
+	}
+	if v["secret"] != "hunter2" {
+		t.Errorf("redactedString modified the value: secret = %v, want hunter2", v["secret"])
+	}
+}
+
+func TestRedactConfig(t *testing.T) {
+	b := []byte(`{
+  "resource": [
+    {"google_sql_user": {"foo": {"name": "foo", "password": "hunter2"}}},
+    {"google_monitoring_notification_channel": {"bar": {"type": "slack", "sensitive_labels": {"auth_token": "abc123"}, "labels": {"channel_name": "#alerts"}}}},
+    {"google_storage_bucket": {"baz": {"name": "baz", "password": "not-a-secret-field"}}}
+  ],
+  "terraform": {"required_version": ">= 0.12.0"}
+}`)
+	got := RedactConfig(b)
+	for _, secret := range []string{"hunter2", "abc123"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("RedactConfig = %v, want %q redacted", got, secret)
+		}
+	}
+	for _, keep := range []string{`"#alerts"`, `"not-a-secret-field"`, `"required_version"`} {
+		if !strings.Contains(got, keep) {
+			t.Errorf("RedactConfig = %v, want it to contain %v", got, keep)
+		}
+	}
+
+	got = RedactConfig([]byte(`{
+  "variable": [
+    {"db_password": {"type": "string", "default": "hunter2", "sensitive": true}},
+    {"region": {"type": "string", "default": "us-central1"}}
+  ],
+  "output": [
+    {"sa_key": {"value": "abc123", "sensitive": true}},
+    {"bucket": {"value": "foo-bucket"}}
+  ]
+}`))
+	for _, secret := range []string{"hunter2", "abc123"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("RedactConfig = %v, want %q redacted", got, secret)
+		}
+	}
+	for _, keep := range []string{`"us-central1"`, `"foo-bucket"`} {
+		if !strings.Contains(got, keep) {
+			t.Errorf("RedactConfig = %v, want it to contain %v", got, keep)
+		}
+	}
+
+	// Deployments group resources in a single object.
+	got = RedactConfig([]byte(`{"resource": {"google_sql_user": {"foo": {"name": "foo", "password": "hunter2"}}}}`))
+	if strings.Contains(got, "hunter2") {
+		t.Errorf("RedactConfig = %v, want password redacted", got)
+	}
+}
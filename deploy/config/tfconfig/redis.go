@@ -0,0 +1,93 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
+)
+
+// RedisInstance represents a Terraform Memorystore Redis instance.
+type RedisInstance struct {
+	Name         string `json:"name"`
+	Project      string `json:"project"`
+	Provider     string `json:"provider,omitempty"`
+	Tier         string `json:"tier"`
+	MemorySizeGb int    `json:"memory_size_gb"`
+	Region       string `json:"region"`
+
+	// AuthorizedNetwork is the network's terraform reference or the name of a network in the deployment.
+	AuthorizedNetwork string `json:"authorized_network,omitempty"`
+
+	// TransitEncryptionMode defaults to SERVER_AUTHENTICATION.
+	// Caches in a deployment are assumed to hold data derived from PHI so it cannot be disabled.
+	TransitEncryptionMode string `json:"transit_encryption_mode"`
+}
+
+// Init initializes the resource.
+func (i *RedisInstance) Init(projectID string) error {
+	if i.Name == "" {
+		return errors.New("name must be set")
+	}
+	if i.Region == "" {
+		return errors.New("region must be set")
+	}
+	if i.Project != "" {
+		return fmt.Errorf("project must be unset: %v", i.Project)
+	}
+	i.Project = projectID
+	i.AuthorizedNetwork = nameRef("google_compute_network", i.AuthorizedNetwork, "self_link")
+	if i.TransitEncryptionMode == "" {
+		i.TransitEncryptionMode = "SERVER_AUTHENTICATION"
+	}
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (i *RedisInstance) Validate() error {
+	switch i.Tier {
+	case "BASIC":
+		if i.MemorySizeGb < 1 {
+			return fmt.Errorf("memory_size_gb of BASIC instance %q must be at least 1, got %d", i.Name, i.MemorySizeGb)
+		}
+	case "STANDARD_HA":
+		if i.MemorySizeGb < 5 {
+			return fmt.Errorf("memory_size_gb of STANDARD_HA instance %q must be at least 5, got %d", i.Name, i.MemorySizeGb)
+		}
+	default:
+		return fmt.Errorf("invalid tier %q for instance %q: must be BASIC or STANDARD_HA", i.Tier, i.Name)
+	}
+	if i.TransitEncryptionMode != "SERVER_AUTHENTICATION" {
+		return fmt.Errorf("transit_encryption_mode of instance %q must be SERVER_AUTHENTICATION, got %q", i.Name, i.TransitEncryptionMode)
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (i *RedisInstance) ID() string {
+	return i.Name
+}
+
+// ResourceType returns the resource terraform provider type.
+func (*RedisInstance) ResourceType() string {
+	return "google_redis_instance"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (i *RedisInstance) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("projects/%s/locations/%s/instances/%s", i.Project, i.Region, i.Name), nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"testing"
+)
+
+func TestRedisInstance(t *testing.T) {
+	tests := []struct {
+		name     string
+		instance *RedisInstance
+		want     string
+	}{
+		{
+			name: "basic",
+			instance: &RedisInstance{
+				Name:         "sessions-dev",
+				Tier:         "BASIC",
+				MemorySizeGb: 1,
+				Region:       "us-central1",
+			},
+			want: `{
+  "name": "sessions-dev",
+  "project": "my-project",
+  "tier": "BASIC",
+  "memory_size_gb": 1,
+  "region": "us-central1",
+  "transit_encryption_mode": "SERVER_AUTHENTICATION"
+}`,
+		},
+		{
+			name: "standard_ha",
+			instance: &RedisInstance{
+				Name:              "sessions",
+				Tier:              "STANDARD_HA",
+				MemorySizeGb:      5,
+				Region:            "us-central1",
+				AuthorizedNetwork: "private",
+			},
+			want: `{
+  "name": "sessions",
+  "project": "my-project",
+  "tier": "STANDARD_HA",
+  "memory_size_gb": 5,
+  "region": "us-central1",
+  "authorized_network": "${google_compute_network.private.self_link}",
+  "transit_encryption_mode": "SERVER_AUTHENTICATION"
+}`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.instance.Init("my-project"); err != nil {
+				t.Fatalf("i.Init = %v", err)
+			}
+			if err := tc.instance.Validate(); err != nil {
+				t.Fatalf("i.Validate = %v", err)
+			}
+			checkJSON(t, tc.instance, tc.want)
+		})
+	}
+}
+
+func TestRedisInstanceValidate(t *testing.T) {
+	encrypted := "SERVER_AUTHENTICATION"
+	tests := []struct {
+		name     string
+		instance *RedisInstance
+		wantErr  bool
+	}{
+		{
+			name:     "standard_ha_at_floor",
+			instance: &RedisInstance{Tier: "STANDARD_HA", MemorySizeGb: 5, TransitEncryptionMode: encrypted},
+		},
+		{
+			name:     "standard_ha_below_floor",
+			instance: &RedisInstance{Tier: "STANDARD_HA", MemorySizeGb: 4, TransitEncryptionMode: encrypted},
+			wantErr:  true,
+		},
+		{
+			name:     "basic_below_standard_ha_floor",
+			instance: &RedisInstance{Tier: "BASIC", MemorySizeGb: 4, TransitEncryptionMode: encrypted},
+		},
+		{
+			name:     "basic_without_memory",
+			instance: &RedisInstance{Tier: "BASIC", TransitEncryptionMode: encrypted},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid_tier",
+			instance: &RedisInstance{Tier: "STANDARD", MemorySizeGb: 5, TransitEncryptionMode: encrypted},
+			wantErr:  true,
+		},
+		{
+			name:     "transit_encryption_disabled",
+			instance: &RedisInstance{Tier: "BASIC", MemorySizeGb: 1, TransitEncryptionMode: "DISABLED"},
+			wantErr:  true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.instance.Name = "foo-instance"
+			if err := tc.instance.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("i.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
+)
+
+// CloudSchedulerJob represents a Terraform Cloud Scheduler job.
+type CloudSchedulerJob struct {
+	Name     string `json:"name"`
+	Project  string `json:"project"`
+	Provider string `json:"provider,omitempty"`
+	Region   string `json:"region"`
+
+	// Schedule is a 5-field cron expression, e.g. "0 2 * * *".
+	Schedule    string `json:"schedule"`
+	TimeZone    string `json:"time_zone,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	// Exactly one of the targets must be set.
+	PubsubTarget        *SchedulerPubsubTarget        `json:"pubsub_target,omitempty"`
+	HTTPTarget          *SchedulerHTTPTarget          `json:"http_target,omitempty"`
+	AppEngineHTTPTarget *SchedulerAppEngineHTTPTarget `json:"app_engine_http_target,omitempty"`
+}
+
+// SchedulerPubsubTarget publishes a message to a topic when the job runs.
+type SchedulerPubsubTarget struct {
+	// TopicName is the full topic name, e.g. Ref(topic, "id") for a PubsubTopic in the deployment.
+	TopicName  string            `json:"topic_name"`
+	Data       string            `json:"data,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// SchedulerHTTPTarget sends a request to an HTTP endpoint when the job runs.
+type SchedulerHTTPTarget struct {
+	URI        string            `json:"uri"`
+	HTTPMethod string            `json:"http_method,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+
+	// At most one of OAuthToken and OIDCToken can be set.
+	OAuthToken *SchedulerOAuthToken `json:"oauth_token,omitempty"`
+	OIDCToken  *SchedulerOIDCToken  `json:"oidc_token,omitempty"`
+}
+
+// SchedulerOAuthToken authenticates requests with an OAuth token, e.g. for Google APIs.
+type SchedulerOAuthToken struct {
+	// ServiceAccountEmail can be a reference to a service account, e.g. ServiceAccount.Ref("email").
+	ServiceAccountEmail string `json:"service_account_email"`
+	Scope               string `json:"scope,omitempty"`
+}
+
+// SchedulerOIDCToken authenticates requests with an OIDC token, e.g. for Cloud Run services.
+type SchedulerOIDCToken struct {
+	// ServiceAccountEmail can be a reference to a service account, e.g. ServiceAccount.Ref("email").
+	ServiceAccountEmail string `json:"service_account_email"`
+	Audience            string `json:"audience,omitempty"`
+}
+
+// SchedulerAppEngineHTTPTarget sends a request to an App Engine app when the job runs.
+type SchedulerAppEngineHTTPTarget struct {
+	RelativeURI      string                     `json:"relative_uri"`
+	HTTPMethod       string                     `json:"http_method,omitempty"`
+	Body             string                     `json:"body,omitempty"`
+	Headers          map[string]string          `json:"headers,omitempty"`
+	AppEngineRouting *SchedulerAppEngineRouting `json:"app_engine_routing,omitempty"`
+}
+
+// SchedulerAppEngineRouting is the App Engine service, version and instance a request is sent to.
+type SchedulerAppEngineRouting struct {
+	Service  string `json:"service,omitempty"`
+	Version  string `json:"version,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+// Init initializes the resource.
+func (j *CloudSchedulerJob) Init(projectID string) error {
+	if j.Name == "" {
+		return errors.New("name must be set")
+	}
+	if j.Project != "" {
+		return fmt.Errorf("project must be unset: %v", j.Project)
+	}
+	j.Project = projectID
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (j *CloudSchedulerJob) Validate() error {
+	if err := validateCron(j.Schedule); err != nil {
+		return fmt.Errorf("invalid schedule %q for job %q: %v", j.Schedule, j.Name, err)
+	}
+	targets := 0
+	for _, set := range []bool{j.PubsubTarget != nil, j.HTTPTarget != nil, j.AppEngineHTTPTarget != nil} {
+		if set {
+			targets++
+		}
+	}
+	if targets != 1 {
+		return fmt.Errorf("exactly one of pubsub_target, http_target and app_engine_http_target must be set for job %q", j.Name)
+	}
+	switch {
+	case j.PubsubTarget != nil:
+		if j.PubsubTarget.TopicName == "" {
+			return fmt.Errorf("topic_name must be set for job %q", j.Name)
+		}
+	case j.HTTPTarget != nil:
+		t := j.HTTPTarget
+		if t.URI == "" {
+			return fmt.Errorf("uri must be set for job %q", j.Name)
+		}
+		if t.OAuthToken != nil && t.OIDCToken != nil {
+			return fmt.Errorf("at most one of oauth_token and oidc_token can be set for job %q", j.Name)
+		}
+		if (t.OAuthToken != nil && t.OAuthToken.ServiceAccountEmail == "") || (t.OIDCToken != nil && t.OIDCToken.ServiceAccountEmail == "") {
+			return fmt.Errorf("service_account_email must be set for the token of job %q", j.Name)
+		}
+	case j.AppEngineHTTPTarget != nil:
+		if j.AppEngineHTTPTarget.RelativeURI == "" {
+			return fmt.Errorf("relative_uri must be set for job %q", j.Name)
+		}
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (j *CloudSchedulerJob) ID() string {
+	return j.Name
+}
+
+// ResourceType returns the resource terraform provider type.
+func (*CloudSchedulerJob) ResourceType() string {
+	return "google_cloud_scheduler_job"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (j *CloudSchedulerJob) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("projects/%s/locations/%s/jobs/%s", j.Project, j.Region, j.Name), nil
+}
+
+// cronField describes the allowed values of a cron field.
+type cronField struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+// cronFields are the fields of a cron expression, in order.
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+	{name: "day of week", min: 0, max: 7, names: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}},
+}
+
+// cronElemRE matches an element of a cron field list: "*" or a value or range, with an optional step.
+var cronElemRE = regexp.MustCompile(`^(\*|[0-9A-Za-z]+(?:-[0-9A-Za-z]+)?)(?:/([0-9]+))?$`)
+
+// validateCron checks that the schedule is a 5-field cron expression.
+// Each field is a comma separated list of "*", values or ranges, each with an optional step, e.g. "*/15" or "1-5".
+func validateCron(schedule string) error {
+	fields := strings.Fields(schedule)
+	if len(fields) != len(cronFields) {
+		return fmt.Errorf("got %d fields, want %d", len(fields), len(cronFields))
+	}
+	for i, f := range fields {
+		cf := cronFields[i]
+		for _, elem := range strings.Split(f, ",") {
+			m := cronElemRE.FindStringSubmatch(elem)
+			if m == nil {
+				return fmt.Errorf("invalid %s %q", cf.name, elem)
+			}
+			if m[2] != "" {
+				if step, _ := strconv.Atoi(m[2]); step == 0 {
+					return fmt.Errorf("invalid step in %s %q", cf.name, elem)
+				}
+			}
+			if m[1] == "*" {
+				continue
+			}
+			bounds := strings.SplitN(m[1], "-", 2)
+			var vals []int
+			for _, b := range bounds {
+				v, err := cf.value(b)
+				if err != nil {
+					return err
+				}
+				vals = append(vals, v)
+			}
+			if len(vals) == 2 && vals[0] > vals[1] {
+				return fmt.Errorf("invalid range in %s %q", cf.name, elem)
+			}
+		}
+	}
+	return nil
+}
+
+// value returns the numeric value of s, which can be a number or a name, e.g. "JAN" for months.
+func (cf cronField) value(s string) (int, error) {
+	for i, n := range cf.names {
+		if strings.EqualFold(s, n) {
+			return i + cf.min, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < cf.min || v > cf.max {
+		return 0, fmt.Errorf("invalid %s %q: must be between %d and %d", cf.name, s, cf.min, cf.max)
+	}
+	return v, nil
+}
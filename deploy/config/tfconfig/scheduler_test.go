@@ -0,0 +1,163 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"testing"
+)
+
+func TestCloudSchedulerJob(t *testing.T) {
+	topic := &PubsubTopic{Name: "deid-batches"}
+	j := &CloudSchedulerJob{
+		Name:     "nightly-deid",
+		Region:   "us-central1",
+		Schedule: "0 2 * * *",
+		TimeZone: "America/New_York",
+		PubsubTarget: &SchedulerPubsubTarget{
+			TopicName:  Ref(topic, "id"),
+			Data:       "c3RhcnQ=",
+			Attributes: map[string]string{"dataset": "patients"},
+		},
+	}
+	if err := j.Init("my-project"); err != nil {
+		t.Fatalf("j.Init = %v", err)
+	}
+	if err := j.Validate(); err != nil {
+		t.Fatalf("j.Validate = %v", err)
+	}
+
+	want := `{
+  "name": "nightly-deid",
+  "project": "my-project",
+  "region": "us-central1",
+  "schedule": "0 2 * * *",
+  "time_zone": "America/New_York",
+  "pubsub_target": {
+    "topic_name": "${google_pubsub_topic.deid-batches.id}",
+    "data": "c3RhcnQ=",
+    "attributes": {"dataset": "patients"}
+  }
+}`
+	checkJSON(t, j, want)
+}
+
+func TestValidateCron(t *testing.T) {
+	tests := []struct {
+		schedule string
+		wantErr  bool
+	}{
+		{schedule: "* * * * *"},
+		{schedule: "*/15 * * * *"},
+		{schedule: "0 9-17/2 * * MON-FRI"},
+		{schedule: "30 1 1,15 JAN,jul 0"},
+		{schedule: "0 0 * * 7"},
+		{schedule: "", wantErr: true},
+		{schedule: "0 2 * *", wantErr: true},
+		{schedule: "0 2 * * * *", wantErr: true},
+		{schedule: "60 * * * *", wantErr: true},
+		{schedule: "0 24 * * *", wantErr: true},
+		{schedule: "0 0 0 * *", wantErr: true},
+		{schedule: "0 0 * 13 *", wantErr: true},
+		{schedule: "0 0 * * 8", wantErr: true},
+		{schedule: "0 17-9 * * *", wantErr: true},
+		{schedule: "*/0 * * * *", wantErr: true},
+		{schedule: "0 0 * FOO *", wantErr: true},
+		{schedule: "0 0 ? * *", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.schedule, func(t *testing.T) {
+			if err := validateCron(tc.schedule); (err != nil) != tc.wantErr {
+				t.Errorf("validateCron(%q) = %v, want error: %t", tc.schedule, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestCloudSchedulerJobValidate(t *testing.T) {
+	pubsub := &SchedulerPubsubTarget{TopicName: "projects/my-project/topics/foo"}
+	tests := []struct {
+		name    string
+		job     *CloudSchedulerJob
+		wantErr bool
+	}{
+		{
+			name: "http_oidc",
+			job: &CloudSchedulerJob{
+				Schedule: "0 * * * *",
+				HTTPTarget: &SchedulerHTTPTarget{
+					URI:       "https://deid-abc123-uc.a.run.app/run",
+					OIDCToken: &SchedulerOIDCToken{ServiceAccountEmail: "${google_service_account.scheduler.email}"},
+				},
+			},
+		},
+		{
+			name: "app_engine",
+			job: &CloudSchedulerJob{
+				Schedule:            "0 * * * *",
+				AppEngineHTTPTarget: &SchedulerAppEngineHTTPTarget{RelativeURI: "/tasks/deid"},
+			},
+		},
+		{
+			name:    "invalid_schedule",
+			job:     &CloudSchedulerJob{Schedule: "every day", PubsubTarget: pubsub},
+			wantErr: true,
+		},
+		{
+			name:    "no_target",
+			job:     &CloudSchedulerJob{Schedule: "0 * * * *"},
+			wantErr: true,
+		},
+		{
+			name: "multiple_targets",
+			job: &CloudSchedulerJob{
+				Schedule:     "0 * * * *",
+				PubsubTarget: pubsub,
+				HTTPTarget:   &SchedulerHTTPTarget{URI: "https://example.com"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "http_oauth_and_oidc",
+			job: &CloudSchedulerJob{
+				Schedule: "0 * * * *",
+				HTTPTarget: &SchedulerHTTPTarget{
+					URI:        "https://example.com",
+					OAuthToken: &SchedulerOAuthToken{ServiceAccountEmail: "foo@my-project.iam.gserviceaccount.com"},
+					OIDCToken:  &SchedulerOIDCToken{ServiceAccountEmail: "foo@my-project.iam.gserviceaccount.com"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "http_token_without_service_account",
+			job: &CloudSchedulerJob{
+				Schedule: "0 * * * *",
+				HTTPTarget: &SchedulerHTTPTarget{
+					URI:        "https://example.com",
+					OAuthToken: &SchedulerOAuthToken{Scope: "https://www.googleapis.com/auth/cloud-platform"},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.job.Name = "foo-job"
+			if err := tc.job.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("j.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
+)
+
+// SecretManagerSecret represents a Terraform secret manager secret.
+// It only holds the secret's metadata: secret payloads belong to secret versions and are never set here.
+type SecretManagerSecret struct {
+	SecretID    string             `json:"secret_id"`
+	Project     string             `json:"project"`
+	Provider    string             `json:"provider,omitempty"`
+	Labels      map[string]string  `json:"labels,omitempty"`
+	Replication *SecretReplication `json:"replication"`
+}
+
+// SecretReplication is the replication policy of a secret. Only one of the fields should be set.
+type SecretReplication struct {
+	Automatic   bool                    `json:"automatic,omitempty"`
+	UserManaged *SecretUserManagedRepls `json:"user_managed,omitempty"`
+}
+
+// SecretUserManagedRepls is a user managed replication policy.
+type SecretUserManagedRepls struct {
+	Replicas []*SecretReplica `json:"replicas"`
+}
+
+// SecretReplica is a location the secret is replicated to.
+type SecretReplica struct {
+	Location                  string                     `json:"location"`
+	CustomerManagedEncryption *CustomerManagedEncryption `json:"customer_managed_encryption,omitempty"`
+}
+
+// CustomerManagedEncryption is the CMEK configuration of a secret replica.
+type CustomerManagedEncryption struct {
+	KMSKeyName string `json:"kms_key_name"`
+}
+
+// Init initializes the resource.
+func (s *SecretManagerSecret) Init(projectID string) error {
+	if s.SecretID == "" {
+		return errors.New("secret_id must be set")
+	}
+	if s.Project != "" {
+		return fmt.Errorf("project must be unset: %v", s.Project)
+	}
+	s.Project = projectID
+	if s.Replication == nil {
+		s.Replication = &SecretReplication{Automatic: true}
+	}
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (s *SecretManagerSecret) Validate() error {
+	r := s.Replication
+	if r == nil {
+		return fmt.Errorf("replication of secret %q must be set", s.SecretID)
+	}
+	if r.Automatic == (r.UserManaged != nil) {
+		return fmt.Errorf("exactly one of automatic and user_managed replication must be set for secret %q", s.SecretID)
+	}
+	if r.UserManaged == nil {
+		return nil
+	}
+	if len(r.UserManaged.Replicas) == 0 {
+		return fmt.Errorf("user managed replication of secret %q must have at least one replica", s.SecretID)
+	}
+	for _, rep := range r.UserManaged.Replicas {
+		if rep.Location == "" {
+			return fmt.Errorf("replica location of secret %q must be set", s.SecretID)
+		}
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (s *SecretManagerSecret) ID() string {
+	return s.SecretID
+}
+
+// ResourceType returns the resource terraform provider type.
+func (s *SecretManagerSecret) ResourceType() string {
+	return "google_secret_manager_secret"
+}
+
+// GetLabels returns the labels of the resource.
+func (s *SecretManagerSecret) GetLabels() map[string]string {
+	return s.Labels
+}
+
+// SetLabels sets the labels of the resource.
+func (s *SecretManagerSecret) SetLabels(labels map[string]string) {
+	s.Labels = labels
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (s *SecretManagerSecret) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("projects/%s/secrets/%s", s.Project, s.SecretID), nil
+}
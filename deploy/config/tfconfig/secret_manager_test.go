@@ -0,0 +1,111 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"testing"
+)
+
+func TestSecretManagerSecret(t *testing.T) {
+	cases := []struct {
+		name string
+		s    *SecretManagerSecret
+		want string
+	}{
+		{
+			name: "automatic",
+			s:    &SecretManagerSecret{SecretID: "hl7-feed-password", Labels: map[string]string{"system": "hl7"}},
+			want: `{
+  "secret_id": "hl7-feed-password",
+  "project": "my-project",
+  "labels": {"system": "hl7"},
+  "replication": {"automatic": true}
+}`,
+		},
+		{
+			name: "user_managed",
+			s: &SecretManagerSecret{
+				SecretID: "hl7-feed-password",
+				Replication: &SecretReplication{
+					UserManaged: &SecretUserManagedRepls{Replicas: []*SecretReplica{
+						{
+							Location:                  "us-central1",
+							CustomerManagedEncryption: &CustomerManagedEncryption{KMSKeyName: "${google_kms_crypto_key.central-key.id}"},
+						},
+						{Location: "us-east1"},
+					}},
+				},
+			},
+			want: `{
+  "secret_id": "hl7-feed-password",
+  "project": "my-project",
+  "replication": {
+    "user_managed": {
+      "replicas": [
+        {
+          "location": "us-central1",
+          "customer_managed_encryption": {"kms_key_name": "${google_kms_crypto_key.central-key.id}"}
+        },
+        {"location": "us-east1"}
+      ]
+    }
+  }
+}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.s.Init("my-project"); err != nil {
+				t.Fatalf("s.Init = %v", err)
+			}
+			if err := tc.s.Validate(); err != nil {
+				t.Fatalf("s.Validate = %v", err)
+			}
+			checkJSON(t, tc.s, tc.want)
+		})
+	}
+}
+
+func TestSecretManagerSecretValidate(t *testing.T) {
+	cases := []struct {
+		name        string
+		replication *SecretReplication
+	}{
+		{
+			name:        "no_replicas",
+			replication: &SecretReplication{UserManaged: &SecretUserManagedRepls{}},
+		},
+		{
+			name:        "automatic_and_user_managed",
+			replication: &SecretReplication{Automatic: true, UserManaged: &SecretUserManagedRepls{Replicas: []*SecretReplica{{Location: "us-east1"}}}},
+		},
+		{
+			name:        "neither",
+			replication: &SecretReplication{},
+		},
+		{
+			name:        "replica_without_location",
+			replication: &SecretReplication{UserManaged: &SecretUserManagedRepls{Replicas: []*SecretReplica{{}}}},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &SecretManagerSecret{SecretID: "foo-secret", Replication: tc.replication}
+			if err := s.Validate(); err == nil {
+				t.Error("s.Validate = nil, want error")
+			}
+		})
+	}
+}
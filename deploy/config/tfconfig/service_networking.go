@@ -0,0 +1,77 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ServiceNetworkingConnection represents a Terraform service networking connection.
+// It peers a network with a service producer, e.g. for Cloud SQL private IP.
+type ServiceNetworkingConnection struct {
+	// Network is the network's terraform reference or the name of a network in the deployment.
+	Network  string `json:"network"`
+	Provider string `json:"provider,omitempty"`
+
+	// Service defaults to servicenetworking.googleapis.com.
+	Service string `json:"service"`
+
+	// ReservedPeeringRanges are the names of the allocated global addresses,
+	// e.g. Ref(address, "name") for a ComputeGlobalAddress in the deployment.
+	ReservedPeeringRanges []string `json:"reserved_peering_ranges"`
+}
+
+// Init initializes the resource.
+func (c *ServiceNetworkingConnection) Init(projectID string) error {
+	if c.Network == "" {
+		return errors.New("network must be set")
+	}
+	if len(c.ReservedPeeringRanges) == 0 {
+		return errors.New("reserved_peering_ranges must be set")
+	}
+	c.Network = nameRef("google_compute_network", c.Network, "self_link")
+	if c.Service == "" {
+		c.Service = "servicenetworking.googleapis.com"
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+// As the connection has no name of its own, it is derived from the network, e.g. "${google_compute_network.private.self_link}" becomes "private".
+func (c *ServiceNetworkingConnection) ID() string {
+	if name := refName(c.Network); name != c.Network {
+		return name
+	}
+	// Use the last segment of network self links or paths.
+	network := c.Network[strings.LastIndex(c.Network, "/")+1:]
+	return standardizeID(network)
+}
+
+// ResourceType returns the resource terraform provider type.
+func (c *ServiceNetworkingConnection) ResourceType() string {
+	return "google_service_networking_connection"
+}
+
+// Validate checks that the resource is valid.
+func (c *ServiceNetworkingConnection) Validate() error {
+	for _, r := range c.ReservedPeeringRanges {
+		if r == "" {
+			return fmt.Errorf("reserved_peering_ranges of connection %q must not be empty", c.ID())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"testing"
+)
+
+func TestServiceNetworkingConnection(t *testing.T) {
+	address := &ComputeGlobalAddress{
+		Name:         "sql-peering-range",
+		Network:      "private",
+		PrefixLength: 16,
+	}
+	if err := address.Init("my-project"); err != nil {
+		t.Fatalf("address.Init = %v", err)
+	}
+	if err := address.Validate(); err != nil {
+		t.Fatalf("address.Validate = %v", err)
+	}
+	wantAddress := `{
+  "name": "sql-peering-range",
+  "project": "my-project",
+  "network": "${google_compute_network.private.self_link}",
+  "purpose": "VPC_PEERING",
+  "address_type": "INTERNAL",
+  "prefix_length": 16
+}`
+	checkJSON(t, address, wantAddress)
+
+	c := &ServiceNetworkingConnection{
+		Network:               "private",
+		ReservedPeeringRanges: []string{Ref(address, "name")},
+	}
+	if err := c.Init("my-project"); err != nil {
+		t.Fatalf("c.Init = %v", err)
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("c.Validate = %v", err)
+	}
+	if got, want := c.ID(), "private"; got != want {
+		t.Errorf("c.ID() = %q, want %q", got, want)
+	}
+	want := `{
+  "network": "${google_compute_network.private.self_link}",
+  "service": "servicenetworking.googleapis.com",
+  "reserved_peering_ranges": ["${google_compute_global_address.sql-peering-range.name}"]
+}`
+	checkJSON(t, c, want)
+}
+
+func TestServiceNetworkingConnectionID(t *testing.T) {
+	tests := []struct {
+		network string
+		want    string
+	}{
+		{network: "${google_compute_network.private.self_link}", want: "private"},
+		{network: "${data.google_compute_network.shared.id}", want: "shared"},
+		{network: "projects/host-project/global/networks/Shared.VPC", want: "shared_vpc"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.network, func(t *testing.T) {
+			c := &ServiceNetworkingConnection{Network: tc.network}
+			if got := c.ID(); got != tc.want {
+				t.Errorf("c.ID() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -18,14 +18,22 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
 )
 
 // SpannerInstance represents a Terraform spanner instance.
 type SpannerInstance struct {
-	Name    string `json:"name"`
-	Project string `json:"project"`
+	Name        string `json:"name"`
+	Project     string `json:"project"`
+	Config      string `json:"config"`
+	DisplayName string `json:"display_name"`
+
+	// At most one of NumNodes and ProcessingUnits must be set.
+	// NumNodes defaults to 1 if neither is set, which was the implied size of instances before sizing could be configured.
+	NumNodes        int `json:"num_nodes,omitempty"`
+	ProcessingUnits int `json:"processing_units,omitempty"`
 
 	IAMMembers []*SpannerInstanceIAMMember `json:"_iam_members"`
 	Databases  []*SpannerDatabase          `json:"_databases"`
@@ -42,6 +50,12 @@ func (i *SpannerInstance) Init(projectID string) error {
 		return fmt.Errorf("project must be unset: %v", i.Project)
 	}
 	i.Project = projectID
+	if i.DisplayName == "" {
+		i.DisplayName = i.Name
+	}
+	if i.NumNodes == 0 && i.ProcessingUnits == 0 {
+		i.NumNodes = 1
+	}
 
 	for _, d := range i.Databases {
 		d.Instance = fmt.Sprintf("${google_spanner_instance.%s.name}", i.ID())
@@ -53,6 +67,20 @@ func (i *SpannerInstance) Init(projectID string) error {
 	return nil
 }
 
+// Validate checks that the resource is valid.
+func (i *SpannerInstance) Validate() error {
+	if i.Config == "" {
+		return fmt.Errorf("config must be set for instance %q", i.Name)
+	}
+	if i.NumNodes > 0 && i.ProcessingUnits > 0 {
+		return fmt.Errorf("only one of num_nodes and processing_units must be set for instance %q", i.Name)
+	}
+	if i.NumNodes < 0 || i.ProcessingUnits < 0 {
+		return fmt.Errorf("num_nodes and processing_units must not be negative for instance %q", i.Name)
+	}
+	return nil
+}
+
 // ID returns the resource unique identifier.
 func (i *SpannerInstance) ID() string {
 	return i.Name
@@ -150,12 +178,18 @@ func (m *SpannerInstanceIAMMember) ResourceType() string {
 	return "google_spanner_instance_iam_member"
 }
 
-// SpannerDatabase represents a Terraform GCS bucket.
+// SpannerDatabase represents a Terraform spanner database.
 type SpannerDatabase struct {
 	Name    string `json:"name"`
 	Project string `json:"project"`
 
-	Instance string `json:"instance"`
+	// Instance is the instance's terraform reference or the name of an instance in the deployment.
+	// It is set automatically for databases defined under an instance.
+	Instance string   `json:"instance"`
+	DDL      []string `json:"ddl,omitempty"`
+
+	// DeletionProtection defaults to true.
+	DeletionProtection *bool `json:"deletion_protection"`
 
 	IAMMembers []*SpannerDatabaseIAMMember `json:"_iam_members"`
 
@@ -175,6 +209,14 @@ func (d *SpannerDatabase) Init(projectID string) error {
 		return fmt.Errorf("project must be unset: %v", d.Project)
 	}
 	d.Project = projectID
+	if d.instanceLiteral == "" && !strings.HasPrefix(d.Instance, "${") {
+		d.instanceLiteral = d.Instance
+	}
+	d.Instance = nameRef("google_spanner_instance", d.Instance, "name")
+	if d.DeletionProtection == nil {
+		t := true
+		d.DeletionProtection = &t
+	}
 	return nil
 }
 
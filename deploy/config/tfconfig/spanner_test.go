@@ -0,0 +1,153 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSpannerInstance(t *testing.T) {
+	data := `{
+  "name": "metadata",
+  "config": "regional-us-central1",
+  "processing_units": 300,
+  "_databases": [{
+    "name": "patients",
+    "ddl": [
+      "CREATE TABLE Patients (PatientId STRING(36) NOT NULL, Name STRING(MAX)) PRIMARY KEY (PatientId)",
+      "CREATE INDEX PatientsByName ON Patients(Name)"
+    ]
+  }]
+}`
+	i := new(SpannerInstance)
+	if err := json.Unmarshal([]byte(data), i); err != nil {
+		t.Fatalf("json.Unmarshal = %v", err)
+	}
+	if err := i.Init("my-project"); err != nil {
+		t.Fatalf("i.Init = %v", err)
+	}
+	if err := i.Validate(); err != nil {
+		t.Fatalf("i.Validate = %v", err)
+	}
+
+	wantInstance := `{
+  "name": "metadata",
+  "project": "my-project",
+  "config": "regional-us-central1",
+  "display_name": "metadata",
+  "processing_units": 300
+}`
+	checkJSON(t, i, wantInstance)
+
+	wantDatabase := `{
+  "name": "patients",
+  "project": "my-project",
+  "instance": "${google_spanner_instance.metadata.name}",
+  "ddl": [
+    "CREATE TABLE Patients (PatientId STRING(36) NOT NULL, Name STRING(MAX)) PRIMARY KEY (PatientId)",
+    "CREATE INDEX PatientsByName ON Patients(Name)"
+  ],
+  "deletion_protection": true
+}`
+	checkJSON(t, i.Databases[0], wantDatabase)
+}
+
+func TestSpannerDatabase(t *testing.T) {
+	f := false
+	d := &SpannerDatabase{
+		Name:               "scratch",
+		Instance:           "metadata",
+		DeletionProtection: &f,
+	}
+	if err := d.Init("my-project"); err != nil {
+		t.Fatalf("d.Init = %v", err)
+	}
+	want := `{
+  "name": "scratch",
+  "project": "my-project",
+  "instance": "${google_spanner_instance.metadata.name}",
+  "deletion_protection": false
+}`
+	checkJSON(t, d, want)
+
+	got, err := d.ImportID(nil)
+	if err != nil {
+		t.Fatalf("d.ImportID = %v", err)
+	}
+	if want := "projects/my-project/instances/metadata/databases/scratch"; got != want {
+		t.Errorf("d.ImportID = %q, want %q", got, want)
+	}
+}
+
+func TestSpannerInstanceValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		instance *SpannerInstance
+		wantErr  bool
+	}{
+		{
+			name:     "num_nodes",
+			instance: &SpannerInstance{Config: "regional-us-central1", NumNodes: 1},
+		},
+		{
+			name:     "processing_units",
+			instance: &SpannerInstance{Config: "regional-us-central1", ProcessingUnits: 100},
+		},
+		{
+			name:     "neither",
+			instance: &SpannerInstance{Config: "regional-us-central1"},
+		},
+		{
+			name:     "both",
+			instance: &SpannerInstance{Config: "regional-us-central1", NumNodes: 1, ProcessingUnits: 1000},
+			wantErr:  true,
+		},
+		{
+			name:     "no_config",
+			instance: &SpannerInstance{NumNodes: 1},
+			wantErr:  true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.instance.Name = "foo-instance"
+			if err := tc.instance.Init("my-project"); err != nil {
+				t.Fatalf("i.Init = %v", err)
+			}
+			if err := tc.instance.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("i.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestSpannerInstanceDefaultNumNodes(t *testing.T) {
+	i := &SpannerInstance{Name: "foo-instance", Config: "regional-us-central1"}
+	if err := i.Init("my-project"); err != nil {
+		t.Fatalf("i.Init = %v", err)
+	}
+	if i.NumNodes != 1 {
+		t.Errorf("i.NumNodes = %d, want 1", i.NumNodes)
+	}
+
+	i = &SpannerInstance{Name: "bar-instance", Config: "regional-us-central1", ProcessingUnits: 100}
+	if err := i.Init("my-project"); err != nil {
+		t.Fatalf("i.Init = %v", err)
+	}
+	if i.NumNodes != 0 {
+		t.Errorf("i.NumNodes = %d, want 0 when processing_units is set", i.NumNodes)
+	}
+}
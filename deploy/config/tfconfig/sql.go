@@ -0,0 +1,237 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
+)
+
+// SQLDatabaseInstance represents a Terraform Cloud SQL instance.
+type SQLDatabaseInstance struct {
+	Name              string       `json:"name"`
+	Project           string       `json:"project"`
+	Provider          string       `json:"provider,omitempty"`
+	DatabaseVersion   string       `json:"database_version"`
+	Region            string       `json:"region"`
+	Settings          *SQLSettings `json:"settings"`
+	EncryptionKeyName string       `json:"encryption_key_name,omitempty"`
+
+	Timeouts *Timeouts `json:"timeouts,omitempty"`
+
+	// DependsOn is commonly set to the service networking connection of the private network.
+	DependsOn []string `json:"depends_on,omitempty"`
+}
+
+// SQLSettings are the settings of a Cloud SQL instance.
+type SQLSettings struct {
+	Tier                string                  `json:"tier"`
+	BackupConfiguration *SQLBackupConfiguration `json:"backup_configuration,omitempty"`
+	IPConfiguration     *SQLIPConfiguration     `json:"ip_configuration"`
+}
+
+// SQLBackupConfiguration is the backup configuration of a Cloud SQL instance.
+type SQLBackupConfiguration struct {
+	Enabled                    bool   `json:"enabled"`
+	StartTime                  string `json:"start_time,omitempty"`
+	BinaryLogEnabled           bool   `json:"binary_log_enabled,omitempty"`
+	PointInTimeRecoveryEnabled bool   `json:"point_in_time_recovery_enabled,omitempty"`
+}
+
+// SQLIPConfiguration is the network configuration of a Cloud SQL instance.
+type SQLIPConfiguration struct {
+	// IPv4Enabled defaults to false so instances are only reachable by private IP.
+	IPv4Enabled *bool `json:"ipv4_enabled"`
+
+	// PrivateNetwork is the self link of the VPC network, e.g. "${google_compute_network.private.self_link}".
+	PrivateNetwork string `json:"private_network,omitempty"`
+	RequireSSL     bool   `json:"require_ssl,omitempty"`
+}
+
+// Init initializes the resource.
+func (i *SQLDatabaseInstance) Init(projectID string) error {
+	if i.Name == "" {
+		return errors.New("name must be set")
+	}
+	if i.DatabaseVersion == "" {
+		return errors.New("database_version must be set")
+	}
+	if i.Settings == nil || i.Settings.Tier == "" {
+		return errors.New("settings.tier must be set")
+	}
+	if i.Project != "" {
+		return fmt.Errorf("project must be unset: %v", i.Project)
+	}
+	i.Project = projectID
+	i.DependsOn = normalizeDependsOn(i.DependsOn)
+
+	if i.Settings.IPConfiguration == nil {
+		i.Settings.IPConfiguration = new(SQLIPConfiguration)
+	}
+	if i.Settings.IPConfiguration.IPv4Enabled == nil {
+		f := false
+		i.Settings.IPConfiguration.IPv4Enabled = &f
+	}
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (i *SQLDatabaseInstance) Validate() error {
+	if i.Timeouts != nil {
+		if err := i.Timeouts.Validate(); err != nil {
+			return fmt.Errorf("invalid timeouts for instance %q: %v", i.Name, err)
+		}
+	}
+	ipc := i.Settings.IPConfiguration
+	if !*ipc.IPv4Enabled && ipc.PrivateNetwork == "" {
+		return fmt.Errorf("private_network must be set for instance %q when public IP is disabled", i.Name)
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (i *SQLDatabaseInstance) ID() string {
+	return i.Name
+}
+
+// ResourceType returns the resource terraform provider type.
+func (*SQLDatabaseInstance) ResourceType() string {
+	return "google_sql_database_instance"
+}
+
+func (i *SQLDatabaseInstance) dependsOn() []string {
+	return i.DependsOn
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (i *SQLDatabaseInstance) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("projects/%s/instances/%s", i.Project, i.Name), nil
+}
+
+// SQLDatabase represents a Terraform Cloud SQL database.
+type SQLDatabase struct {
+	Name     string `json:"name"`
+	Project  string `json:"project"`
+	Provider string `json:"provider,omitempty"`
+
+	// Instance is the instance's terraform reference or the name of an instance in the deployment.
+	Instance  string `json:"instance"`
+	Charset   string `json:"charset,omitempty"`
+	Collation string `json:"collation,omitempty"`
+}
+
+// Init initializes the resource.
+func (d *SQLDatabase) Init(projectID string) error {
+	if d.Name == "" {
+		return errors.New("name must be set")
+	}
+	if d.Instance == "" {
+		return errors.New("instance must be set")
+	}
+	if d.Project != "" {
+		return fmt.Errorf("project must be unset: %v", d.Project)
+	}
+	d.Project = projectID
+	d.Instance = nameRef("google_sql_database_instance", d.Instance, "name")
+	return nil
+}
+
+// ID returns the resource unique identifier.
+// Database names are only unique within an instance, so the ID is the standardized instance and database name.
+func (d *SQLDatabase) ID() string {
+	return standardizeID(refName(d.Instance) + "_" + d.Name)
+}
+
+// ResourceType returns the resource terraform provider type.
+func (*SQLDatabase) ResourceType() string {
+	return "google_sql_database"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (d *SQLDatabase) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("projects/%s/instances/%s/databases/%s", d.Project, refName(d.Instance), d.Name), nil
+}
+
+// SQLUser represents a Terraform Cloud SQL user.
+type SQLUser struct {
+	// Name is the service account email for CLOUD_IAM_SERVICE_ACCOUNT users.
+	// For PostgreSQL instances it is truncated before ".gserviceaccount.com".
+	Name     string `json:"name"`
+	Project  string `json:"project"`
+	Provider string `json:"provider,omitempty"`
+
+	// Instance is the instance's terraform reference or the name of an instance in the deployment.
+	Instance string `json:"instance"`
+	Type     string `json:"type,omitempty"`
+
+	// Password should be a reference rather than a literal, e.g. "${random_password.db-user.result}".
+	// It must be unset for IAM users.
+	Password string `json:"password,omitempty"`
+}
+
+// sqlServiceAccountUserRE matches a service account email, optionally truncated before ".gserviceaccount.com".
+var sqlServiceAccountUserRE = regexp.MustCompile(`^[^@\s]+@[a-z][a-z0-9-]*\.iam(\.gserviceaccount\.com)?$`)
+
+// Init initializes the resource.
+func (u *SQLUser) Init(projectID string) error {
+	if u.Name == "" {
+		return errors.New("name must be set")
+	}
+	if u.Instance == "" {
+		return errors.New("instance must be set")
+	}
+	if u.Project != "" {
+		return fmt.Errorf("project must be unset: %v", u.Project)
+	}
+	u.Project = projectID
+	u.Instance = nameRef("google_sql_database_instance", u.Instance, "name")
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (u *SQLUser) Validate() error {
+	switch u.Type {
+	case "", "BUILT_IN":
+	case "CLOUD_IAM_USER", "CLOUD_IAM_SERVICE_ACCOUNT":
+		if u.Password != "" {
+			return fmt.Errorf("password must be unset for %s user %q", u.Type, u.Name)
+		}
+		if u.Type == "CLOUD_IAM_SERVICE_ACCOUNT" && !sqlServiceAccountUserRE.MatchString(u.Name) {
+			return fmt.Errorf("name %q of CLOUD_IAM_SERVICE_ACCOUNT user must be a service account email", u.Name)
+		}
+	default:
+		return fmt.Errorf("invalid type %q for user %q: must be one of BUILT_IN, CLOUD_IAM_USER or CLOUD_IAM_SERVICE_ACCOUNT", u.Type, u.Name)
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+// User names are only unique within an instance and are commonly emails, so the ID is the standardized instance and user name.
+func (u *SQLUser) ID() string {
+	return standardizeID(refName(u.Instance) + "_" + u.Name)
+}
+
+// ResourceType returns the resource terraform provider type.
+func (*SQLUser) ResourceType() string {
+	return "google_sql_user"
+}
+
+// String returns the user config with the password redacted for logging.
+func (u *SQLUser) String() string {
+	return redactedString(u, sensitivePaths[u.ResourceType()]...)
+}
@@ -0,0 +1,256 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSQLDatabaseInstancePrivateIP(t *testing.T) {
+	i := &SQLDatabaseInstance{
+		Name:            "metadata",
+		DatabaseVersion: "POSTGRES_11",
+		Region:          "us-central1",
+		Settings: &SQLSettings{
+			Tier: "db-custom-1-3840",
+			BackupConfiguration: &SQLBackupConfiguration{
+				Enabled:   true,
+				StartTime: "03:00",
+			},
+			IPConfiguration: &SQLIPConfiguration{
+				PrivateNetwork: "${google_compute_network.private.self_link}",
+				RequireSSL:     true,
+			},
+		},
+		EncryptionKeyName: "${google_kms_crypto_key.sql-key.id}",
+		Timeouts:          &Timeouts{Create: "1h"},
+		DependsOn:         []string{"google_service_networking_connection.private"},
+	}
+	if err := i.Init("my-project"); err != nil {
+		t.Fatalf("i.Init = %v", err)
+	}
+	if err := i.Validate(); err != nil {
+		t.Fatalf("i.Validate = %v", err)
+	}
+
+	want := `{
+  "name": "metadata",
+  "project": "my-project",
+  "database_version": "POSTGRES_11",
+  "region": "us-central1",
+  "settings": {
+    "tier": "db-custom-1-3840",
+    "backup_configuration": {
+      "enabled": true,
+      "start_time": "03:00"
+    },
+    "ip_configuration": {
+      "ipv4_enabled": false,
+      "private_network": "${google_compute_network.private.self_link}",
+      "require_ssl": true
+    }
+  },
+  "encryption_key_name": "${google_kms_crypto_key.sql-key.id}",
+  "timeouts": {
+    "create": "1h"
+  },
+  "depends_on": ["google_service_networking_connection.private"]
+}`
+	checkJSON(t, i, want)
+}
+
+func TestSQLDatabaseInstancePublicIPDisabledByDefault(t *testing.T) {
+	i := &SQLDatabaseInstance{
+		Name:            "metadata",
+		DatabaseVersion: "POSTGRES_11",
+		Settings:        &SQLSettings{Tier: "db-f1-micro"},
+	}
+	if err := i.Init("my-project"); err != nil {
+		t.Fatalf("i.Init = %v", err)
+	}
+	ipc := i.Settings.IPConfiguration
+	if ipc == nil || ipc.IPv4Enabled == nil || *ipc.IPv4Enabled {
+		t.Fatalf("ip_configuration = %+v, want ipv4_enabled false", ipc)
+	}
+	// Without public IP the instance is unreachable unless a private network is set.
+	if err := i.Validate(); err == nil {
+		t.Error("i.Validate = nil, want error")
+	}
+
+	enabled := true
+	i = &SQLDatabaseInstance{
+		Name:            "metadata",
+		DatabaseVersion: "POSTGRES_11",
+		Settings: &SQLSettings{
+			Tier:            "db-f1-micro",
+			IPConfiguration: &SQLIPConfiguration{IPv4Enabled: &enabled},
+		},
+	}
+	if err := i.Init("my-project"); err != nil {
+		t.Fatalf("i.Init = %v", err)
+	}
+	if !*i.Settings.IPConfiguration.IPv4Enabled {
+		t.Error("ipv4_enabled = false, want explicitly set value true to be kept")
+	}
+	if err := i.Validate(); err != nil {
+		t.Errorf("i.Validate = %v", err)
+	}
+
+	i.Timeouts = &Timeouts{Update: "30"}
+	if err := i.Validate(); err == nil {
+		t.Error("i.Validate with invalid timeout = nil, want error")
+	}
+}
+
+func TestSQLDatabase(t *testing.T) {
+	d := &SQLDatabase{
+		Name:      "app-metadata",
+		Instance:  "metadata",
+		Charset:   "UTF8",
+		Collation: "en_US.UTF8",
+	}
+	if err := d.Init("my-project"); err != nil {
+		t.Fatalf("d.Init = %v", err)
+	}
+	if got, want := d.ID(), "metadata_app-metadata"; got != want {
+		t.Errorf("d.ID() = %q, want %q", got, want)
+	}
+	want := `{
+  "name": "app-metadata",
+  "project": "my-project",
+  "instance": "${google_sql_database_instance.metadata.name}",
+  "charset": "UTF8",
+  "collation": "en_US.UTF8"
+}`
+	checkJSON(t, d, want)
+}
+
+func TestSQLUser(t *testing.T) {
+	tests := []struct {
+		name   string
+		user   *SQLUser
+		wantID string
+		want   string
+	}{
+		{
+			name: "built_in",
+			user: &SQLUser{
+				Name:     "app",
+				Instance: "metadata",
+				Password: "${random_password.app.result}",
+			},
+			wantID: "metadata_app",
+			want: `{
+  "name": "app",
+  "project": "my-project",
+  "instance": "${google_sql_database_instance.metadata.name}",
+  "password": "${random_password.app.result}"
+}`,
+		},
+		{
+			name: "iam_service_account",
+			user: &SQLUser{
+				Name:     "api-runner@my-project.iam",
+				Instance: "${google_sql_database_instance.metadata.name}",
+				Type:     "CLOUD_IAM_SERVICE_ACCOUNT",
+			},
+			wantID: "metadata_api-runner_my-project_iam",
+			want: `{
+  "name": "api-runner@my-project.iam",
+  "project": "my-project",
+  "instance": "${google_sql_database_instance.metadata.name}",
+  "type": "CLOUD_IAM_SERVICE_ACCOUNT"
+}`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.user.Init("my-project"); err != nil {
+				t.Fatalf("u.Init = %v", err)
+			}
+			if err := tc.user.Validate(); err != nil {
+				t.Fatalf("u.Validate = %v", err)
+			}
+			if got := tc.user.ID(); got != tc.wantID {
+				t.Errorf("u.ID() = %q, want %q", got, tc.wantID)
+			}
+			checkJSON(t, tc.user, tc.want)
+		})
+	}
+}
+
+func TestSQLUserString(t *testing.T) {
+	u := &SQLUser{Name: "app", Instance: "metadata", Password: "hunter2"}
+	if err := u.Init("my-project"); err != nil {
+		t.Fatalf("u.Init = %v", err)
+	}
+
+	want := `{"instance":"${google_sql_database_instance.metadata.name}","name":"app","password":"***","project":"my-project"}`
+	if got := fmt.Sprint(u); got != want {
+		t.Errorf("fmt.Sprint(u) = %v, want %v", got, want)
+	}
+	checkJSON(t, u, `{
+  "name": "app",
+  "project": "my-project",
+  "instance": "${google_sql_database_instance.metadata.name}",
+  "password": "hunter2"
+}`)
+}
+
+func TestSQLUserValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    *SQLUser
+		wantErr bool
+	}{
+		{
+			name: "full_service_account_email",
+			user: &SQLUser{Name: "api-runner@my-project.iam.gserviceaccount.com", Type: "CLOUD_IAM_SERVICE_ACCOUNT"},
+		},
+		{
+			name:    "iam_service_account_with_password",
+			user:    &SQLUser{Name: "api-runner@my-project.iam", Type: "CLOUD_IAM_SERVICE_ACCOUNT", Password: "${random_password.app.result}"},
+			wantErr: true,
+		},
+		{
+			name:    "iam_user_with_password",
+			user:    &SQLUser{Name: "alice@example.com", Type: "CLOUD_IAM_USER", Password: "hunter2"},
+			wantErr: true,
+		},
+		{
+			name:    "iam_service_account_not_email",
+			user:    &SQLUser{Name: "api-runner", Type: "CLOUD_IAM_SERVICE_ACCOUNT"},
+			wantErr: true,
+		},
+		{
+			name:    "iam_service_account_user_email",
+			user:    &SQLUser{Name: "alice@example.com", Type: "CLOUD_IAM_SERVICE_ACCOUNT"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid_type",
+			user:    &SQLUser{Name: "app", Type: "IAM"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.user.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("u.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -18,19 +18,28 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"path/filepath"
 
 	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
 )
 
 // StorageBucket represents a Terraform GCS bucket.
 type StorageBucket struct {
-	Name             string           `json:"name"`
-	Project          string           `json:"project"`
-	Location         string           `json:"location"`
-	LifecycleRules   []*LifecycleRule `json:"lifecycle_rule,omitempty"`
-	Logging          *Logging         `json:"logging,omitempty"`
-	Versioning       versioning       `json:"versioning,omitempty"`
-	BucketPolicyOnly *bool            `json:"bucket_policy_only"`
+	Name           string            `json:"name"`
+	Project        string            `json:"project"`
+	Location       string            `json:"location"`
+	StorageClass   string            `json:"storage_class,omitempty"`
+	ForceDestroy   bool              `json:"force_destroy,omitempty"`
+	Labels         map[string]string `json:"labels,omitempty"`
+	LifecycleRules []*LifecycleRule  `json:"lifecycle_rule,omitempty"`
+	Logging        *Logging          `json:"logging,omitempty"`
+	Versioning     versioning        `json:"versioning,omitempty"`
+
+	// BucketPolicyOnly is the deprecated form of UniformBucketLevelAccess. At most one of the two can be set.
+	// BucketPolicyOnly defaults to true if neither is set, so existing buckets are not changed.
+	// Set UniformBucketLevelAccess instead to migrate a bucket to the new field.
+	BucketPolicyOnly         *bool `json:"bucket_policy_only,omitempty"`
+	UniformBucketLevelAccess *bool `json:"uniform_bucket_level_access,omitempty"`
 
 	DependsOn []string `json:"depends_on,omitempty"`
 
@@ -98,14 +107,19 @@ func (b *StorageBucket) Init(projectID string) error {
 		return fmt.Errorf("project must not be set: %q", b.Project)
 	}
 	b.Project = projectID
+	b.DependsOn = normalizeDependsOn(b.DependsOn)
 
 	if b.Versioning.Enabled != nil && !*b.Versioning.Enabled {
 		return errors.New("versioning must not be disabled")
 	}
 
+	if b.BucketPolicyOnly != nil && b.UniformBucketLevelAccess != nil {
+		return errors.New("only one of bucket_policy_only and uniform_bucket_level_access must be set")
+	}
+
 	t := true
 	b.Versioning.Enabled = &t
-	if b.BucketPolicyOnly == nil {
+	if b.BucketPolicyOnly == nil && b.UniformBucketLevelAccess == nil {
 		b.BucketPolicyOnly = &t
 	}
 
@@ -128,6 +142,20 @@ func (b *StorageBucket) ResourceType() string {
 	return "google_storage_bucket"
 }
 
+// GetLabels returns the labels of the resource.
+func (b *StorageBucket) GetLabels() map[string]string {
+	return b.Labels
+}
+
+// SetLabels sets the labels of the resource.
+func (b *StorageBucket) SetLabels(labels map[string]string) {
+	b.Labels = labels
+}
+
+func (b *StorageBucket) dependsOn() []string {
+	return b.DependsOn
+}
+
 // DependentResources returns the child resources of this resource.
 func (b *StorageBucket) DependentResources() []Resource {
 	if len(b.IAMMembers) == 0 {
@@ -208,3 +236,152 @@ func (m *StorageIAMMember) ID() string {
 func (m *StorageIAMMember) ResourceType() string {
 	return "google_storage_bucket_iam_member"
 }
+
+// StorageBucketObject represents a Terraform GCS bucket object.
+// Exactly one of Source and Content must be set.
+type StorageBucketObject struct {
+	Name string `json:"name"`
+
+	// Bucket is the bucket's terraform reference or the name of a bucket in the deployment.
+	Bucket string `json:"bucket"`
+
+	// Source is the absolute path to a local file to upload.
+	// Terraform runs in a temporary directory, so a relative path would not resolve to the user's file.
+	Source string `json:"source,omitempty"`
+
+	// Content is the inline content of the object.
+	Content string `json:"content,omitempty"`
+
+	ContentType string `json:"content_type,omitempty"`
+
+	// KMSKeyName is the CMEK key used to encrypt the object, e.g. Ref(key, "id").
+	KMSKeyName string `json:"kms_key_name,omitempty"`
+
+	DependsOn []string `json:"depends_on,omitempty"`
+}
+
+// Init initializes the resource.
+// Bucket objects do not have a project field so the project ID is unused.
+func (o *StorageBucketObject) Init(string) error {
+	if o.Name == "" {
+		return errors.New("name must be set")
+	}
+	if o.Bucket == "" {
+		return fmt.Errorf("bucket of object %q must be set", o.Name)
+	}
+	o.Bucket = nameRef("google_storage_bucket", o.Bucket, "name")
+	o.DependsOn = normalizeDependsOn(o.DependsOn)
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (o *StorageBucketObject) Validate() error {
+	if (o.Source == "") == (o.Content == "") {
+		return fmt.Errorf("exactly one of source and content of object %q must be set", o.Name)
+	}
+	if o.Source != "" && !filepath.IsAbs(o.Source) {
+		return fmt.Errorf("source %q of object %q must be an absolute path", o.Source, o.Name)
+	}
+	if o.KMSKeyName != "" {
+		if err := validateKMSCryptoKeyName(o.KMSKeyName); err != nil {
+			return fmt.Errorf("invalid kms_key_name of object %q: %v", o.Name, err)
+		}
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+// It combines the bucket and object names as object names are only unique within a bucket.
+func (o *StorageBucketObject) ID() string {
+	return standardizeID(fmt.Sprintf("%s_%s", refName(o.Bucket), o.Name))
+}
+
+// ResourceType returns the resource terraform provider type.
+func (o *StorageBucketObject) ResourceType() string {
+	return "google_storage_bucket_object"
+}
+
+func (o *StorageBucketObject) dependsOn() []string {
+	return o.DependsOn
+}
+
+// StorageBucketIAMMembers represents multiple Terraform GCS bucket IAM members across one or more buckets.
+// It is used to wrap and merge multiple IAM members into a single IAM member when being marshalled to JSON.
+// Unlike the IAM members set on a StorageBucket, each member sets the bucket it should be granted on,
+// which allows granting access on buckets not defined in the deployment.
+type StorageBucketIAMMembers struct {
+	Members   []*StorageBucketIAMMember
+	DependsOn []string
+}
+
+// StorageBucketIAMMember represents a Terraform GCS bucket IAM member.
+type StorageBucketIAMMember struct {
+	Bucket    string        `json:"bucket"`
+	Role      string        `json:"role"`
+	Member    string        `json:"member"`
+	Condition *IAMCondition `json:"condition,omitempty"`
+
+	// The following fields should not be set by users.
+
+	// ForEach is used to let a single iam member expand to reference multiple iam members
+	// through the use of terraform's for_each iterator.
+	ForEach   map[string]*StorageBucketIAMMember `json:"for_each,omitempty"`
+	DependsOn []string                           `json:"depends_on,omitempty"`
+
+	// Dynamic holds the condition dynamic block of the member, see memberConditionDynamicBlock.
+	Dynamic map[string]interface{} `json:"dynamic,omitempty"`
+}
+
+// Init initializes the resource.
+// Bucket IAM members do not have a project field so the project ID is unused.
+func (ms *StorageBucketIAMMembers) Init(string) error {
+	for _, m := range ms.Members {
+		if m.Bucket == "" {
+			return fmt.Errorf("bucket must be set for role %q and member %q", m.Role, m.Member)
+		}
+		if err := validateIAMMember(m.Member); err != nil {
+			return fmt.Errorf("invalid member for role %q on bucket %q: %v", m.Role, m.Bucket, err)
+		}
+	}
+	return nil
+}
+
+func (m *StorageBucketIAMMember) forEachKey() string {
+	return resourceMemberKey(m.Bucket, m.Role, m.Member, m.Condition)
+}
+
+func (m *StorageBucketIAMMember) iamCondition() *IAMCondition {
+	return m.Condition
+}
+
+// ID returns the resource unique identifier.
+// It is hardcoded to return "buckets" as there is at most one of this resource in a deployment.
+func (ms *StorageBucketIAMMembers) ID() string {
+	return "buckets"
+}
+
+// ResourceType returns the resource terraform provider type.
+func (ms *StorageBucketIAMMembers) ResourceType() string {
+	return "google_storage_bucket_iam_member"
+}
+
+func (ms *StorageBucketIAMMembers) dependsOn() []string {
+	return ms.DependsOn
+}
+
+// MarshalJSON marshals the list of members into a single member.
+// The single member will set a for_each block to expand to multiple iam members in the terraform call.
+// See forEachKeys for details on how the for_each keys are built.
+func (ms *StorageBucketIAMMembers) MarshalJSON() ([]byte, error) {
+	return marshalForEachMembers(&StorageBucketIAMMember{
+		Bucket:    "${each.value.bucket}",
+		Role:      "${each.value.role}",
+		Member:    "${each.value.member}",
+		DependsOn: normalizeDependsOn(ms.DependsOn),
+	}, ms.Members)
+}
+
+// UnmarshalJSON unmarshals the bytes to a list of members.
+func (ms *StorageBucketIAMMembers) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &ms.Members)
+}
@@ -0,0 +1,318 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStorageBucketIAMMembers(t *testing.T) {
+	ms := &StorageBucketIAMMembers{Members: []*StorageBucketIAMMember{
+		{Bucket: "foo-bucket", Role: "roles/storage.objectViewer", Member: "serviceAccount:foo@my-project.iam.gserviceaccount.com"},
+		{Bucket: "bar-bucket", Role: "roles/storage.objectViewer", Member: "serviceAccount:foo@my-project.iam.gserviceaccount.com"},
+		{
+			Bucket: "bar-bucket",
+			Role:   "roles/storage.objectCreator",
+			Member: "serviceAccount:foo@my-project.iam.gserviceaccount.com",
+			Condition: &IAMCondition{
+				Title:      "exports_only",
+				Expression: `resource.name.startsWith("projects/_/buckets/bar-bucket/objects/exports/")`,
+			},
+		},
+	}}
+	if err := ms.Init("my-project"); err != nil {
+		t.Fatalf("ms.Init = %v", err)
+	}
+
+	want := `{
+  "for_each": {
+    "foo-bucket roles/storage.objectViewer serviceAccount:foo@my-project.iam.gserviceaccount.com": {
+      "bucket": "foo-bucket",
+      "role": "roles/storage.objectViewer",
+      "member": "serviceAccount:foo@my-project.iam.gserviceaccount.com"
+    },
+    "bar-bucket roles/storage.objectViewer serviceAccount:foo@my-project.iam.gserviceaccount.com": {
+      "bucket": "bar-bucket",
+      "role": "roles/storage.objectViewer",
+      "member": "serviceAccount:foo@my-project.iam.gserviceaccount.com"
+    },
+    "bar-bucket roles/storage.objectCreator serviceAccount:foo@my-project.iam.gserviceaccount.com exports_only": {
+      "bucket": "bar-bucket",
+      "role": "roles/storage.objectCreator",
+      "member": "serviceAccount:foo@my-project.iam.gserviceaccount.com",
+      "condition": {
+        "title": "exports_only",
+        "expression": "resource.name.startsWith(\"projects/_/buckets/bar-bucket/objects/exports/\")"
+      }
+    }
+  },
+  "bucket": "${each.value.bucket}",
+  "role": "${each.value.role}",
+  "member": "${each.value.member}",
+  "dynamic": {
+    "condition": {
+      "for_each": "${lookup(each.value, \"condition\", null) == null ? [] : [each.value.condition]}",
+      "content": {
+        "title": "${condition.value.title}",
+        "description": "${lookup(condition.value, \"description\", null)}",
+        "expression": "${condition.value.expression}"
+      }
+    }
+  }
+}`
+	checkJSON(t, ms, want)
+}
+
+func TestStorageBucketIAMMembersReferenceKey(t *testing.T) {
+	m := &StorageBucketIAMMember{Bucket: "${google_storage_bucket.foo-bucket.name}", Role: "roles/storage.objectViewer", Member: "group:foo@my-domain.com"}
+	if got, want := m.forEachKey(), "foo-bucket roles/storage.objectViewer group:foo@my-domain.com"; got != want {
+		t.Errorf("m.forEachKey() = %q, want %q", got, want)
+	}
+}
+
+func TestStorageBucketIAMMembersInitErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		member *StorageBucketIAMMember
+	}{
+		{
+			name:   "missing_bucket",
+			member: &StorageBucketIAMMember{Role: "roles/storage.objectViewer", Member: "group:foo@my-domain.com"},
+		},
+		{
+			name:   "invalid_member",
+			member: &StorageBucketIAMMember{Bucket: "foo-bucket", Role: "roles/storage.objectViewer", Member: "foo@my-domain.com"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ms := &StorageBucketIAMMembers{Members: []*StorageBucketIAMMember{tc.member}}
+			if err := ms.Init("my-project"); err == nil {
+				t.Error("ms.Init = nil, want error")
+			}
+		})
+	}
+}
+
+func TestStorageBucketUniformBucketLevelAccess(t *testing.T) {
+	tr, f := true, false
+	cases := []struct {
+		name string
+		b    *StorageBucket
+		want string
+	}{
+		{
+			name: "default",
+			b:    &StorageBucket{Name: "foo-bucket", Location: "US"},
+			want: `{
+  "name": "foo-bucket",
+  "project": "my-project",
+  "location": "US",
+  "versioning": {"enabled": true},
+  "bucket_policy_only": true
+}`,
+		},
+		{
+			name: "uniform_bucket_level_access",
+			b:    &StorageBucket{Name: "foo-bucket", Location: "US", UniformBucketLevelAccess: &tr},
+			want: `{
+  "name": "foo-bucket",
+  "project": "my-project",
+  "location": "US",
+  "versioning": {"enabled": true},
+  "uniform_bucket_level_access": true
+}`,
+		},
+		{
+			name: "explicitly_disabled",
+			b:    &StorageBucket{Name: "foo-bucket", Location: "US", UniformBucketLevelAccess: &f},
+			want: `{
+  "name": "foo-bucket",
+  "project": "my-project",
+  "location": "US",
+  "versioning": {"enabled": true},
+  "uniform_bucket_level_access": false
+}`,
+		},
+		{
+			name: "bucket_policy_only",
+			b:    &StorageBucket{Name: "foo-bucket", Location: "US", BucketPolicyOnly: &f},
+			want: `{
+  "name": "foo-bucket",
+  "project": "my-project",
+  "location": "US",
+  "versioning": {"enabled": true},
+  "bucket_policy_only": false
+}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.b.Init("my-project"); err != nil {
+				t.Fatalf("b.Init = %v", err)
+			}
+			checkJSON(t, tc.b, tc.want)
+		})
+	}
+}
+
+func TestStorageBucketBothAccessFieldsSet(t *testing.T) {
+	tr := true
+	b := &StorageBucket{Name: "foo-bucket", Location: "US", BucketPolicyOnly: &tr, UniformBucketLevelAccess: &tr}
+	if err := b.Init("my-project"); err == nil {
+		t.Error("b.Init = nil, want error")
+	}
+}
+
+func TestStorageBucketLifecycleRules(t *testing.T) {
+	data := `{
+  "name": "foo-bucket",
+  "location": "US",
+  "storage_class": "MULTI_REGIONAL",
+  "force_destroy": true,
+  "labels": {"team": "data", "env": "prod"},
+  "lifecycle_rule": [
+    {"action": {"type": "SetStorageClass", "storage_class": "NEARLINE"}, "condition": {"age": 30}},
+    {"action": {"type": "Delete"}, "condition": {"age": 365}}
+  ]
+}`
+	b := new(StorageBucket)
+	if err := json.Unmarshal([]byte(data), b); err != nil {
+		t.Fatalf("json.Unmarshal = %v", err)
+	}
+	if err := b.Init("my-project"); err != nil {
+		t.Fatalf("b.Init = %v", err)
+	}
+
+	got, err := json.Marshal(b)
+	if err != nil {
+		t.Fatalf("json.Marshal = %v", err)
+	}
+	want := `{"bucket_policy_only":true,"force_destroy":true,"labels":{"env":"prod","team":"data"},` +
+		`"lifecycle_rule":[{"action":{"storage_class":"NEARLINE","type":"SetStorageClass"},"condition":{"age":30}},` +
+		`{"action":{"type":"Delete"},"condition":{"age":365}}],` +
+		`"location":"US","name":"foo-bucket","project":"my-project","storage_class":"MULTI_REGIONAL",` +
+		`"versioning":{"enabled":true}}`
+	if string(got) != want {
+		t.Errorf("json.Marshal = %v, want %v", string(got), want)
+	}
+}
+
+func TestStorageBucketObject(t *testing.T) {
+	cases := []struct {
+		name   string
+		o      *StorageBucketObject
+		want   string
+		wantID string
+	}{
+		{
+			name: "inline_content",
+			o: &StorageBucketObject{
+				Name:        "config/settings.json",
+				Bucket:      "foo-bucket",
+				Content:     `{"debug": false}`,
+				ContentType: "application/json",
+				KMSKeyName:  "${google_kms_crypto_key.bucket-key.id}",
+			},
+			want: `{
+  "name": "config/settings.json",
+  "bucket": "${google_storage_bucket.foo-bucket.name}",
+  "content": "{\"debug\": false}",
+  "content_type": "application/json",
+  "kms_key_name": "${google_kms_crypto_key.bucket-key.id}"
+}`,
+			wantID: "foo-bucket_config_settings_json",
+		},
+		{
+			name: "source_file",
+			o: &StorageBucketObject{
+				Name:   "seed.csv",
+				Bucket: "${google_storage_bucket.bar-bucket.name}",
+				Source: "/tmp/data/seed.csv",
+			},
+			want: `{
+  "name": "seed.csv",
+  "bucket": "${google_storage_bucket.bar-bucket.name}",
+  "source": "/tmp/data/seed.csv"
+}`,
+			wantID: "bar-bucket_seed_csv",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.o.Init("my-project"); err != nil {
+				t.Fatalf("o.Init = %v", err)
+			}
+			if err := tc.o.Validate(); err != nil {
+				t.Fatalf("o.Validate = %v", err)
+			}
+			checkJSON(t, tc.o, tc.want)
+			if got := tc.o.ID(); got != tc.wantID {
+				t.Errorf("o.ID() = %v, want %v", got, tc.wantID)
+			}
+		})
+	}
+}
+
+func TestStorageBucketObjectValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		o       *StorageBucketObject
+		wantErr bool
+	}{
+		{
+			name:    "neither_source_nor_content",
+			o:       &StorageBucketObject{},
+			wantErr: true,
+		},
+		{
+			name:    "both_source_and_content",
+			o:       &StorageBucketObject{Source: "data/seed.csv", Content: "a,b"},
+			wantErr: true,
+		},
+		{
+			name:    "relative_source",
+			o:       &StorageBucketObject{Source: "data/seed.csv"},
+			wantErr: true,
+		},
+		{
+			name: "absolute_source",
+			o:    &StorageBucketObject{Source: "/tmp/data/seed.csv"},
+		},
+		{
+			name:    "invalid_kms_key_name",
+			o:       &StorageBucketObject{Content: "a,b", KMSKeyName: "bucket-key"},
+			wantErr: true,
+		},
+		{
+			name: "full_kms_key_name",
+			o:    &StorageBucketObject{Content: "a,b", KMSKeyName: "projects/my-project/locations/us/keyRings/ring/cryptoKeys/bucket-key"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.o.Name = "seed.csv"
+			tc.o.Bucket = "foo-bucket"
+			if err := tc.o.Init("my-project"); err != nil {
+				t.Fatalf("o.Init = %v", err)
+			}
+			err := tc.o.Validate()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("o.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
+)
+
+// CloudTasksQueue represents a Terraform Cloud Tasks queue.
+type CloudTasksQueue struct {
+	Name        string            `json:"name"`
+	Project     string            `json:"project"`
+	Provider    string            `json:"provider,omitempty"`
+	Location    string            `json:"location"`
+	RateLimits  *TasksRateLimits  `json:"rate_limits,omitempty"`
+	RetryConfig *TasksRetryConfig `json:"retry_config,omitempty"`
+}
+
+// TasksRateLimits are the rate limits of task dispatches in a queue.
+type TasksRateLimits struct {
+	MaxDispatchesPerSecond  float64 `json:"max_dispatches_per_second,omitempty"`
+	MaxConcurrentDispatches int     `json:"max_concurrent_dispatches,omitempty"`
+}
+
+// TasksRetryConfig is the retry behaviour of failed tasks in a queue.
+type TasksRetryConfig struct {
+	// MaxAttempts is the number of attempts per task, or -1 for unlimited attempts.
+	MaxAttempts int `json:"max_attempts,omitempty"`
+
+	// Durations are in seconds, e.g. "0.1s".
+	MaxRetryDuration string `json:"max_retry_duration,omitempty"`
+	MinBackoff       string `json:"min_backoff,omitempty"`
+	MaxBackoff       string `json:"max_backoff,omitempty"`
+	MaxDoublings     int    `json:"max_doublings,omitempty"`
+}
+
+// Init initializes the resource.
+func (q *CloudTasksQueue) Init(projectID string) error {
+	if q.Name == "" {
+		return errors.New("name must be set")
+	}
+	if q.Location == "" {
+		return errors.New("location must be set")
+	}
+	if q.Project != "" {
+		return fmt.Errorf("project must be unset: %v", q.Project)
+	}
+	q.Project = projectID
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (q *CloudTasksQueue) Validate() error {
+	if l := q.RateLimits; l != nil && (l.MaxDispatchesPerSecond < 0 || l.MaxConcurrentDispatches < 0) {
+		return fmt.Errorf("rate limits of queue %q must not be negative", q.Name)
+	}
+	c := q.RetryConfig
+	if c == nil {
+		return nil
+	}
+	if c.MaxAttempts < -1 {
+		return fmt.Errorf("max attempts of queue %q must be at least -1 (unlimited), got %d", q.Name, c.MaxAttempts)
+	}
+	for _, d := range []string{c.MaxRetryDuration, c.MinBackoff, c.MaxBackoff} {
+		if d != "" && !durationRE.MatchString(d) {
+			return fmt.Errorf("retry duration %q of queue %q must be a duration in seconds, e.g. \"0.1s\"", d, q.Name)
+		}
+	}
+	if c.MinBackoff != "" && c.MaxBackoff != "" {
+		min, _ := strconv.ParseFloat(strings.TrimSuffix(c.MinBackoff, "s"), 64)
+		max, _ := strconv.ParseFloat(strings.TrimSuffix(c.MaxBackoff, "s"), 64)
+		if min > max {
+			return fmt.Errorf("min backoff %q of queue %q must not be greater than max backoff %q", c.MinBackoff, q.Name, c.MaxBackoff)
+		}
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (q *CloudTasksQueue) ID() string {
+	return q.Name
+}
+
+// ResourceType returns the resource terraform provider type.
+func (*CloudTasksQueue) ResourceType() string {
+	return "google_cloud_tasks_queue"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (q *CloudTasksQueue) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("projects/%s/locations/%s/queues/%s", q.Project, q.Location, q.Name), nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"testing"
+)
+
+func TestCloudTasksQueue(t *testing.T) {
+	q := &CloudTasksQueue{
+		Name:     "deid-tasks",
+		Location: "us-central1",
+		RateLimits: &TasksRateLimits{
+			MaxDispatchesPerSecond:  5.5,
+			MaxConcurrentDispatches: 10,
+		},
+		RetryConfig: &TasksRetryConfig{
+			MaxAttempts:  -1,
+			MinBackoff:   "0.5s",
+			MaxBackoff:   "300s",
+			MaxDoublings: 4,
+		},
+	}
+	if err := q.Init("my-project"); err != nil {
+		t.Fatalf("q.Init = %v", err)
+	}
+	if err := q.Validate(); err != nil {
+		t.Fatalf("q.Validate = %v", err)
+	}
+
+	want := `{
+  "name": "deid-tasks",
+  "project": "my-project",
+  "location": "us-central1",
+  "rate_limits": {
+    "max_dispatches_per_second": 5.5,
+    "max_concurrent_dispatches": 10
+  },
+  "retry_config": {
+    "max_attempts": -1,
+    "min_backoff": "0.5s",
+    "max_backoff": "300s",
+    "max_doublings": 4
+  }
+}`
+	checkJSON(t, q, want)
+}
+
+func TestCloudTasksQueueValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		retry   *TasksRetryConfig
+		limits  *TasksRateLimits
+		wantErr bool
+	}{
+		{
+			name: "no_config",
+		},
+		{
+			name:  "retry_duration",
+			retry: &TasksRetryConfig{MaxAttempts: 5, MaxRetryDuration: "3600s"},
+		},
+		{
+			name:    "max_attempts_too_low",
+			retry:   &TasksRetryConfig{MaxAttempts: -2},
+			wantErr: true,
+		},
+		{
+			name:    "min_backoff_not_seconds",
+			retry:   &TasksRetryConfig{MinBackoff: "100ms"},
+			wantErr: true,
+		},
+		{
+			name:    "max_backoff_no_unit",
+			retry:   &TasksRetryConfig{MaxBackoff: "300"},
+			wantErr: true,
+		},
+		{
+			name:    "max_retry_duration_minutes",
+			retry:   &TasksRetryConfig{MaxRetryDuration: "5m"},
+			wantErr: true,
+		},
+		{
+			name:    "min_backoff_greater_than_max",
+			retry:   &TasksRetryConfig{MinBackoff: "10s", MaxBackoff: "1s"},
+			wantErr: true,
+		},
+		{
+			name:    "negative_rate_limit",
+			limits:  &TasksRateLimits{MaxDispatchesPerSecond: -1},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			q := &CloudTasksQueue{Name: "foo-queue", RateLimits: tc.limits, RetryConfig: tc.retry}
+			if err := q.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("q.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
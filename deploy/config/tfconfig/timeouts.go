@@ -0,0 +1,52 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"fmt"
+	"time"
+)
+
+// Timeouts represents a Terraform timeouts block for resources with long running operations.
+// Resources carry one through a "timeouts" field, which is marshalled alongside the resource's own fields.
+// See https://www.terraform.io/docs/configuration/resources.html#operation-timeouts.
+type Timeouts struct {
+	// Create, Update and Delete are durations such as "30m" or "1h". Unset operations use the provider defaults.
+	Create string `json:"create,omitempty"`
+	Update string `json:"update,omitempty"`
+	Delete string `json:"delete,omitempty"`
+}
+
+// Validate checks that the timeouts are valid durations.
+func (t *Timeouts) Validate() error {
+	durations := []struct{ op, d string }{
+		{"create", t.Create},
+		{"update", t.Update},
+		{"delete", t.Delete},
+	}
+	for _, d := range durations {
+		if d.d == "" {
+			continue
+		}
+		v, err := time.ParseDuration(d.d)
+		if err != nil {
+			return fmt.Errorf("invalid %s timeout %q: %v", d.op, d.d, err)
+		}
+		if v <= 0 {
+			return fmt.Errorf("invalid %s timeout %q: must be positive", d.op, d.d)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"testing"
+)
+
+func TestTimeouts(t *testing.T) {
+	cases := []struct {
+		name string
+		t    *Timeouts
+		want string
+	}{
+		{
+			name: "empty",
+			t:    &Timeouts{},
+			want: `{}`,
+		},
+		{
+			name: "all_fields",
+			t:    &Timeouts{Create: "30m", Update: "1h", Delete: "1h30m"},
+			want: `{
+  "create": "30m",
+  "update": "1h",
+  "delete": "1h30m"
+}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.t.Validate(); err != nil {
+				t.Fatalf("t.Validate = %v", err)
+			}
+			checkJSON(t, tc.t, tc.want)
+		})
+	}
+}
+
+func TestTimeoutsValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		t       *Timeouts
+		wantErr bool
+	}{
+		{name: "valid", t: &Timeouts{Create: "30m", Delete: "10s"}},
+		{name: "no_unit", t: &Timeouts{Create: "30"}, wantErr: true},
+		{name: "bad_unit", t: &Timeouts{Update: "1d"}, wantErr: true},
+		{name: "zero", t: &Timeouts{Delete: "0s"}, wantErr: true},
+		{name: "negative", t: &Timeouts{Create: "-5m"}, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.t.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("t.Validate = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
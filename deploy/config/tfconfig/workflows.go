@@ -0,0 +1,99 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
+)
+
+// WorkflowsWorkflow represents a Terraform Cloud Workflows workflow.
+type WorkflowsWorkflow struct {
+	Name        string `json:"name"`
+	Project     string `json:"project"`
+	Provider    string `json:"provider,omitempty"`
+	Region      string `json:"region"`
+	Description string `json:"description,omitempty"`
+
+	// ServiceAccount is the email of the service account the workflow runs as.
+	// It can be a reference to a service account, e.g. ServiceAccount.Ref("email").
+	ServiceAccount string `json:"service_account,omitempty"`
+
+	// SourceContents is the YAML or JSON workflow definition.
+	// It is kept verbatim: workflow expressions such as "${x}" are escaped when marshalled so terraform does not interpolate them.
+	SourceContents string `json:"source_contents"`
+}
+
+// Init initializes the resource.
+func (w *WorkflowsWorkflow) Init(projectID string) error {
+	if w.Name == "" {
+		return errors.New("name must be set")
+	}
+	if w.Region == "" {
+		return errors.New("region must be set")
+	}
+	if w.Project != "" {
+		return fmt.Errorf("project must be unset: %v", w.Project)
+	}
+	w.Project = projectID
+	return nil
+}
+
+// Validate checks that the resource is valid.
+func (w *WorkflowsWorkflow) Validate() error {
+	if strings.TrimSpace(w.SourceContents) == "" {
+		return fmt.Errorf("source_contents must be set for workflow %q", w.Name)
+	}
+	return nil
+}
+
+// ID returns the resource unique identifier.
+func (w *WorkflowsWorkflow) ID() string {
+	return w.Name
+}
+
+// ResourceType returns the resource terraform provider type.
+func (*WorkflowsWorkflow) ResourceType() string {
+	return "google_workflows_workflow"
+}
+
+// ImportID returns the ID to use for terraform imports.
+func (w *WorkflowsWorkflow) ImportID(runner.Runner) (string, error) {
+	return fmt.Sprintf("projects/%s/locations/%s/workflows/%s", w.Project, w.Region, w.Name), nil
+}
+
+// aliasWorkflowsWorkflow is used to prevent infinite recursion when dealing with json marshaling.
+// https://stackoverflow.com/q/52433467
+type aliasWorkflowsWorkflow WorkflowsWorkflow
+
+// MarshalJSON provides a custom JSON marshaller.
+// It is used to escape terraform template sequences in the source so terraform passes it through unchanged.
+func (w *WorkflowsWorkflow) MarshalJSON() ([]byte, error) {
+	alias := aliasWorkflowsWorkflow(*w)
+	alias.SourceContents = escapeTemplate(w.SourceContents)
+	return json.Marshal(alias)
+}
+
+// templateEscaper escapes the terraform interpolation and directive sequences.
+var templateEscaper = strings.NewReplacer("${", "$${", "%{", "%%{")
+
+// escapeTemplate returns s with terraform template sequences escaped so terraform evaluates it to s.
+func escapeTemplate(s string) string {
+	return templateEscaper.Replace(s)
+}
@@ -0,0 +1,107 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfconfig
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testWorkflowSource = `# Runs a FHIR import for each uploaded file.
+main:
+  params: [event]
+  steps:
+    - init:
+        assign:
+          - bucket: "gs://my-project-fhir-uploads"
+          - note: 'quotes " and \ backslashes & <brackets> stay'
+    - import:
+        call: http.post
+        args:
+          url: https://healthcare.googleapis.com/v1/import
+          auth:
+            type: OAuth2
+    # Trailing whitespace and tabs	are kept.   
+`
+
+func TestWorkflowsWorkflow(t *testing.T) {
+	w := &WorkflowsWorkflow{
+		Name:           "fhir-import",
+		Region:         "us-central1",
+		ServiceAccount: "${google_service_account.workflow-runner.email}",
+		SourceContents: testWorkflowSource,
+	}
+	if err := w.Init("my-project"); err != nil {
+		t.Fatalf("w.Init = %v", err)
+	}
+	if err := w.Validate(); err != nil {
+		t.Fatalf("w.Validate = %v", err)
+	}
+
+	b, err := json.Marshal(w)
+	if err != nil {
+		t.Fatalf("json.Marshal = %v", err)
+	}
+	got := make(map[string]interface{})
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("json.Unmarshal = %v", err)
+	}
+	if got["source_contents"] != testWorkflowSource {
+		t.Errorf("source_contents = %q, want %q", got["source_contents"], testWorkflowSource)
+	}
+	if got["service_account"] != "${google_service_account.workflow-runner.email}" {
+		t.Errorf("service_account = %q, want unescaped reference", got["service_account"])
+	}
+}
+
+func TestWorkflowsWorkflowEscapesExpressions(t *testing.T) {
+	source := "main:\n  steps:\n    - log:\n        return: ${sys.get_env(\"GOOGLE_CLOUD_PROJECT_ID\")} %{x}\n"
+	w := &WorkflowsWorkflow{Name: "foo", Region: "us-central1", SourceContents: source}
+	if err := w.Init("my-project"); err != nil {
+		t.Fatalf("w.Init = %v", err)
+	}
+
+	b, err := json.Marshal(w)
+	if err != nil {
+		t.Fatalf("json.Marshal = %v", err)
+	}
+	var got struct {
+		SourceContents string `json:"source_contents"`
+	}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("json.Unmarshal = %v", err)
+	}
+	want := "main:\n  steps:\n    - log:\n        return: $${sys.get_env(\"GOOGLE_CLOUD_PROJECT_ID\")} %%{x}\n"
+	if got.SourceContents != want {
+		t.Errorf("source_contents = %q, want %q", got.SourceContents, want)
+	}
+	// Terraform evaluates the escaped sequences back to the original source.
+	if unescaped := strings.NewReplacer("$${", "${", "%%{", "%{").Replace(got.SourceContents); unescaped != source {
+		t.Errorf("unescaped source_contents = %q, want %q", unescaped, source)
+	}
+	if w.SourceContents != source {
+		t.Errorf("w.SourceContents = %q, want it unchanged by marshalling", w.SourceContents)
+	}
+}
+
+func TestWorkflowsWorkflowValidate(t *testing.T) {
+	for _, source := range []string{"", " \n\t"} {
+		w := &WorkflowsWorkflow{Name: "foo", SourceContents: source}
+		if err := w.Validate(); err == nil {
+			t.Errorf("w.Validate(%q) = nil, want error", source)
+		}
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"os/exec"
 	"path/filepath"
 
+	"github.com/GoogleCloudPlatform/healthcare/deploy/config/tfconfig"
 	"github.com/GoogleCloudPlatform/healthcare/deploy/runner"
 	"github.com/imdario/mergo"
 )
@@ -67,6 +68,24 @@ func Apply(config *Config, dir string, opts *Options, rn runner.Runner) error {
 		}
 	}
 
+	if config.Terraform != nil && config.Terraform.Backend != nil {
+		if err := config.Terraform.Backend.Validate(); err != nil {
+			return fmt.Errorf("invalid terraform backend: %v", err)
+		}
+	}
+
+	if config.Terraform != nil {
+		if err := config.Terraform.RequiredProviders.Init(); err != nil {
+			return fmt.Errorf("invalid terraform required providers: %v", err)
+		}
+	}
+
+	for _, v := range config.Variables {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("invalid terraform variable: %v", err)
+		}
+	}
+
 	runCmd := func(args ...string) error {
 		cmd := exec.Command("terraform", args...)
 		cmd.Dir = dir
@@ -91,7 +110,11 @@ func Apply(config *Config, dir string, opts *Options, rn runner.Runner) error {
 		}
 	}
 
-	log.Printf("terraform config:\n%v", string(b))
+	if err := CheckProviderAliases(b); err != nil {
+		return fmt.Errorf("invalid terraform config: %v", err)
+	}
+
+	log.Printf("terraform config:\n%v", tfconfig.RedactConfig(b))
 
 	// drw-r--r--
 	if err := ioutil.WriteFile(filepath.Join(dir, "main.tf.json"), b, 0644); err != nil {
@@ -137,3 +137,28 @@ func TestApply(t *testing.T) {
 
 	// TODO: test with actual modules
 }
+
+func TestApplyUndeclaredProviderAlias(t *testing.T) {
+	conf := NewConfig()
+	conf.Resources = []*Resource{{
+		Name:       "foo-account",
+		Type:       "google_service_account",
+		Properties: map[string]interface{}{"account_id": "foo-account", "provider": "google.project_a"},
+	}}
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := Apply(conf, dir, nil, &testRunner{}); err == nil {
+		t.Fatal("Apply = nil, want error")
+	}
+
+	opts := &Options{CustomConfig: map[string]interface{}{
+		"provider": []interface{}{map[string]interface{}{"google": map[string]interface{}{"project": "project-a", "alias": "project_a"}}},
+	}}
+	if err := Apply(conf, dir, opts, &testRunner{}); err != nil {
+		t.Errorf("Apply with custom provider config = %v", err)
+	}
+}
@@ -17,7 +17,12 @@ package terraform
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 // Config represents a Terraform config.
@@ -27,40 +32,322 @@ import (
 type Config struct {
 	Providers []*Provider `json:"provider,omitempty"`
 	Terraform *Terraform  `json:"terraform,omitempty"`
+	Variables []*Variable `json:"variable,omitempty"`
 	Data      []*Resource `json:"data,omitempty"`
 	Modules   []*Module   `json:"module,omitempty"`
 	Resources []*Resource `json:"resource,omitempty"`
 	Outputs   []*Output   `json:"output,omitempty"`
+
+	// Imports are import blocks, which are only supported from Terraform 1.5.
+	Imports []*Import `json:"import,omitempty"`
 }
 
+// defaultRequiredVersion is the terraform version constraint of new configs.
+const defaultRequiredVersion = ">= 0.12.0"
+
+// requiredProviderSourceVersion is the terraform version constraint of configs setting required provider sources,
+// which are only supported from Terraform 0.13.
+const requiredProviderSourceVersion = ">= 0.13.0"
+
 // NewConfig returns a new terraform config.
 func NewConfig() *Config {
 	c := &Config{
 		Terraform: &Terraform{
-			RequiredVersion: ">= 0.12.0",
+			RequiredVersion: defaultRequiredVersion,
 		},
 	}
 	return c
 }
 
 // Provider provides a terraform provider config.
+// See https://www.terraform.io/docs/providers/google/guides/provider_reference.html.
 type Provider struct {
-	Name       string
+	// Name is the provider name, e.g. "google" or "google-beta". Defaults to "google".
+	Name    string
+	Project string
+	Region  string
+	Zone    string
+
+	// Alias allows multiple configs of the same provider.
+	// Resources use an aliased provider by setting their provider field to "<name>.<alias>".
+	Alias string
+
+	// Properties holds any additional provider fields.
 	Properties map[string]interface{}
 }
 
+// Init initializes the provider.
+func (p *Provider) Init() error {
+	if p.Project == "" {
+		return errors.New("project must be set")
+	}
+	if p.Name == "" {
+		p.Name = "google"
+	}
+	return nil
+}
+
 // MarshalJSON implements a custom marshaller which marshals properties to be under name.
 func (p *Provider) MarshalJSON() ([]byte, error) {
+	props := make(map[string]interface{})
+	for k, v := range p.Properties {
+		props[k] = v
+	}
+	fields := map[string]string{
+		"project": p.Project,
+		"region":  p.Region,
+		"zone":    p.Zone,
+		"alias":   p.Alias,
+	}
+	for k, v := range fields {
+		if v != "" {
+			props[k] = v
+		}
+	}
 	return json.Marshal(map[string]interface{}{
-		p.Name: p.Properties,
+		p.Name: props,
 	})
 }
 
+// CheckProviderAliases checks that all resources and data sources of the given JSON config that use an aliased provider,
+// e.g. "google.project_a", reference a provider block declared with that alias.
+// The config should be the full config that is applied, including any custom config merged into it.
+// Unaliased providers are not checked as terraform creates default provider configs on demand.
+func CheckProviderAliases(config []byte) error {
+	var top map[string]interface{}
+	if err := json.Unmarshal(config, &top); err != nil {
+		return fmt.Errorf("failed to unmarshal config: %v", err)
+	}
+
+	declared := make(map[string]bool)
+	for _, block := range blocks(top["provider"]) {
+		for name, v := range block {
+			for _, p := range blocks(v) {
+				if alias, ok := p["alias"].(string); ok && alias != "" {
+					declared[name+"."+alias] = true
+				}
+			}
+		}
+	}
+
+	var errs []string
+	for _, kind := range []string{"resource", "data"} {
+		for _, block := range blocks(top[kind]) {
+			for typ, v := range block {
+				for _, byName := range blocks(v) {
+					for name, props := range byName {
+						for _, p := range blocks(props) {
+							provider, _ := p["provider"].(string)
+							if !strings.Contains(provider, ".") || declared[provider] {
+								continue
+							}
+							errs = append(errs, fmt.Sprintf("%s.%s uses undeclared provider %q", typ, name, provider))
+						}
+					}
+				}
+			}
+		}
+	}
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return fmt.Errorf("found resources using undeclared provider aliases: %v", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// blocks returns the objects of a terraform JSON block, which can either be set as a single object or a list of objects.
+func blocks(v interface{}) []map[string]interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{v}
+	case []interface{}:
+		var ms []map[string]interface{}
+		for _, e := range v {
+			if m, ok := e.(map[string]interface{}); ok {
+				ms = append(ms, m)
+			}
+		}
+		return ms
+	}
+	return nil
+}
+
 // Terraform provides a terraform block config.
 // See https://www.terraform.io/docs/configuration/terraform.html for details.
 type Terraform struct {
-	RequiredVersion string   `json:"required_version,omitempty"`
-	Backend         *Backend `json:"backend,omitempty"`
+	RequiredVersion   string            `json:"required_version,omitempty"`
+	RequiredProviders RequiredProviders `json:"required_providers,omitempty"`
+	Backend           *Backend          `json:"backend,omitempty"`
+}
+
+// MarshalJSON implements a custom marshaller which raises the default required version to ">= 0.13.0"
+// if a required provider sets a source.
+func (t *Terraform) MarshalJSON() ([]byte, error) {
+	type alias Terraform // use type alias to avoid infinite recursion
+	a := alias(*t)
+	if (a.RequiredVersion == "" || a.RequiredVersion == defaultRequiredVersion) && a.RequiredProviders.hasSource() {
+		a.RequiredVersion = requiredProviderSourceVersion
+	}
+	return json.Marshal(a)
+}
+
+// RequireVersion raises the required version so the config requires at least the given Terraform version, e.g. "1.5.0".
+// The required version is kept if one of its constraints already requires the given version or a later one.
+// Otherwise, its lower bounds are replaced by ">= <version>" and its other constraints, such as upper bounds, are kept.
+func (t *Terraform) RequireVersion(version string) {
+	var cs []string
+	if t.RequiredVersion != "" {
+		for _, c := range strings.Split(t.RequiredVersion, ",") {
+			c = strings.TrimSpace(c)
+			m := versionOperatorRE.FindStringSubmatch(c)
+			op, v := m[1], m[2]
+			switch {
+			case op != "!=" && op != "<" && op != "<=" && compareVersions(v, version) >= 0:
+				return
+			case op == ">=" || op == ">":
+				// Replaced by the new lower bound.
+			default:
+				cs = append(cs, c)
+			}
+		}
+	}
+	t.RequiredVersion = strings.Join(append(cs, ">= "+version), ", ")
+}
+
+// versionOperatorRE splits a single version constraint into its operator and version.
+var versionOperatorRE = regexp.MustCompile(`^(=|!=|>=|>|<=|<|~>)?\s*(.*)$`)
+
+// versionRE splits a version into its numeric segments and its pre-release, ignoring any build metadata.
+var versionRE = regexp.MustCompile(`^v?([0-9]+(?:\.[0-9]+)*)([^+]*)`)
+
+// compareVersions returns -1, 0 or 1 if version a is respectively lower than, equal to or greater than version b.
+// Missing segments are zero and a pre-release is lower than its release. Malformed versions are lower than any other.
+func compareVersions(a, b string) int {
+	ma, mb := versionRE.FindStringSubmatch(a), versionRE.FindStringSubmatch(b)
+	switch {
+	case ma == nil && mb == nil:
+		return 0
+	case ma == nil:
+		return -1
+	case mb == nil:
+		return 1
+	}
+	sa, sb := strings.Split(ma[1], "."), strings.Split(mb[1], ".")
+	for i := 0; i < len(sa) || i < len(sb); i++ {
+		var na, nb int
+		if i < len(sa) {
+			na, _ = strconv.Atoi(sa[i])
+		}
+		if i < len(sb) {
+			nb, _ = strconv.Atoi(sb[i])
+		}
+		switch {
+		case na < nb:
+			return -1
+		case na > nb:
+			return 1
+		}
+	}
+	switch {
+	case ma[2] != "" && mb[2] == "":
+		return -1
+	case ma[2] == "" && mb[2] != "":
+		return 1
+	}
+	return 0
+}
+
+// RequiredProvider provides the source and version constraint of a provider used by the config.
+// See https://www.terraform.io/docs/configuration/provider-requirements.html.
+type RequiredProvider struct {
+	// Name is the provider local name, either "google" or "google-beta".
+	Name string `json:"-"`
+
+	// Source is optional, e.g. "hashicorp/google".
+	// Setting it requires Terraform 0.13 so the default required version of the config is raised to ">= 0.13.0".
+	Source string `json:"source,omitempty"`
+
+	// Version is a terraform version constraint, e.g. ">= 4.0, < 5.0".
+	Version string `json:"version,omitempty"`
+}
+
+// RequiredProviders provides the required_providers block of a terraform block.
+// It marshals to a map of provider name to requirements, or to the version constraint
+// supported by Terraform 0.12 if no source is set.
+type RequiredProviders []*RequiredProvider
+
+// requiredProviderNames are the providers that can be constrained.
+var requiredProviderNames = map[string]bool{
+	"google":      true,
+	"google-beta": true,
+}
+
+// versionConstraintRE matches a single terraform version constraint, e.g. ">= 4.0", "~> 4.5.1" or ">= 4.0.0-beta.1".
+// Versions follow the format of https://github.com/hashicorp/go-version, which terraform uses to parse constraints,
+// including pre-release versions with or without a leading dash and build metadata.
+var versionConstraintRE = regexp.MustCompile(`^(=|!=|>|>=|<|<=|~>)?\s*v?[0-9]+(\.[0-9]+)*` +
+	`(-([0-9]+[0-9A-Za-z~-]*(\.[0-9A-Za-z~-]+)*)|(-?([A-Za-z~-]+[0-9A-Za-z~-]*(\.[0-9A-Za-z~-]+)*)))?` +
+	`(\+([0-9A-Za-z~-]+(\.[0-9A-Za-z~-]+)*))?$`)
+
+// Init initializes and validates the required providers.
+func (rs RequiredProviders) Init() error {
+	seen := make(map[string]bool)
+	for _, r := range rs {
+		if !requiredProviderNames[r.Name] {
+			return fmt.Errorf("required provider name %q must be one of google or google-beta", r.Name)
+		}
+		if seen[r.Name] {
+			return fmt.Errorf("required provider %q is set more than once", r.Name)
+		}
+		seen[r.Name] = true
+		if err := validateVersionConstraint(r.Version); err != nil {
+			return fmt.Errorf("invalid version of required provider %q: %v", r.Name, err)
+		}
+	}
+	return nil
+}
+
+// validateVersionConstraint checks that the given comma separated version constraints can be parsed by terraform.
+// An empty version means any version is allowed.
+func validateVersionConstraint(version string) error {
+	if version == "" {
+		return nil
+	}
+	for _, c := range strings.Split(version, ",") {
+		if !versionConstraintRE.MatchString(strings.TrimSpace(c)) {
+			return fmt.Errorf("malformed version constraint %q in %q", c, version)
+		}
+	}
+	return nil
+}
+
+// MarshalJSON implements a custom marshaller which marshals each provider's requirements to be under its name.
+func (rs RequiredProviders) MarshalJSON() ([]byte, error) {
+	type alias RequiredProvider // use type alias to avoid infinite recursion
+	if !rs.hasSource() {
+		m := make(map[string]string)
+		for _, r := range rs {
+			if r.Version != "" {
+				m[r.Name] = r.Version
+			}
+		}
+		return json.Marshal(m)
+	}
+	m := make(map[string]alias)
+	for _, r := range rs {
+		m[r.Name] = alias(*r)
+	}
+	return json.Marshal(m)
+}
+
+// hasSource returns whether any of the required providers sets a source.
+func (rs RequiredProviders) hasSource() bool {
+	for _, r := range rs {
+		if r.Source != "" {
+			return true
+		}
+	}
+	return false
 }
 
 // Backend provides a terraform backend config.
@@ -70,12 +357,68 @@ type Backend struct {
 	Prefix string `json:"prefix,omitempty"`
 }
 
+// Validate checks that the backend is valid.
+func (b *Backend) Validate() error {
+	if b.Bucket == "" {
+		return errors.New("backend bucket must be set")
+	}
+	return nil
+}
+
 // MarshalJSON implements a custom marshaller which marshals the backend under a "gcs" block.
 func (b *Backend) MarshalJSON() ([]byte, error) {
 	type alias Backend // use type alias to avoid infinite recursion
 	return json.Marshal(map[string]interface{}{"gcs": alias(*b)})
 }
 
+// Variable provides a terraform input variable config.
+// See https://www.terraform.io/docs/configuration/variables.html.
+type Variable struct {
+	Name string `json:"-"`
+
+	// Type is a terraform type constraint, e.g. "string" or "list(string)".
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	// Default is omitted when nil, making the variable required.
+	Default   interface{} `json:"default,omitempty"`
+	Sensitive bool        `json:"sensitive,omitempty"`
+}
+
+// identifierRE matches valid terraform identifiers.
+var identifierRE = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]*$`)
+
+// reservedVariableNames are the names terraform reserves for meta-arguments.
+var reservedVariableNames = map[string]bool{
+	"count":      true,
+	"depends_on": true,
+	"for_each":   true,
+	"lifecycle":  true,
+	"locals":     true,
+	"providers":  true,
+	"source":     true,
+	"version":    true,
+}
+
+// Validate checks that the variable is valid.
+func (v *Variable) Validate() error {
+	if !identifierRE.MatchString(v.Name) {
+		return fmt.Errorf("variable name %q must start with a letter or underscore and only contain letters, digits, underscores and hyphens", v.Name)
+	}
+	if reservedVariableNames[v.Name] {
+		return fmt.Errorf("variable name %q is reserved by terraform", v.Name)
+	}
+	return nil
+}
+
+// MarshalJSON implements a custom marshaller which marshals the variable fields to be under name.
+func (v *Variable) MarshalJSON() ([]byte, error) {
+	type alias Variable // use type alias to avoid infinite recursion
+	return json.Marshal(map[string]interface{}{
+		v.Name: alias(*v),
+	})
+}
+
 // Module provides a terraform module config.
 // See https://www.terraform.io/docs/configuration/modules.html for details.
 type Module struct {
@@ -136,22 +479,36 @@ func (r *Resource) MarshalJSON() ([]byte, error) {
 }
 
 // Import defines fields used for a terraform import.
-// See https://www.terraform.io/docs/import/usage.html.
+// It is used both for the import command and for import blocks.
+// See https://www.terraform.io/docs/import/usage.html and https://developer.hashicorp.com/terraform/language/import.
 type Import struct {
-	Address string
-	ID      string
+	Address string `json:"to"`
+	ID      string `json:"id"`
 }
 
 // Output provides a terraform output config.
 // See https://www.terraform.io/docs/configuration/outputs.html.
 type Output struct {
-	Name  string
-	Value string
+	Name string
+
+	// Value is a terraform expression, e.g. a reference built with tfconfig.Ref.
+	Value       string
+	Description string
+
+	// Sensitive outputs are redacted in terraform plan and apply output.
+	Sensitive bool
 }
 
 // MarshalJSON implements a custom marshaller which marshals value to be under name.
 func (o *Output) MarshalJSON() ([]byte, error) {
+	props := map[string]interface{}{"value": o.Value}
+	if o.Description != "" {
+		props["description"] = o.Description
+	}
+	if o.Sensitive {
+		props["sensitive"] = true
+	}
 	return json.Marshal(map[string]interface{}{
-		o.Name: map[string]interface{}{"value": o.Value},
+		o.Name: props,
 	})
 }
@@ -0,0 +1,486 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraform
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/healthcare/deploy/config/tfconfig"
+	"github.com/google/go-cmp/cmp"
+)
+
+// checkJSON checks that the JSON marshalled value matches the wanted JSON.
+func checkJSON(t *testing.T, v interface{}, want string) {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("json.Marshal = %v", err)
+	}
+	var got, wantMap interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("json.Unmarshal got = %v", err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantMap); err != nil {
+		t.Fatalf("json.Unmarshal want = %v", err)
+	}
+	if diff := cmp.Diff(got, wantMap); diff != "" {
+		t.Errorf("json.Marshal differs (-got +want):\n%v", diff)
+	}
+}
+
+func TestProvider(t *testing.T) {
+	cases := []struct {
+		name string
+		p    *Provider
+		want string
+	}{
+		{
+			name: "default",
+			p:    &Provider{Project: "my-project", Region: "us-central1"},
+			want: `{
+  "google": {
+    "project": "my-project",
+    "region": "us-central1"
+  }
+}`,
+		},
+		{
+			name: "aliased",
+			p:    &Provider{Project: "my-project", Zone: "us-east1-b", Alias: "east"},
+			want: `{
+  "google": {
+    "project": "my-project",
+    "zone": "us-east1-b",
+    "alias": "east"
+  }
+}`,
+		},
+		{
+			name: "beta",
+			p:    &Provider{Name: "google-beta", Project: "my-project"},
+			want: `{
+  "google-beta": {
+    "project": "my-project"
+  }
+}`,
+		},
+		{
+			name: "extra_properties",
+			p:    &Provider{Project: "my-project", Properties: map[string]interface{}{"request_timeout": "60s"}},
+			want: `{
+  "google": {
+    "project": "my-project",
+    "request_timeout": "60s"
+  }
+}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.p.Init(); err != nil {
+				t.Fatalf("p.Init = %v", err)
+			}
+			checkJSON(t, tc.p, tc.want)
+		})
+	}
+}
+
+func TestProviderInitNoProject(t *testing.T) {
+	p := &Provider{Name: "google-beta"}
+	if err := p.Init(); err == nil {
+		t.Error("p.Init = nil, want error")
+	}
+}
+
+func TestCheckProviderAliases(t *testing.T) {
+	cases := []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{
+			name: "default_providers",
+			config: `{
+  "provider": [{"google": {"project": "my-project"}}],
+  "resource": [{"google_service_account": {"foo": {"account_id": "foo"}}}, {"google_redis_instance": {"bar": {"provider": "google-beta"}}}]
+}`,
+		},
+		{
+			name: "declared_alias",
+			config: `{
+  "provider": [{"google": {"project": "my-project"}}, {"google": {"project": "project-a", "alias": "project_a"}}],
+  "resource": [{"google_service_account": {"foo": {"account_id": "foo", "provider": "google.project_a"}}}],
+  "data": [{"google_project": {"project": {"project_id": "project-a", "provider": "google.project_a"}}}]
+}`,
+		},
+		{
+			name: "map_blocks",
+			config: `{
+  "provider": {"google": [{"alias": "project_a"}]},
+  "resource": {"google_service_account": {"foo": {"provider": "google.project_a"}}}
+}`,
+		},
+		{
+			name: "undeclared_alias",
+			config: `{
+  "provider": [{"google": {"project": "project-a", "alias": "project_a"}}],
+  "resource": [{"google_service_account": {"foo": {"account_id": "foo", "provider": "google.project_b"}}}]
+}`,
+			wantErr: true,
+		},
+		{
+			name: "alias_of_other_provider",
+			config: `{
+  "provider": [{"google": {"project": "project-a", "alias": "project_a"}}],
+  "resource": [{"google_service_account": {"foo": {"account_id": "foo", "provider": "google-beta.project_a"}}}]
+}`,
+			wantErr: true,
+		},
+		{
+			name: "undeclared_data_alias",
+			config: `{
+  "data": [{"google_project": {"project": {"project_id": "project-a", "provider": "google.project_a"}}}]
+}`,
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := CheckProviderAliases([]byte(tc.config)); (err != nil) != tc.wantErr {
+				t.Errorf("CheckProviderAliases = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestBackend(t *testing.T) {
+	conf := NewConfig()
+	conf.Terraform.Backend = &Backend{Bucket: "my-project-state", Prefix: "resources"}
+	if err := conf.Terraform.Backend.Validate(); err != nil {
+		t.Fatalf("b.Validate = %v", err)
+	}
+
+	// The backend is nested in the top level terraform block, not under resources.
+	want := `{
+  "terraform": {
+    "required_version": ">= 0.12.0",
+    "backend": {
+      "gcs": {
+        "bucket": "my-project-state",
+        "prefix": "resources"
+      }
+    }
+  }
+}`
+	checkJSON(t, conf, want)
+}
+
+func TestBackendEmptyBucket(t *testing.T) {
+	b := &Backend{Prefix: "resources"}
+	if err := b.Validate(); err == nil {
+		t.Error("b.Validate = nil, want error")
+	}
+
+	conf := NewConfig()
+	conf.Terraform.Backend = b
+	if err := Apply(conf, "", nil, &testRunner{}); err == nil {
+		t.Error("Apply = nil, want error")
+	}
+}
+
+func TestRequiredProviders(t *testing.T) {
+	cases := []struct {
+		name string
+		rs   RequiredProviders
+		want string
+	}{
+		{
+			name: "versions",
+			rs: RequiredProviders{
+				{Name: "google", Version: ">= 4.0, < 5.0"},
+				{Name: "google-beta", Version: ">= 4.0.0-beta.1, ~> 4.5"},
+			},
+			want: `{
+  "terraform": {
+    "required_version": ">= 0.12.0",
+    "required_providers": {
+      "google": ">= 4.0, < 5.0",
+      "google-beta": ">= 4.0.0-beta.1, ~> 4.5"
+    }
+  }
+}`,
+		},
+		{
+			name: "source",
+			rs: RequiredProviders{
+				{Name: "google", Version: ">= 4.0, < 5.0"},
+				{Name: "google-beta", Source: "hashicorp/google-beta", Version: "~> 4.5"},
+			},
+			want: `{
+  "terraform": {
+    "required_version": ">= 0.13.0",
+    "required_providers": {
+      "google": {
+        "version": ">= 4.0, < 5.0"
+      },
+      "google-beta": {
+        "source": "hashicorp/google-beta",
+        "version": "~> 4.5"
+      }
+    }
+  }
+}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			conf := NewConfig()
+			conf.Terraform.RequiredProviders = tc.rs
+			if err := conf.Terraform.RequiredProviders.Init(); err != nil {
+				t.Fatalf("RequiredProviders.Init = %v", err)
+			}
+			checkJSON(t, conf, tc.want)
+		})
+	}
+}
+
+func TestRequiredProvidersSourceKeepsRequiredVersion(t *testing.T) {
+	conf := NewConfig()
+	conf.Terraform.RequiredVersion = ">= 1.0"
+	conf.Terraform.RequiredProviders = RequiredProviders{{Name: "google", Source: "hashicorp/google"}}
+	want := `{
+  "terraform": {
+    "required_version": ">= 1.0",
+    "required_providers": {
+      "google": {
+        "source": "hashicorp/google"
+      }
+    }
+  }
+}`
+	checkJSON(t, conf, want)
+}
+
+func TestTerraformRequireVersion(t *testing.T) {
+	cases := []struct {
+		name            string
+		requiredVersion string
+		want            string
+	}{
+		{
+			name: "empty",
+			want: ">= 1.5.0",
+		},
+		{
+			name:            "lower",
+			requiredVersion: ">= 0.12.0",
+			want:            ">= 1.5.0",
+		},
+		{
+			name:            "lower_with_upper_bound",
+			requiredVersion: "> 0.12.0, < 2.0",
+			want:            "< 2.0, >= 1.5.0",
+		},
+		{
+			name:            "lower_pessimistic",
+			requiredVersion: "~> 1.2",
+			want:            "~> 1.2, >= 1.5.0",
+		},
+		{
+			name:            "lower_pre_release",
+			requiredVersion: ">= 1.5.0-beta1",
+			want:            ">= 1.5.0",
+		},
+		{
+			name:            "equal",
+			requiredVersion: ">= 1.5",
+			want:            ">= 1.5",
+		},
+		{
+			name:            "stricter",
+			requiredVersion: ">= 1.6.0, < 2.0",
+			want:            ">= 1.6.0, < 2.0",
+		},
+		{
+			name:            "stricter_pessimistic",
+			requiredVersion: "~> 1.7",
+			want:            "~> 1.7",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tf := &Terraform{RequiredVersion: tc.requiredVersion}
+			tf.RequireVersion("1.5.0")
+			if tf.RequiredVersion != tc.want {
+				t.Errorf("RequiredVersion = %q, want %q", tf.RequiredVersion, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidateVersionConstraint(t *testing.T) {
+	for _, v := range []string{"", "4.0", "= 4.0.1", "!= 4.1", "~> 4.5.1", ">= 4.0.0-beta.1", "< 5.0.0-rc1+build.2", ">= 4.0.0beta1", "v4.0.0.1"} {
+		if err := validateVersionConstraint(v); err != nil {
+			t.Errorf("validateVersionConstraint(%q) = %v, want nil", v, err)
+		}
+	}
+}
+
+func TestRequiredProvidersInitErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		rs   RequiredProviders
+	}{
+		{
+			name: "malformed_constraint",
+			rs:   RequiredProviders{{Name: "google", Version: ">= 4.0, <"}},
+		},
+		{
+			name: "unknown_operator",
+			rs:   RequiredProviders{{Name: "google", Version: "=> 4.0"}},
+		},
+		{
+			name: "unsupported_provider",
+			rs:   RequiredProviders{{Name: "aws", Version: ">= 4.0"}},
+		},
+		{
+			name: "duplicate_provider",
+			rs:   RequiredProviders{{Name: "google"}, {Name: "google", Version: ">= 4.0"}},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.rs.Init(); err == nil {
+				t.Error("RequiredProviders.Init = nil, want error")
+			}
+		})
+	}
+
+	conf := NewConfig()
+	conf.Terraform.RequiredProviders = RequiredProviders{{Name: "google", Version: "latest"}}
+	if err := Apply(conf, "", nil, &testRunner{}); err == nil {
+		t.Error("Apply = nil, want error")
+	}
+}
+
+func TestOutput(t *testing.T) {
+	sa := &tfconfig.ServiceAccount{AccountID: "foo-account"}
+	cases := []struct {
+		name string
+		o    *Output
+		want string
+	}{
+		{
+			name: "non_sensitive",
+			o:    &Output{Name: "sa_email", Value: sa.Ref("email"), Description: "Email of the foo service account."},
+			want: `{
+  "sa_email": {
+    "value": "${google_service_account.foo-account.email}",
+    "description": "Email of the foo service account."
+  }
+}`,
+		},
+		{
+			name: "sensitive",
+			o:    &Output{Name: "sa_key", Value: "${google_service_account_key.foo-account.private_key}", Sensitive: true},
+			want: `{
+  "sa_key": {
+    "value": "${google_service_account_key.foo-account.private_key}",
+    "sensitive": true
+  }
+}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			checkJSON(t, tc.o, tc.want)
+		})
+	}
+}
+
+func TestVariable(t *testing.T) {
+	cases := []struct {
+		name string
+		v    *Variable
+		want string
+	}{
+		{
+			name: "required",
+			v:    &Variable{Name: "region", Type: "string", Description: "Region to deploy to."},
+			want: `{
+  "region": {
+    "type": "string",
+    "description": "Region to deploy to."
+  }
+}`,
+		},
+		{
+			name: "default",
+			v:    &Variable{Name: "allowed_zones", Type: "list(string)", Default: []string{"us-central1-a", "us-central1-b"}},
+			want: `{
+  "allowed_zones": {
+    "type": "list(string)",
+    "default": ["us-central1-a", "us-central1-b"]
+  }
+}`,
+		},
+		{
+			name: "sensitive",
+			v:    &Variable{Name: "db_password", Type: "string", Sensitive: true},
+			want: `{
+  "db_password": {
+    "type": "string",
+    "sensitive": true
+  }
+}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.v.Validate(); err != nil {
+				t.Fatalf("v.Validate = %v", err)
+			}
+			checkJSON(t, tc.v, tc.want)
+		})
+	}
+}
+
+func TestVariableInvalidName(t *testing.T) {
+	for _, name := range []string{"", "1region", "my.region", "my region", "count"} {
+		t.Run(name, func(t *testing.T) {
+			v := &Variable{Name: name, Type: "string"}
+			if err := v.Validate(); err == nil {
+				t.Errorf("v.Validate = nil, want error")
+			}
+		})
+	}
+}
+
+func TestImportBlock(t *testing.T) {
+	conf := NewConfig()
+	conf.Imports = []*Import{{
+		Address: "google_service_account.foo-account",
+		ID:      "projects/my-project/serviceAccounts/foo-account@my-project.iam.gserviceaccount.com",
+	}}
+	want := `{
+  "terraform": {"required_version": ">= 0.12.0"},
+  "import": [{
+    "to": "google_service_account.foo-account",
+    "id": "projects/my-project/serviceAccounts/foo-account@my-project.iam.gserviceaccount.com"
+  }]
+}`
+	checkJSON(t, conf, want)
+}
@@ -0,0 +1,176 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/GoogleCloudPlatform/healthcare/deploy/config/tfconfig"
+)
+
+// Deployment aggregates resources with the providers, backend, variables and outputs of a single terraform config.
+// It marshals to a complete config document where resources and data sources are grouped by type, then ID,
+// e.g. {"resource": {"google_service_account": {"foo": {...}}}}.
+type Deployment struct {
+	Providers         []*Provider
+	RequiredProviders RequiredProviders
+	Backend           *Backend
+	Variables         []*Variable
+	Outputs           []*Output
+
+	// Resources are the top level resources of the deployment.
+	// Their dependent resources (e.g. IAM members of a bucket) are added when marshalling.
+	Resources []tfconfig.Resource
+
+	// CommonLabels are merged into the labels of all resources that support labels when initializing the deployment.
+	CommonLabels map[string]string
+}
+
+type depender interface {
+	DependentResources() []tfconfig.Resource
+}
+
+// Init initializes and validates all parts of the deployment.
+// Each resource is initialized with the given project ID, then all resources are validated through tfconfig.ValidateResources.
+// Resource errors are aggregated in a tfconfig.MultiError so all of them are reported at once.
+func (d *Deployment) Init(projectID string) error {
+	for _, p := range d.Providers {
+		if err := p.Init(); err != nil {
+			return fmt.Errorf("failed to init provider %q: %v", p.Name, err)
+		}
+	}
+	if err := d.RequiredProviders.Init(); err != nil {
+		return fmt.Errorf("invalid terraform required providers: %v", err)
+	}
+	if d.Backend != nil {
+		if err := d.Backend.Validate(); err != nil {
+			return fmt.Errorf("invalid terraform backend: %v", err)
+		}
+	}
+	for _, v := range d.Variables {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("invalid terraform variable: %v", err)
+		}
+	}
+	tfconfig.MergeCommonLabels(d.Resources, d.CommonLabels)
+	// Resources are only validated once all of them are initialized, as validation assumes initialized resources.
+	errs := new(tfconfig.MultiError)
+	for _, r := range d.Resources {
+		if err := r.Init(projectID); err != nil {
+			errs.Add(r.ResourceType(), r.ID(), fmt.Errorf("failed to init: %w", err))
+		}
+	}
+	if err := errs.ErrOrNil(); err != nil {
+		return err
+	}
+	return tfconfig.ValidateResources(d.Resources)
+}
+
+// MarshalJSON marshals the deployment to a complete terraform config document.
+// Providers, variables and outputs keep the list form used by Config.
+func (d *Deployment) MarshalJSON() ([]byte, error) {
+	c := NewConfig()
+	c.Terraform.RequiredProviders = d.RequiredProviders
+	c.Terraform.Backend = d.Backend
+	c.Providers = d.Providers
+	c.Variables = d.Variables
+	c.Outputs = d.Outputs
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	doc := make(map[string]interface{})
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config to map: %v", err)
+	}
+
+	resources := make(map[string]map[string]tfconfig.Resource)
+	data := make(map[string]map[string]tfconfig.Resource)
+	if err := groupResources(resources, data, d.Resources); err != nil {
+		return nil, err
+	}
+	if len(resources) > 0 {
+		doc["resource"] = resources
+	}
+	if len(data) > 0 {
+		doc["data"] = data
+	}
+	return json.Marshal(doc)
+}
+
+// groupResources adds the given resources and their dependent resources to the given type to ID maps.
+// Data resources are added to data.
+func groupResources(resources, data map[string]map[string]tfconfig.Resource, rs []tfconfig.Resource) error {
+	for _, r := range rs {
+		group, kind := resources, "resource"
+		if _, ok := r.(tfconfig.DataResource); ok {
+			group, kind = data, "data"
+		}
+		byID, ok := group[r.ResourceType()]
+		if !ok {
+			byID = make(map[string]tfconfig.Resource)
+			group[r.ResourceType()] = byID
+		}
+		if _, ok := byID[r.ID()]; ok {
+			return fmt.Errorf("found duplicate %s %s.%s", kind, r.ResourceType(), r.ID())
+		}
+		byID[r.ID()] = r
+
+		if dr, ok := r.(depender); ok {
+			if err := groupResources(resources, data, dr.DependentResources()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ResourceSummary describes a resource of a deployment without its full config.
+type ResourceSummary struct {
+	ResourceType string
+	ID           string
+
+	// Dependencies are the depends_on entries of the resource.
+	Dependencies []string
+}
+
+// Summary returns a summary of every resource of the deployment, including dependent resources, sorted by type then ID.
+// It is meant to preview what a deployment creates and should be called after Init.
+func (d *Deployment) Summary() []ResourceSummary {
+	var summaries []ResourceSummary
+	var add func([]tfconfig.Resource)
+	add = func(rs []tfconfig.Resource) {
+		for _, r := range rs {
+			summaries = append(summaries, ResourceSummary{
+				ResourceType: r.ResourceType(),
+				ID:           r.ID(),
+				Dependencies: tfconfig.DependsOn(r),
+			})
+			if dr, ok := r.(depender); ok {
+				add(dr.DependentResources())
+			}
+		}
+	}
+	add(d.Resources)
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if summaries[i].ResourceType != summaries[j].ResourceType {
+			return summaries[i].ResourceType < summaries[j].ResourceType
+		}
+		return summaries[i].ID < summaries[j].ID
+	})
+	return summaries
+}
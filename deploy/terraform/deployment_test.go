@@ -0,0 +1,265 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraform
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/healthcare/deploy/config/tfconfig"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDeployment(t *testing.T) {
+	d := &Deployment{
+		Providers: []*Provider{{Project: "my-project"}},
+		Backend:   &Backend{Bucket: "my-state", Prefix: "resources"},
+		Outputs:   []*Output{{Name: "email", Value: "${google_service_account.foo-account.email}"}},
+		Resources: []tfconfig.Resource{
+			&tfconfig.ServiceAccount{AccountID: "foo-account", DisplayName: "Foo"},
+			&tfconfig.ProjectIAMMembers{
+				Members: []*tfconfig.ProjectIAMMember{
+					{Role: "roles/viewer", Member: "serviceAccount:foo-account@my-project.iam.gserviceaccount.com"},
+				},
+				DependsOn: []string{"google_service_account.foo-account"},
+			},
+		},
+	}
+	if err := d.Init("my-project"); err != nil {
+		t.Fatalf("d.Init = %v", err)
+	}
+
+	want := `{
+  "terraform": {
+    "required_version": ">= 0.12.0",
+    "backend": {
+      "gcs": {
+        "bucket": "my-state",
+        "prefix": "resources"
+      }
+    }
+  },
+  "provider": [{
+    "google": {
+      "project": "my-project"
+    }
+  }],
+  "output": [{
+    "email": {
+      "value": "${google_service_account.foo-account.email}"
+    }
+  }],
+  "resource": {
+    "google_service_account": {
+      "foo-account": {
+        "account_id": "foo-account",
+        "project": "my-project",
+        "display_name": "Foo"
+      }
+    },
+    "google_project_iam_member": {
+      "project": {
+        "for_each": {
+          "roles/viewer serviceAccount:foo-account@my-project.iam.gserviceaccount.com": {
+            "role": "roles/viewer",
+            "member": "serviceAccount:foo-account@my-project.iam.gserviceaccount.com"
+          }
+        },
+        "project": "my-project",
+        "role": "${each.value.role}",
+        "member": "${each.value.member}",
+        "depends_on": ["google_service_account.foo-account"]
+      }
+    }
+  }
+}`
+
+	b, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("json.Marshal = %v", err)
+	}
+	var got, wantMap interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("json.Unmarshal = %v", err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantMap); err != nil {
+		t.Fatalf("json.Unmarshal = %v", err)
+	}
+	if diff := cmp.Diff(got, wantMap); diff != "" {
+		t.Errorf("deployment differs (-got, +want):\n%v", diff)
+	}
+}
+
+func TestDeploymentInitErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		rs   []tfconfig.Resource
+	}{
+		{
+			name: "init",
+			rs:   []tfconfig.Resource{&tfconfig.ServiceAccount{AccountID: "foo"}},
+		},
+		{
+			name: "validate",
+			rs: []tfconfig.Resource{&tfconfig.ProjectIAMMembers{
+				Members: []*tfconfig.ProjectIAMMember{{Member: "group:foo@my-domain.com"}},
+			}},
+		},
+		{
+			name: "duplicate_ids",
+			rs: []tfconfig.Resource{
+				&tfconfig.ServiceAccount{AccountID: "foo-account"},
+				&tfconfig.ServiceAccount{AccountID: "foo-account", DisplayName: "Foo"},
+			},
+		},
+		{
+			name: "service_account_iam_binding_conflict",
+			rs: []tfconfig.Resource{
+				&tfconfig.ServiceAccountIAMBinding{
+					ServiceAccountID: "${google_service_account.foo-account.name}",
+					Role:             "roles/iam.serviceAccountUser",
+					Members:          []string{"group:users@my-domain.com"},
+				},
+				&tfconfig.ServiceAccountIAMMembers{Members: []*tfconfig.ServiceAccountIAMMember{{
+					ServiceAccountID: "projects/my-project/serviceAccounts/foo-account@my-project.iam.gserviceaccount.com",
+					Role:             "roles/iam.serviceAccountUser",
+					Member:           "group:admins@my-domain.com",
+				}}},
+			},
+		},
+		{
+			name: "malformed_depends_on",
+			rs: []tfconfig.Resource{&tfconfig.ProjectIAMMembers{
+				DependsOn: []string{"foo-account"},
+			}},
+		},
+		{
+			name: "dangling_depends_on",
+			rs: []tfconfig.Resource{&tfconfig.ProjectIAMMembers{
+				DependsOn: []string{"google_service_account.foo-account"},
+			}},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := &Deployment{Resources: tc.rs}
+			if err := d.Init("my-project"); err == nil {
+				t.Error("d.Init = nil, want error")
+			}
+		})
+	}
+}
+
+func TestDeploymentSummary(t *testing.T) {
+	d := &Deployment{Resources: []tfconfig.Resource{
+		&tfconfig.StorageBucket{
+			Name:       "foo-bucket",
+			Location:   "US",
+			IAMMembers: []*tfconfig.StorageIAMMember{{Role: "roles/storage.objectViewer", Member: "group:readers@my-domain.com"}},
+		},
+		&tfconfig.ServiceAccount{AccountID: "foo-account"},
+		&tfconfig.ProjectIAMMembers{
+			Members:   []*tfconfig.ProjectIAMMember{{Role: "roles/viewer", Member: "group:viewers@my-domain.com"}},
+			DependsOn: []string{"google_service_account.foo-account", "google_storage_bucket.foo-bucket"},
+		},
+		&tfconfig.ServiceAccount{AccountID: "bar-account"},
+	}}
+	if err := d.Init("my-project"); err != nil {
+		t.Fatalf("d.Init = %v", err)
+	}
+
+	want := []ResourceSummary{
+		{ResourceType: "google_project_iam_member", ID: "project", Dependencies: []string{"google_service_account.foo-account", "google_storage_bucket.foo-bucket"}},
+		{ResourceType: "google_service_account", ID: "bar-account"},
+		{ResourceType: "google_service_account", ID: "foo-account"},
+		{ResourceType: "google_storage_bucket", ID: "foo-bucket"},
+		{ResourceType: "google_storage_bucket_iam_member", ID: "foo-bucket"},
+	}
+	if diff := cmp.Diff(want, d.Summary()); diff != "" {
+		t.Errorf("d.Summary() differs (-want +got):\n%v", diff)
+	}
+}
+
+func TestDeploymentInitAggregatesErrors(t *testing.T) {
+	rs := []tfconfig.Resource{
+		&tfconfig.ServiceAccount{AccountID: "bar-account", Description: strings.Repeat("a", 257)},
+		&tfconfig.ServiceAccount{AccountID: "baz-account"},
+		&tfconfig.ProjectIAMMembers{Members: []*tfconfig.ProjectIAMMember{{Member: "group:foo@my-domain.com"}}},
+	}
+	d := &Deployment{Resources: rs}
+	err := d.Init("my-project")
+	var merr *tfconfig.MultiError
+	if !errors.As(err, &merr) {
+		t.Fatalf("d.Init = %v, want MultiError", err)
+	}
+	if got := len(strings.Split(err.Error(), "\n")); got != 2 {
+		t.Errorf("d.Init = %v, want 2 lines", err)
+	}
+	for _, want := range []string{"google_service_account.bar-account", "google_project_iam_member.project"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("d.Init = %v, want error naming %q", err, want)
+		}
+	}
+}
+
+func TestDeploymentInitSkipsValidationOnInitErrors(t *testing.T) {
+	d := &Deployment{Resources: []tfconfig.Resource{
+		&tfconfig.ServiceAccount{AccountID: "foo"},
+		&tfconfig.ServiceAccount{AccountID: "b"},
+		&tfconfig.ServiceAccount{AccountID: "bar-account", Description: strings.Repeat("a", 257)},
+	}}
+	err := d.Init("my-project")
+	if err == nil {
+		t.Fatal("d.Init = nil, want error")
+	}
+	if got := len(strings.Split(err.Error(), "\n")); got != 2 {
+		t.Errorf("d.Init = %v, want 2 lines", err)
+	}
+	for _, want := range []string{"google_service_account.foo: failed to init", "google_service_account.b: failed to init"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("d.Init = %v, want error containing %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "bar-account") {
+		t.Errorf("d.Init = %v, want no validation error once init failed", err)
+	}
+}
+
+func TestDeploymentCommonLabels(t *testing.T) {
+	b := &tfconfig.StorageBucket{Name: "foo-bucket", Location: "US", Labels: map[string]string{"env": "prod"}}
+	d := &Deployment{
+		Resources:    []tfconfig.Resource{b},
+		CommonLabels: map[string]string{"env": "dev", "team": "data"},
+	}
+	if err := d.Init("my-project"); err != nil {
+		t.Fatalf("d.Init = %v", err)
+	}
+	want := map[string]string{"env": "prod", "team": "data"}
+	if diff := cmp.Diff(want, b.Labels); diff != "" {
+		t.Errorf("bucket labels differ (-want +got):\n%v", diff)
+	}
+}
+
+func TestDeploymentMarshalDuplicateIDs(t *testing.T) {
+	d := &Deployment{Resources: []tfconfig.Resource{
+		&tfconfig.ServiceAccount{AccountID: "foo-account", Project: "my-project"},
+		&tfconfig.ServiceAccount{AccountID: "foo-account", Project: "my-project"},
+	}}
+	if _, err := json.Marshal(d); err == nil {
+		t.Error("json.Marshal = nil, want error")
+	}
+}